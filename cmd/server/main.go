@@ -21,14 +21,19 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"path/filepath"
 
 	"michishirube/internal/config"
+	"michishirube/internal/handlers"
 	"michishirube/internal/logger"
+	"michishirube/internal/models"
 	"michishirube/internal/server"
+	"michishirube/internal/storage"
+	"michishirube/internal/storage/memory"
 	"michishirube/internal/storage/sqlite"
 )
 
@@ -69,6 +74,22 @@ func main() {
 
 	// Reconfigure logger with the actual log level from config
 	actualLogger := logger.NewLogger(cfg.GetSlogLevel())
+	if cfg.LogFile != "" {
+		fileLogger, closer, err := logger.NewFileLogger(cfg.LogFile, cfg.GetSlogLevel(),
+			logger.WithMaxSizeMB(cfg.LogMaxSizeMB),
+			logger.WithTee(os.Stdout),
+		)
+		if err != nil {
+			log.Error("Failed to open log file", "log_file", cfg.LogFile, "error", err)
+			os.Exit(1)
+		}
+		defer func() {
+			if err := closer.Close(); err != nil {
+				log.Error("Failed to close log file", "error", err)
+			}
+		}()
+		actualLogger = fileLogger
+	}
 	ctx = logger.WithLogger(ctx, actualLogger)
 	log = logger.FromContext(ctx)
 
@@ -76,15 +97,31 @@ func main() {
 	ctx = logger.WithFields(ctx, "port", cfg.Port, "db_path", cfg.DBPath, "log_level", cfg.LogLevel)
 	log.Info("Logger reconfigured with config level")
 
-	// Ensure database directory exists
-	if err := ensureDBDirectory(ctx, cfg.DBPath); err != nil {
-		log.Error("Failed to create database directory", "error", err)
+	priorities := make([]models.Priority, 0, len(cfg.PriorityOrder))
+	for _, priority := range cfg.PriorityOrder {
+		priorities = append(priorities, models.Priority(priority))
+	}
+	err = models.Configure(models.Limits{
+		NoJiraID:           cfg.NoJiraID,
+		JiraIDPattern:      cfg.JiraIDPattern,
+		MaxTitleLen:        cfg.MaxTitleLen,
+		MaxCommentLen:      cfg.MaxCommentLen,
+		NewTaskPriority:    models.Priority(cfg.DefaultPriority),
+		NewTaskStatus:      models.Status(cfg.DefaultStatus),
+		PriorityOrder:      priorities,
+		EnforceTransitions: cfg.EnforceTransitions,
+		TagColors:          cfg.TagColors,
+		AllowedURLSchemes:  cfg.AllowedLinkSchemes,
+	})
+	if err != nil {
+		log.Error("Failed to configure validation limits", "error", err)
 		os.Exit(1)
 	}
+	log.Debug("Validation limits configured", "no_jira_id", models.NoJiraID(), "jira_id_pattern", cfg.JiraIDPattern,
+		"link_schemes", models.AllowedURLSchemes(), "enforce_transitions", models.EnforceTransitions())
 
 	// Initialize storage
-	log.Info("Initializing storage", "db_path", cfg.DBPath)
-	storage, err := sqlite.New(cfg.DBPath)
+	storage, err := openStorage(ctx, cfg)
 	if err != nil {
 		log.Error("Failed to initialize storage", "error", err)
 		os.Exit(1)
@@ -99,7 +136,7 @@ func main() {
 	log.Info("Storage initialized successfully")
 
 	// Initialize and start server
-	srv := server.New(cfg, storage, log)
+	srv := server.New(cfg, storage, log, handlers.BuildInfo{Version: version, Commit: commit})
 	log.Info("Starting HTTP server", "port", cfg.Port)
 
 	if err := srv.Start(); err != nil {
@@ -108,6 +145,36 @@ func main() {
 	}
 }
 
+// openStorage builds the backend selected by storage_driver
+func openStorage(ctx context.Context, cfg *config.Config) (storage.Storage, error) {
+	log := logger.FromContext(ctx)
+
+	if cfg.StorageDriver == config.StorageDriverMemory {
+		log.Warn("Using in-memory storage; data will be lost on shutdown")
+		if cfg.DBEncryptionKey != "" {
+			log.Warn("db_encryption_key is ignored by in-memory storage")
+		}
+		return memory.New(), nil
+	}
+
+	// Ensure database directory exists
+	if err := ensureDBDirectory(ctx, cfg.DBPath); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	log.Info("Initializing storage", "db_path", cfg.DBPath, "encrypted", cfg.DBEncryptionKey != "")
+	return sqlite.New(cfg.DBPath,
+		sqlite.WithJournalMode(cfg.SQLiteJournalMode),
+		sqlite.WithBusyTimeout(cfg.SQLiteBusyTimeout),
+		sqlite.WithSynchronous(cfg.SQLiteSynchronous),
+		sqlite.WithMaxOpenConns(cfg.SQLiteMaxOpenConns),
+		sqlite.WithMaxIdleConns(cfg.SQLiteMaxIdleConns),
+		sqlite.WithConnMaxLifetime(cfg.SQLiteConnMaxLifetime),
+		sqlite.WithRetryAttempts(cfg.SQLiteRetryAttempts),
+		sqlite.WithEncryptionKey(cfg.DBEncryptionKey),
+	)
+}
+
 func ensureDBDirectory(ctx context.Context, dbPath string) error {
 	log := logger.FromContext(ctx)
 
@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"michishirube/internal/logger"
+	"michishirube/internal/models"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,12 +21,106 @@ var (
 	ErrDBPathEmpty   = errors.New("db_path cannot be empty")
 	ErrConfigParse   = errors.New("failed to parse config.yaml")
 	ErrInvalidLogLevel = errors.New("invalid log level")
+	ErrInvalidPort     = errors.New("port must be a number between 1 and 65535")
+)
+
+const (
+	defaultWALCheckpointInterval = 5 * time.Minute
+	defaultPageSize              = 50
+	defaultMaxPageSize           = 200
+	defaultMaxBodyBytes          = 1 << 20
+	defaultSQLiteJournalMode     = "WAL"
+	defaultSQLiteBusyTimeout     = 5 * time.Second
+	defaultSQLiteSynchronous     = "NORMAL"
+	defaultSQLiteMaxOpenConns    = 4
+	defaultSQLiteMaxIdleConns    = 4
+	defaultSQLiteRetryAttempts   = 5
+	defaultStorageDriver         = StorageDriverSQLite
+	defaultReadTimeout           = 30 * time.Second
+	defaultWriteTimeout          = 30 * time.Second
+	defaultIdleTimeout           = 120 * time.Second
+	defaultShutdownTimeout       = 30 * time.Second
+	defaultMaxTitleLen           = 500
+	defaultMaxCommentLen         = 10000
+	defaultRateLimitBurst        = 20
+	defaultReportSchedule        = "08:00"
+)
+
+// Storage drivers accepted by storage_driver
+const (
+	StorageDriverSQLite = "sqlite"
+	StorageDriverMemory = "memory" // Nothing is persisted; for demos and tests
 )
 
 type Config struct {
-	Port     string `yaml:"port"`
-	DBPath   string `yaml:"db_path"`
-	LogLevel string `yaml:"log_level"`
+	Port         string `yaml:"port"`
+	BindAddress  string `yaml:"bind_address"` // Host or IP to listen on, e.g. 127.0.0.1; empty listens on all interfaces
+	DBPath       string `yaml:"db_path"`
+	LogLevel     string `yaml:"log_level"`
+	LogFile      string `yaml:"log_file"`        // Optional path of a rotated log file, in addition to stdout
+	LogMaxSizeMB int    `yaml:"log_max_size_mb"` // Size at which the log file is rotated
+
+	ReadTimeout  time.Duration `yaml:"read_timeout"`  // Longest time to read a whole request, body included (0 means none)
+	WriteTimeout time.Duration `yaml:"write_timeout"` // Longest time to write a response (0 means none; /api/events ignores it)
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`  // How long a keep-alive connection may wait for its next request
+
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // How long in-flight requests may run after a shutdown signal before connections are closed
+
+	StorageDriver string `yaml:"storage_driver"` // sqlite (default) or memory; db_path and sqlite_* only apply to sqlite
+
+	DBEncryptionKey string `yaml:"db_encryption_key"` // SQLCipher key for the database file; needs a SQLCipher build of the SQLite driver
+
+	WALCheckpointInterval time.Duration `yaml:"wal_checkpoint_interval"` // How often to truncate the WAL file (0 disables)
+
+	ArchiveRetentionDays int `yaml:"archive_retention_days"` // Archived tasks untouched for this many days are purged daily (0 keeps them forever)
+
+	ReportOutputPath string `yaml:"report_output_path"` // File the Markdown status report is written to on report_schedule; empty disables it
+	ReportSchedule   string `yaml:"report_schedule"`    // When to write the report: a daily local time such as 08:00, or an interval such as 1h
+
+	SQLiteJournalMode string        `yaml:"sqlite_journal_mode"` // PRAGMA journal_mode; WAL lets reads run alongside a write
+	SQLiteBusyTimeout time.Duration `yaml:"sqlite_busy_timeout"` // How long to wait on a locked database before failing
+	SQLiteSynchronous string        `yaml:"sqlite_synchronous"`  // PRAGMA synchronous; NORMAL is durable enough with WAL
+
+	SQLiteMaxOpenConns    int           `yaml:"sqlite_max_open_conns"`    // Connection pool size; SQLite only ever has one writer
+	SQLiteMaxIdleConns    int           `yaml:"sqlite_max_idle_conns"`    // Connections kept open while idle
+	SQLiteConnMaxLifetime time.Duration `yaml:"sqlite_conn_max_lifetime"` // How long a connection is reused (0 means forever)
+	SQLiteRetryAttempts   int           `yaml:"sqlite_retry_attempts"`    // Tries per write when the database stays locked past the busy timeout
+
+	TLSCertFile string `yaml:"tls_cert_file"` // PEM certificate; HTTPS is served when both cert and key are set
+	TLSKeyFile  string `yaml:"tls_key_file"`  // PEM private key
+
+	AllowedLinkSchemes []string `yaml:"allowed_link_schemes"` // URL schemes accepted for links (defaults to http, https, slack)
+
+	JiraIDPattern string `yaml:"jira_id_pattern"` // Regex Jira IDs must match in full, e.g. PROJ-[0-9]+ (empty accepts any ID)
+	NoJiraID      string `yaml:"no_jira_id"`      // Placeholder Jira ID for tasks without a ticket (defaults to NO-JIRA)
+
+	MaxTitleLen   int `yaml:"max_title_len"`   // Longest task title accepted, in characters
+	MaxCommentLen int `yaml:"max_comment_len"` // Longest comment accepted, in characters
+
+	DefaultPriority string `yaml:"default_priority"` // Priority of new tasks that don't set one (defaults to normal)
+	DefaultStatus   string `yaml:"default_status"`   // Status of new tasks that don't set one (defaults to new; archived and blocked are not allowed)
+
+	PriorityOrder []string `yaml:"priority_order"` // Priorities from most to least urgent, for sorting (defaults to critical, high, normal, minor)
+
+	TagColors map[string]string `yaml:"tag_colors"` // #rrggbb colors for specific tags; other tags get a color derived from their name
+
+	EnforceTransitions bool `yaml:"enforce_transitions"` // Reject status changes outside the workflow (e.g. archived to in_progress) unless forced
+
+	APIKeys []string `yaml:"api_keys"` // Keys accepted for mutating /api/ requests; empty disables auth
+
+	RateLimitRPS   float64 `yaml:"rate_limit_rps"`   // Requests per second each client IP may sustain; 0 disables rate limiting
+	RateLimitBurst int     `yaml:"rate_limit_burst"` // Requests a client IP may make in a burst before rate_limit_rps applies
+	TrustProxy     bool    `yaml:"trust_proxy"`      // Take the client IP from X-Forwarded-For; only enable behind a proxy that sets it
+
+	APIOnly bool `yaml:"api_only"` // Serve only /api/, /health and /ready; the web UI and its templates are skipped
+
+	EventsEnabled bool `yaml:"events_enabled"` // Serve the /api/events change stream
+
+	DefaultPageSize int `yaml:"default_page_size"` // Task list limit when the request gives none
+	MaxPageSize     int `yaml:"max_page_size"`     // Largest task list limit a request may ask for
+
+	MaxBodyBytes int64 `yaml:"max_body_bytes"` // Largest JSON body accepted by create/update endpoints
+	StrictJSON   bool  `yaml:"strict_json"`    // Reject create/update bodies with unknown fields instead of ignoring them
 }
 
 func Load(ctx context.Context) (*Config, error) {
@@ -29,9 +128,44 @@ func Load(ctx context.Context) (*Config, error) {
 	
 	// Default values
 	config := &Config{
-		Port:     "8080",
-		DBPath:   "michishirube.db",
-		LogLevel: "info",
+		Port:         "8080",
+		DBPath:       "michishirube.db",
+		LogLevel:     "info",
+		LogMaxSizeMB: logger.DefaultLogMaxSizeMB,
+
+		ReadTimeout:  defaultReadTimeout,
+		WriteTimeout: defaultWriteTimeout,
+		IdleTimeout:  defaultIdleTimeout,
+
+		ShutdownTimeout: defaultShutdownTimeout,
+
+		StorageDriver: defaultStorageDriver,
+
+		WALCheckpointInterval: defaultWALCheckpointInterval,
+
+		SQLiteJournalMode: defaultSQLiteJournalMode,
+		SQLiteBusyTimeout: defaultSQLiteBusyTimeout,
+		SQLiteSynchronous: defaultSQLiteSynchronous,
+
+		SQLiteMaxOpenConns: defaultSQLiteMaxOpenConns,
+		SQLiteMaxIdleConns: defaultSQLiteMaxIdleConns,
+
+		SQLiteRetryAttempts: defaultSQLiteRetryAttempts,
+
+		DefaultPageSize: defaultPageSize,
+		MaxPageSize:     defaultMaxPageSize,
+
+		MaxBodyBytes: defaultMaxBodyBytes,
+
+		RateLimitBurst: defaultRateLimitBurst,
+
+		ReportSchedule: defaultReportSchedule,
+
+		MaxTitleLen:   defaultMaxTitleLen,
+		MaxCommentLen: defaultMaxCommentLen,
+
+		DefaultPriority: string(models.DefaultPriority),
+		DefaultStatus:   string(models.DefaultStatus),
 	}
 
 	log.Info("Loading configuration with defaults", "port", config.Port, "db_path", config.DBPath, "log_level", config.LogLevel)
@@ -63,6 +197,11 @@ func Load(ctx context.Context) (*Config, error) {
 		}
 	}
 
+	if bindAddress := os.Getenv("BIND_ADDRESS"); bindAddress != "" {
+		log.Info("Overriding bind_address from environment", "bind_address", bindAddress)
+		config.BindAddress = bindAddress
+	}
+
 	if dbPath := os.Getenv("DB_PATH"); dbPath != "" {
 		if dbPath == "" {
 			log.Warn("Invalid DB_PATH from environment (empty), using default", "default", "michishirube.db")
@@ -81,6 +220,68 @@ func Load(ctx context.Context) (*Config, error) {
 		}
 	}
 
+	if driver := os.Getenv("STORAGE_DRIVER"); driver != "" {
+		log.Info("Overriding storage_driver from environment", "storage_driver", driver)
+		config.StorageDriver = driver
+	}
+
+	if logFile := os.Getenv("LOG_FILE"); logFile != "" {
+		log.Info("Overriding log_file from environment", "log_file", logFile)
+		config.LogFile = logFile
+	}
+
+	if certFile := os.Getenv("TLS_CERT_FILE"); certFile != "" {
+		log.Info("Overriding tls_cert_file from environment", "tls_cert_file", certFile)
+		config.TLSCertFile = certFile
+	}
+
+	if keyFile := os.Getenv("TLS_KEY_FILE"); keyFile != "" {
+		log.Info("Overriding tls_key_file from environment", "tls_key_file", keyFile)
+		config.TLSKeyFile = keyFile
+	}
+
+	if schemes := os.Getenv("ALLOWED_LINK_SCHEMES"); schemes != "" {
+		log.Info("Overriding allowed_link_schemes from environment", "allowed_link_schemes", schemes)
+		config.AllowedLinkSchemes = strings.Split(schemes, ",")
+	}
+
+	if retention := os.Getenv("ARCHIVE_RETENTION_DAYS"); retention != "" {
+		if days, err := strconv.Atoi(retention); err != nil {
+			log.Warn("Invalid ARCHIVE_RETENTION_DAYS from environment, ignoring", "invalid", retention)
+		} else {
+			log.Info("Overriding archive_retention_days from environment", "archive_retention_days", days)
+			config.ArchiveRetentionDays = days
+		}
+	}
+
+	if apiOnly := os.Getenv("API_ONLY"); apiOnly != "" {
+		if enabled, err := strconv.ParseBool(apiOnly); err != nil {
+			log.Warn("Invalid API_ONLY from environment, ignoring", "invalid", apiOnly)
+		} else {
+			log.Info("Overriding api_only from environment", "api_only", enabled)
+			config.APIOnly = enabled
+		}
+	}
+
+	if eventsEnabled := os.Getenv("EVENTS_ENABLED"); eventsEnabled != "" {
+		if enabled, err := strconv.ParseBool(eventsEnabled); err != nil {
+			log.Warn("Invalid EVENTS_ENABLED from environment, ignoring", "invalid", eventsEnabled)
+		} else {
+			log.Info("Overriding events_enabled from environment", "events_enabled", enabled)
+			config.EventsEnabled = enabled
+		}
+	}
+
+	if key := os.Getenv("DB_ENCRYPTION_KEY"); key != "" {
+		log.Info("Overriding db_encryption_key from environment")
+		config.DBEncryptionKey = key
+	}
+
+	if keys := os.Getenv("API_KEYS"); keys != "" {
+		log.Info("Overriding api_keys from environment", "count", len(strings.Split(keys, ",")))
+		config.APIKeys = strings.Split(keys, ",")
+	}
+
 	// Validate and fix configuration
 	config.validateAndFix(log)
 
@@ -92,6 +293,16 @@ func (c *Config) validateAndFix(log *slog.Logger) {
 	if c.Port == "" {
 		log.Warn("Invalid port configuration (empty), using default", "default", "8080")
 		c.Port = "8080"
+	} else if err := validatePort(c.Port); err != nil {
+		log.Warn("Invalid port configuration, using default", "invalid", c.Port, "error", err, "default", "8080")
+		c.Port = "8080"
+	}
+
+	// IPv6 addresses may be written in brackets as in a URL
+	c.BindAddress = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(c.BindAddress), "["), "]")
+	if c.BindAddress != "" && !isValidHost(c.BindAddress) {
+		log.Warn("Invalid bind_address configuration, listening on all interfaces", "invalid", c.BindAddress)
+		c.BindAddress = ""
 	}
 	
 	if c.DBPath == "" {
@@ -103,6 +314,242 @@ func (c *Config) validateAndFix(log *slog.Logger) {
 		log.Warn("Invalid log_level configuration, using default", "invalid", c.LogLevel, "default", "info")
 		c.LogLevel = "info"
 	}
+
+	if c.LogMaxSizeMB <= 0 {
+		log.Warn("Invalid log_max_size_mb configuration, using default", "invalid", c.LogMaxSizeMB, "default", logger.DefaultLogMaxSizeMB)
+		c.LogMaxSizeMB = logger.DefaultLogMaxSizeMB
+	}
+
+	if c.ReadTimeout < 0 {
+		log.Warn("Invalid read_timeout configuration, using default", "invalid", c.ReadTimeout, "default", defaultReadTimeout)
+		c.ReadTimeout = defaultReadTimeout
+	}
+
+	if c.WriteTimeout < 0 {
+		log.Warn("Invalid write_timeout configuration, using default", "invalid", c.WriteTimeout, "default", defaultWriteTimeout)
+		c.WriteTimeout = defaultWriteTimeout
+	}
+
+	if c.IdleTimeout < 0 {
+		log.Warn("Invalid idle_timeout configuration, using default", "invalid", c.IdleTimeout, "default", defaultIdleTimeout)
+		c.IdleTimeout = defaultIdleTimeout
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		log.Warn("Invalid shutdown_timeout configuration, using default", "invalid", c.ShutdownTimeout, "default", defaultShutdownTimeout)
+		c.ShutdownTimeout = defaultShutdownTimeout
+	}
+
+	if c.StorageDriver != StorageDriverSQLite && c.StorageDriver != StorageDriverMemory {
+		log.Warn("Invalid storage_driver configuration, using default", "invalid", c.StorageDriver, "default", defaultStorageDriver)
+		c.StorageDriver = defaultStorageDriver
+	}
+
+	if c.JiraIDPattern != "" {
+		if _, err := regexp.Compile(c.JiraIDPattern); err != nil {
+			log.Warn("Invalid jira_id_pattern configuration, accepting any Jira ID", "invalid", c.JiraIDPattern, "error", err)
+			c.JiraIDPattern = ""
+		}
+	}
+
+	if c.MaxTitleLen <= 0 {
+		log.Warn("Invalid max_title_len configuration, using default", "invalid", c.MaxTitleLen, "default", defaultMaxTitleLen)
+		c.MaxTitleLen = defaultMaxTitleLen
+	}
+
+	if c.MaxCommentLen <= 0 {
+		log.Warn("Invalid max_comment_len configuration, using default", "invalid", c.MaxCommentLen, "default", defaultMaxCommentLen)
+		c.MaxCommentLen = defaultMaxCommentLen
+	}
+
+	if !models.Priority(c.DefaultPriority).IsValid() {
+		log.Warn("Invalid default_priority configuration, using default", "invalid", c.DefaultPriority, "default", models.DefaultPriority)
+		c.DefaultPriority = string(models.DefaultPriority)
+	}
+
+	if status := models.Status(c.DefaultStatus); !status.IsValid() || status == models.Archived || status == models.Blocked {
+		log.Warn("Invalid default_status configuration, using default", "invalid", c.DefaultStatus, "default", models.DefaultStatus)
+		c.DefaultStatus = string(models.DefaultStatus)
+	}
+
+	if c.WALCheckpointInterval < 0 {
+		log.Warn("Invalid wal_checkpoint_interval configuration, using default", "invalid", c.WALCheckpointInterval, "default", defaultWALCheckpointInterval)
+		c.WALCheckpointInterval = defaultWALCheckpointInterval
+	}
+
+	if c.ArchiveRetentionDays < 0 {
+		log.Warn("Invalid archive_retention_days configuration, disabling purge", "invalid", c.ArchiveRetentionDays)
+		c.ArchiveRetentionDays = 0
+	}
+
+	if _, _, err := parseReportSchedule(c.ReportSchedule); err != nil {
+		log.Warn("Invalid report_schedule configuration, using default", "invalid", c.ReportSchedule, "error", err, "default", defaultReportSchedule)
+		c.ReportSchedule = defaultReportSchedule
+	}
+
+	if !isValidJournalMode(c.SQLiteJournalMode) {
+		log.Warn("Invalid sqlite_journal_mode configuration, using default", "invalid", c.SQLiteJournalMode, "default", defaultSQLiteJournalMode)
+		c.SQLiteJournalMode = defaultSQLiteJournalMode
+	}
+
+	if c.SQLiteBusyTimeout < 0 {
+		log.Warn("Invalid sqlite_busy_timeout configuration, using default", "invalid", c.SQLiteBusyTimeout, "default", defaultSQLiteBusyTimeout)
+		c.SQLiteBusyTimeout = defaultSQLiteBusyTimeout
+	}
+
+	if !isValidSynchronous(c.SQLiteSynchronous) {
+		log.Warn("Invalid sqlite_synchronous configuration, using default", "invalid", c.SQLiteSynchronous, "default", defaultSQLiteSynchronous)
+		c.SQLiteSynchronous = defaultSQLiteSynchronous
+	}
+
+	if c.SQLiteMaxOpenConns <= 0 {
+		log.Warn("Invalid sqlite_max_open_conns configuration, using default", "invalid", c.SQLiteMaxOpenConns, "default", defaultSQLiteMaxOpenConns)
+		c.SQLiteMaxOpenConns = defaultSQLiteMaxOpenConns
+	}
+
+	if c.SQLiteMaxIdleConns < 0 {
+		log.Warn("Invalid sqlite_max_idle_conns configuration, using default", "invalid", c.SQLiteMaxIdleConns, "default", defaultSQLiteMaxIdleConns)
+		c.SQLiteMaxIdleConns = defaultSQLiteMaxIdleConns
+	}
+
+	if c.SQLiteConnMaxLifetime < 0 {
+		log.Warn("Invalid sqlite_conn_max_lifetime configuration, using default", "invalid", c.SQLiteConnMaxLifetime, "default", 0)
+		c.SQLiteConnMaxLifetime = 0
+	}
+
+	if c.SQLiteRetryAttempts <= 0 {
+		log.Warn("Invalid sqlite_retry_attempts configuration, using default", "invalid", c.SQLiteRetryAttempts, "default", defaultSQLiteRetryAttempts)
+		c.SQLiteRetryAttempts = defaultSQLiteRetryAttempts
+	}
+
+	if c.MaxPageSize <= 0 {
+		log.Warn("Invalid max_page_size configuration, using default", "invalid", c.MaxPageSize, "default", defaultMaxPageSize)
+		c.MaxPageSize = defaultMaxPageSize
+	}
+
+	if c.DefaultPageSize <= 0 {
+		log.Warn("Invalid default_page_size configuration, using default", "invalid", c.DefaultPageSize, "default", defaultPageSize)
+		c.DefaultPageSize = defaultPageSize
+	}
+	if c.DefaultPageSize > c.MaxPageSize {
+		log.Warn("default_page_size exceeds max_page_size, capping", "default_page_size", c.DefaultPageSize, "max_page_size", c.MaxPageSize)
+		c.DefaultPageSize = c.MaxPageSize
+	}
+
+	if c.MaxBodyBytes <= 0 {
+		log.Warn("Invalid max_body_bytes configuration, using default", "invalid", c.MaxBodyBytes, "default", defaultMaxBodyBytes)
+		c.MaxBodyBytes = defaultMaxBodyBytes
+	}
+
+	keys := c.APIKeys[:0]
+	for _, key := range c.APIKeys {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	c.APIKeys = keys
+
+	if c.RateLimitRPS < 0 {
+		log.Warn("Invalid rate_limit_rps configuration, disabling rate limiting", "invalid", c.RateLimitRPS)
+		c.RateLimitRPS = 0
+	}
+	if c.RateLimitBurst <= 0 {
+		log.Warn("Invalid rate_limit_burst configuration, using default", "invalid", c.RateLimitBurst, "default", defaultRateLimitBurst)
+		c.RateLimitBurst = defaultRateLimitBurst
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		log.Warn("Both tls_cert_file and tls_key_file are required for HTTPS, serving plain HTTP",
+			"tls_cert_file", c.TLSCertFile, "tls_key_file", c.TLSKeyFile)
+	}
+}
+
+// AuthEnabled reports whether mutating API requests require an API key
+func (c *Config) AuthEnabled() bool {
+	return len(c.APIKeys) > 0
+}
+
+// RateLimitEnabled reports whether requests are rate limited per client IP
+func (c *Config) RateLimitEnabled() bool {
+	return c.RateLimitRPS > 0
+}
+
+// ListenAddr is the address the server listens on, host:port
+func (c *Config) ListenAddr() string {
+	return net.JoinHostPort(c.BindAddress, c.Port)
+}
+
+// ReportEnabled reports whether the status report is written to a file on a schedule
+func (c *Config) ReportEnabled() bool {
+	return c.ReportOutputPath != ""
+}
+
+// NextReportAt returns when the scheduled report is next due after now: the
+// next occurrence of the daily time, or now plus the interval
+func (c *Config) NextReportAt(now time.Time) time.Time {
+	clock, interval, err := parseReportSchedule(c.ReportSchedule)
+	if err != nil {
+		clock, interval, _ = parseReportSchedule(defaultReportSchedule)
+	}
+	if interval > 0 {
+		return now.Add(interval)
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// TLSEnabled reports whether both a TLS certificate and key are configured
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// parseReportSchedule accepts either a daily HH:MM time, returned as clock, or
+// a positive Go duration, returned as interval
+func parseReportSchedule(schedule string) (clock time.Time, interval time.Duration, err error) {
+	schedule = strings.TrimSpace(schedule)
+	if clock, err = time.Parse("15:04", schedule); err == nil {
+		return clock, 0, nil
+	}
+	if interval, err = time.ParseDuration(schedule); err != nil {
+		return time.Time{}, 0, errors.New("want a daily time such as 08:00 or an interval such as 1h")
+	}
+	if interval < time.Minute {
+		return time.Time{}, 0, errors.New("interval must be at least 1m")
+	}
+	return time.Time{}, interval, nil
+}
+
+func validatePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return ErrInvalidPort
+	}
+	return nil
+}
+
+// isValidHost accepts IP addresses and DNS host names such as localhost
+func isValidHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	if len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, ch := range label {
+			if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '-') {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 func isValidLogLevel(level string) bool {
@@ -114,6 +561,24 @@ func isValidLogLevel(level string) bool {
 	}
 }
 
+func isValidJournalMode(mode string) bool {
+	switch strings.ToUpper(mode) {
+	case "DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF":
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidSynchronous(level string) bool {
+	switch strings.ToUpper(level) {
+	case "OFF", "NORMAL", "FULL", "EXTRA":
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *Config) GetSlogLevel() slog.Level {
 	switch strings.ToLower(c.LogLevel) {
 	case "debug":
@@ -4,7 +4,9 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"michishirube/internal/logger"
 
@@ -43,6 +45,33 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			name: "non-numeric port",
+			config: Config{
+				Port:     "abc",
+				DBPath:   "test.db",
+				LogLevel: "info",
+			},
+			valid: false,
+		},
+		{
+			name: "port zero",
+			config: Config{
+				Port:     "0",
+				DBPath:   "test.db",
+				LogLevel: "info",
+			},
+			valid: false,
+		},
+		{
+			name: "port above range",
+			config: Config{
+				Port:     "65536",
+				DBPath:   "test.db",
+				LogLevel: "info",
+			},
+			valid: false,
+		},
 		{
 			name: "invalid log level",
 			config: Config{
@@ -61,11 +90,13 @@ func TestConfig_Validate(t *testing.T) {
 			if tt.valid {
 				// Config should remain valid
 				assert.NotEmpty(t, tt.config.Port)
+				assert.NoError(t, validatePort(tt.config.Port))
 				assert.NotEmpty(t, tt.config.DBPath)
 				assert.True(t, isValidLogLevel(tt.config.LogLevel))
 			} else {
 				// After validateAndFix, config should be valid (fixed with defaults)
 				assert.NotEmpty(t, tt.config.Port, "Port should be fixed with default")
+				assert.NoError(t, validatePort(tt.config.Port), "Port should be a valid TCP port")
 				assert.NotEmpty(t, tt.config.DBPath, "DBPath should be fixed with default")
 				assert.True(t, isValidLogLevel(tt.config.LogLevel), "LogLevel should be fixed with default")
 			}
@@ -139,6 +170,28 @@ func TestLoad_WithDefaults(t *testing.T) {
 	assert.Equal(t, "8080", config.Port)
 	assert.Equal(t, "michishirube.db", config.DBPath)
 	assert.Equal(t, "info", config.LogLevel)
+	assert.Empty(t, config.LogFile)
+	assert.Equal(t, logger.DefaultLogMaxSizeMB, config.LogMaxSizeMB)
+	assert.Equal(t, 30*time.Second, config.ReadTimeout)
+	assert.Equal(t, 30*time.Second, config.WriteTimeout)
+	assert.Equal(t, 120*time.Second, config.IdleTimeout)
+	assert.Equal(t, 30*time.Second, config.ShutdownTimeout)
+	assert.Equal(t, defaultWALCheckpointInterval, config.WALCheckpointInterval)
+	assert.Equal(t, defaultPageSize, config.DefaultPageSize)
+	assert.Equal(t, "WAL", config.SQLiteJournalMode)
+	assert.Equal(t, 5*time.Second, config.SQLiteBusyTimeout)
+	assert.Equal(t, "NORMAL", config.SQLiteSynchronous)
+	assert.Equal(t, defaultSQLiteMaxOpenConns, config.SQLiteMaxOpenConns)
+	assert.Equal(t, defaultSQLiteMaxIdleConns, config.SQLiteMaxIdleConns)
+	assert.Zero(t, config.SQLiteConnMaxLifetime)
+	assert.Equal(t, defaultSQLiteRetryAttempts, config.SQLiteRetryAttempts)
+	assert.Equal(t, StorageDriverSQLite, config.StorageDriver)
+	assert.Equal(t, defaultMaxPageSize, config.MaxPageSize)
+	assert.Equal(t, int64(defaultMaxBodyBytes), config.MaxBodyBytes)
+	assert.Equal(t, 500, config.MaxTitleLen)
+	assert.Equal(t, 10000, config.MaxCommentLen)
+	assert.Equal(t, "normal", config.DefaultPriority)
+	assert.Equal(t, "new", config.DefaultStatus)
 }
 
 func TestLoad_WithConfigFile(t *testing.T) {
@@ -146,6 +199,34 @@ func TestLoad_WithConfigFile(t *testing.T) {
 	tempConfigContent := `port: "9090"
 db_path: "custom.db"
 log_level: "debug"
+log_file: "logs/michishirube.log"
+log_max_size_mb: 10
+read_timeout: 1m30s
+write_timeout: 0s
+idle_timeout: -5s
+wal_checkpoint_interval: 10m
+allowed_link_schemes: ["https", "slack", "vscode"]
+default_page_size: 500
+max_page_size: 100
+sqlite_journal_mode: "delete"
+sqlite_busy_timeout: 2s
+sqlite_synchronous: "bogus"
+sqlite_max_open_conns: 1
+sqlite_max_idle_conns: -1
+sqlite_conn_max_lifetime: 30m
+sqlite_retry_attempts: 8
+jira_id_pattern: "[A-Z]+-[0-9]+"
+no_jira_id: "NONE"
+max_body_bytes: 4096
+strict_json: true
+max_title_len: 120
+max_comment_len: 0
+default_priority: "high"
+default_status: "in_progress"
+enforce_transitions: true
+shutdown_timeout: 45s
+tag_colors:
+  urgent: "#d93f0b"
 `
 
 	// Save current directory and change back after test
@@ -184,6 +265,97 @@ log_level: "debug"
 	assert.Equal(t, "9090", config.Port)
 	assert.Equal(t, "custom.db", config.DBPath)
 	assert.Equal(t, "debug", config.LogLevel)
+	assert.Equal(t, "logs/michishirube.log", config.LogFile)
+	assert.Equal(t, 10, config.LogMaxSizeMB)
+	assert.Equal(t, 90*time.Second, config.ReadTimeout)
+	assert.Zero(t, config.WriteTimeout, "zero disables the timeout")
+	assert.Equal(t, defaultIdleTimeout, config.IdleTimeout, "negative value falls back to the default")
+	assert.Equal(t, 45*time.Second, config.ShutdownTimeout)
+	assert.Equal(t, 10*time.Minute, config.WALCheckpointInterval)
+	assert.Equal(t, []string{"https", "slack", "vscode"}, config.AllowedLinkSchemes)
+	assert.Equal(t, 100, config.MaxPageSize)
+	assert.Equal(t, 100, config.DefaultPageSize, "default page size is capped at the maximum")
+	assert.Equal(t, "delete", config.SQLiteJournalMode)
+	assert.Equal(t, 2*time.Second, config.SQLiteBusyTimeout)
+	assert.Equal(t, "NORMAL", config.SQLiteSynchronous, "invalid value falls back to the default")
+	assert.Equal(t, 1, config.SQLiteMaxOpenConns)
+	assert.Equal(t, int64(4096), config.MaxBodyBytes)
+	assert.True(t, config.StrictJSON)
+	assert.Equal(t, 120, config.MaxTitleLen)
+	assert.Equal(t, defaultMaxCommentLen, config.MaxCommentLen, "zero falls back to the default")
+	assert.Equal(t, "high", config.DefaultPriority)
+	assert.True(t, config.EnforceTransitions)
+	assert.Equal(t, map[string]string{"urgent": "#d93f0b"}, config.TagColors)
+	assert.Equal(t, "in_progress", config.DefaultStatus)
+	assert.Equal(t, defaultSQLiteMaxIdleConns, config.SQLiteMaxIdleConns)
+	assert.Equal(t, 30*time.Minute, config.SQLiteConnMaxLifetime)
+	assert.Equal(t, 8, config.SQLiteRetryAttempts)
+	assert.Equal(t, "[A-Z]+-[0-9]+", config.JiraIDPattern)
+	assert.Equal(t, "NONE", config.NoJiraID)
+}
+
+func TestConfig_ValidateAndFix_InvalidTimeouts(t *testing.T) {
+	config := &Config{
+		Port:         "8080",
+		DBPath:       "test.db",
+		LogLevel:     "info",
+		ReadTimeout:  -time.Second,
+		WriteTimeout: -time.Minute,
+		IdleTimeout:  -time.Hour,
+
+		ShutdownTimeout: 0,
+	}
+	config.validateAndFix(logger.NewLogger(slog.LevelError))
+
+	assert.Equal(t, defaultReadTimeout, config.ReadTimeout)
+	assert.Equal(t, defaultWriteTimeout, config.WriteTimeout)
+	assert.Equal(t, defaultIdleTimeout, config.IdleTimeout)
+	assert.Equal(t, defaultShutdownTimeout, config.ShutdownTimeout, "zero would cut off every in-flight request")
+}
+
+func TestLoad_UnparseableTimeout(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("write_timeout: soon\n"), 0600))
+	t.Setenv("CONFIG_PATH", configPath)
+
+	ctx := logger.WithLogger(context.Background(), logger.NewLogger(slog.LevelError))
+	_, err := Load(ctx)
+
+	assert.ErrorIs(t, err, ErrConfigParse)
+}
+
+func TestConfig_ValidateAndFix_InvalidJiraIDPattern(t *testing.T) {
+	config := &Config{Port: "8080", DBPath: "test.db", LogLevel: "info", JiraIDPattern: "PROJ-("}
+	config.validateAndFix(logger.NewLogger(slog.LevelError))
+
+	assert.Empty(t, config.JiraIDPattern)
+}
+
+func TestConfig_ValidateAndFix_NewTaskDefaults(t *testing.T) {
+	tests := []struct {
+		name         string
+		priority     string
+		status       string
+		wantPriority string
+		wantStatus   string
+	}{
+		{"valid", "critical", "done", "critical", "done"},
+		{"unknown priority", "urgent", "new", "normal", "new"},
+		{"unknown status", "high", "waiting", "high", "new"},
+		{"archived status", "high", "archived", "high", "new"},
+		{"blocked status", "high", "blocked", "high", "new"},
+		{"empty", "", "", "normal", "new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Port: "8080", DBPath: "test.db", LogLevel: "info", DefaultPriority: tt.priority, DefaultStatus: tt.status}
+			config.validateAndFix(logger.NewLogger(slog.LevelError))
+
+			assert.Equal(t, tt.wantPriority, config.DefaultPriority)
+			assert.Equal(t, tt.wantStatus, config.DefaultStatus)
+		})
+	}
 }
 
 func TestLoad_WithEnvironmentVariables(t *testing.T) {
@@ -243,4 +415,254 @@ func TestPredefinedErrors(t *testing.T) {
 	assert.Equal(t, "db_path cannot be empty", ErrDBPathEmpty.Error())
 	assert.Equal(t, "failed to parse config.yaml", ErrConfigParse.Error())
 	assert.Equal(t, "invalid log level", ErrInvalidLogLevel.Error())
+	assert.Equal(t, "port must be a number between 1 and 65535", ErrInvalidPort.Error())
+}
+
+func TestValidatePort(t *testing.T) {
+	tests := []struct {
+		port  string
+		valid bool
+	}{
+		{"8080", true},
+		{"1", true},
+		{"65535", true},
+		{"0", false},
+		{"65536", false},
+		{"-1", false},
+		{"abc", false},
+		{"80a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.port, func(t *testing.T) {
+			err := validatePort(tt.port)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidPort)
+			}
+		})
+	}
+}
+
+func TestConfig_TLSEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected bool
+	}{
+		{"no tls", Config{}, false},
+		{"cert only", Config{TLSCertFile: "cert.pem"}, false},
+		{"key only", Config{TLSKeyFile: "key.pem"}, false},
+		{"cert and key", Config{TLSCertFile: "cert.pem", TLSKeyFile: "key.pem"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.config.TLSEnabled())
+		})
+	}
+}
+
+func TestLoad_APIKeysFromEnvironment(t *testing.T) {
+	t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+	t.Setenv("API_KEYS", "first-key, second-key,,")
+
+	ctx := logger.WithLogger(context.Background(), logger.NewLogger(slog.LevelError))
+	config, err := Load(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first-key", "second-key"}, config.APIKeys)
+	assert.True(t, config.AuthEnabled())
+}
+
+func TestConfig_AuthEnabled(t *testing.T) {
+	assert.False(t, (&Config{}).AuthEnabled())
+	assert.True(t, (&Config{APIKeys: []string{"key"}}).AuthEnabled())
+}
+
+func TestConfig_ValidateAndFix_RateLimit(t *testing.T) {
+	config := &Config{Port: "8080", DBPath: "test.db", LogLevel: "info", RateLimitRPS: -1, RateLimitBurst: 0}
+	config.validateAndFix(logger.NewLogger(slog.LevelError))
+
+	assert.Zero(t, config.RateLimitRPS)
+	assert.False(t, config.RateLimitEnabled())
+	assert.Equal(t, defaultRateLimitBurst, config.RateLimitBurst)
+
+	config = &Config{Port: "8080", DBPath: "test.db", LogLevel: "info", RateLimitRPS: 2.5, RateLimitBurst: 5}
+	config.validateAndFix(logger.NewLogger(slog.LevelError))
+
+	assert.True(t, config.RateLimitEnabled())
+	assert.Equal(t, 5, config.RateLimitBurst)
+}
+
+func TestLoad_ArchiveRetentionDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		expected int
+	}{
+		{"disabled by default", "", 0},
+		{"from environment", "30", 30},
+		{"negative disables purge", "-1", 0},
+		{"not a number is ignored", "thirty", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+			t.Setenv("ARCHIVE_RETENTION_DAYS", tt.env)
+
+			ctx := logger.WithLogger(context.Background(), logger.NewLogger(slog.LevelError))
+			config, err := Load(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, config.ArchiveRetentionDays)
+		})
+	}
+}
+
+func TestLoad_BindAddress(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("bind_address: 127.0.0.1\nport: \"9090\"\n"), 0600))
+	t.Setenv("CONFIG_PATH", configPath)
+
+	ctx := logger.WithLogger(context.Background(), logger.NewLogger(slog.LevelError))
+	config, err := Load(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1", config.BindAddress)
+	assert.Equal(t, "127.0.0.1:9090", config.ListenAddr())
+}
+
+func TestConfig_ValidateAndFix_BindAddress(t *testing.T) {
+	tests := []struct {
+		bindAddress string
+		expected    string
+		listenAddr  string
+	}{
+		{"", "", ":8080"},
+		{"localhost", "localhost", "localhost:8080"},
+		{"::1", "::1", "[::1]:8080"},
+		{"[::1]", "::1", "[::1]:8080"},
+		{"my-host.example.com", "my-host.example.com", "my-host.example.com:8080"},
+		{"127.0.0.1:9090", "", ":8080"},
+		{"not a host", "", ":8080"},
+		{"-bad-.example.com", "", ":8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bindAddress, func(t *testing.T) {
+			config := &Config{Port: "8080", DBPath: "test.db", LogLevel: "info", BindAddress: tt.bindAddress}
+			config.validateAndFix(logger.NewLogger(slog.LevelError))
+
+			assert.Equal(t, tt.expected, config.BindAddress)
+			assert.Equal(t, tt.listenAddr, config.ListenAddr())
+		})
+	}
+}
+
+func TestConfig_ValidateAndFix_ReportSchedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		expected string
+	}{
+		{"08:00", "08:00"},
+		{"17:30", "17:30"},
+		{"1h", "1h"},
+		{"30s", defaultReportSchedule},
+		{"25:00", defaultReportSchedule},
+		{"every morning", defaultReportSchedule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			config := &Config{Port: "8080", DBPath: "test.db", LogLevel: "info", ReportSchedule: tt.schedule}
+			config.validateAndFix(logger.NewLogger(slog.LevelError))
+
+			assert.Equal(t, tt.expected, config.ReportSchedule)
+		})
+	}
+}
+
+func TestConfig_NextReportAt(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		schedule string
+		expected time.Time
+	}{
+		{"17:30", time.Date(2024, 1, 15, 17, 30, 0, 0, time.UTC)},
+		{"08:00", time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)},
+		{"10:00", time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC)},
+		{"90m", now.Add(90 * time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			config := &Config{ReportSchedule: tt.schedule}
+			assert.Equal(t, tt.expected, config.NextReportAt(now))
+		})
+	}
+}
+
+func TestLoad_ReportOutput(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("report_output_path: /tmp/status.md\n"), 0600))
+	t.Setenv("CONFIG_PATH", configPath)
+
+	ctx := logger.WithLogger(context.Background(), logger.NewLogger(slog.LevelError))
+	config, err := Load(ctx)
+	require.NoError(t, err)
+
+	assert.True(t, config.ReportEnabled())
+	assert.Equal(t, "/tmp/status.md", config.ReportOutputPath)
+	assert.Equal(t, defaultReportSchedule, config.ReportSchedule)
+}
+
+func TestLoad_APIOnlyFromEnvironment(t *testing.T) {
+	t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+	t.Setenv("API_ONLY", "true")
+
+	ctx := logger.WithLogger(context.Background(), logger.NewLogger(slog.LevelError))
+	config, err := Load(ctx)
+	require.NoError(t, err)
+
+	assert.True(t, config.APIOnly)
+}
+
+func TestLoad_EventsEnabledFromEnvironment(t *testing.T) {
+	t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+	t.Setenv("EVENTS_ENABLED", "true")
+
+	ctx := logger.WithLogger(context.Background(), logger.NewLogger(slog.LevelError))
+	config, err := Load(ctx)
+	require.NoError(t, err)
+
+	assert.True(t, config.EventsEnabled)
+}
+
+func TestLoad_StorageDriver(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		expected string
+	}{
+		{name: "memory", env: "memory", expected: StorageDriverMemory},
+		{name: "sqlite", env: "sqlite", expected: StorageDriverSQLite},
+		{name: "unknown falls back to sqlite", env: "postgres", expected: StorageDriverSQLite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+			t.Setenv("STORAGE_DRIVER", tt.env)
+
+			ctx := logger.WithLogger(context.Background(), logger.NewLogger(slog.LevelError))
+			config, err := Load(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, config.StorageDriver)
+		})
+	}
 }
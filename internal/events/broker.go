@@ -0,0 +1,132 @@
+// Package events fans out task, link and comment changes to in-process
+// subscribers such as the /api/events stream.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types published for storage writes
+const (
+	TaskCreated    = "task.created"
+	TaskUpdated    = "task.updated"
+	TaskDeleted    = "task.deleted"
+	LinkCreated    = "link.created"
+	LinkUpdated    = "link.updated"
+	LinkDeleted    = "link.deleted"
+	CommentCreated = "comment.created"
+	CommentDeleted = "comment.deleted"
+
+	// TasksChanged is published by bulk writes (import, purge, tag rename)
+	// that touch an unknown set of tasks; clients should reload
+	TasksChanged = "tasks.changed"
+)
+
+const (
+	defaultHistorySize = 256 // Events kept for Last-Event-ID replay
+	subscriberBuffer   = 64  // Events queued per subscriber before it is dropped
+)
+
+// Event describes one change. ID increases monotonically for the lifetime of
+// the broker and is what clients send back as Last-Event-ID.
+type Event struct {
+	ID        uint64    `json:"id"`
+	Type      string    `json:"type"`
+	TaskID    string    `json:"task_id,omitempty"`
+	ObjectID  string    `json:"object_id,omitempty"` // Link or comment ID for link and comment events
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Broker is a simple in-process pub/sub. Publish never blocks: a subscriber
+// whose buffer is full is dropped and its channel closed, so a slow client
+// can't hold up writes.
+type Broker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Event
+	historySize int
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+// NewBroker creates a broker that remembers the most recent events for replay
+func NewBroker() *Broker {
+	return &Broker{
+		historySize: defaultHistorySize,
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Publish assigns the event an ID and timestamp and delivers it to every subscriber
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber. Events after lastID that are still in
+// the history are returned as missed, and everything published afterwards
+// arrives on the channel. The channel is closed when the subscriber is dropped
+// for falling behind, when cancel is called or when the broker is closed.
+func (b *Broker) Subscribe(lastID uint64) (ch <-chan Event, missed []Event, cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastID > 0 {
+		for _, event := range b.history {
+			if event.ID > lastID {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	events := make(chan Event, subscriberBuffer)
+	if b.closed {
+		close(events)
+		return events, missed, func() {}
+	}
+	b.subscribers[events] = struct{}{}
+
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[events]; ok {
+			delete(b.subscribers, events)
+			close(events)
+		}
+	}
+	return events, missed, cancel
+}
+
+// Close ends every subscription by closing its channel, so streams can finish
+// while the server shuts down. Later subscriptions are closed straight away.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker_PublishDeliversToSubscribers(t *testing.T) {
+	broker := NewBroker()
+	stream, missed, cancel := broker.Subscribe(0)
+	defer cancel()
+	assert.Empty(t, missed)
+
+	broker.Publish(Event{Type: TaskCreated, TaskID: "task-1"})
+
+	event := <-stream
+	assert.Equal(t, uint64(1), event.ID)
+	assert.Equal(t, TaskCreated, event.Type)
+	assert.Equal(t, "task-1", event.TaskID)
+	assert.False(t, event.Timestamp.IsZero())
+}
+
+func TestBroker_SubscribeReplaysAfterLastID(t *testing.T) {
+	broker := NewBroker()
+	for _, taskID := range []string{"a", "b", "c"} {
+		broker.Publish(Event{Type: TaskUpdated, TaskID: taskID})
+	}
+
+	_, missed, cancel := broker.Subscribe(1)
+	defer cancel()
+
+	require.Len(t, missed, 2)
+	assert.Equal(t, "b", missed[0].TaskID)
+	assert.Equal(t, "c", missed[1].TaskID)
+}
+
+func TestBroker_DropsSlowSubscribers(t *testing.T) {
+	broker := NewBroker()
+	stream, _, cancel := broker.Subscribe(0)
+	defer cancel()
+
+	// Nobody reads, so the buffer fills and the next publish drops the subscriber
+	for i := 0; i <= subscriberBuffer; i++ {
+		broker.Publish(Event{Type: TaskUpdated})
+	}
+
+	received := 0
+	for range stream {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+}
+
+func TestBroker_CloseEndsSubscriptions(t *testing.T) {
+	broker := NewBroker()
+	stream, _, cancel := broker.Subscribe(0)
+
+	broker.Close()
+	_, ok := <-stream
+	assert.False(t, ok, "open subscriptions are closed")
+	cancel()
+
+	late, _, cancel := broker.Subscribe(0)
+	defer cancel()
+	_, ok = <-late
+	assert.False(t, ok, "subscriptions after Close start closed")
+}
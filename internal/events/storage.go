@@ -0,0 +1,215 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+)
+
+// publishingStorage wraps a backend and publishes an event after each
+// successful write. Reads pass straight through.
+type publishingStorage struct {
+	storage.Storage
+	broker *Broker
+
+	// pending collects events inside a transaction; they are published
+	// only once it commits
+	pending *[]Event
+}
+
+// maintainedStorage keeps the backend's maintenance interfaces (checkpoints,
+// vacuum, orphan cleanup and schema version) visible through the wrapper
+type maintainedStorage struct {
+	*publishingStorage
+	storage.Checkpointer
+	storage.Optimizer
+	storage.OrphanCleaner
+	storage.SchemaVersioner
+}
+
+// NewStorage wraps store so its writes are published to broker
+func NewStorage(store storage.Storage, broker *Broker) storage.Storage {
+	wrapped := &publishingStorage{Storage: store, broker: broker}
+	checkpointer, isCheckpointer := store.(storage.Checkpointer)
+	optimizer, isOptimizer := store.(storage.Optimizer)
+	cleaner, isCleaner := store.(storage.OrphanCleaner)
+	versioner, isVersioner := store.(storage.SchemaVersioner)
+	if isCheckpointer && isOptimizer && isCleaner && isVersioner {
+		return maintainedStorage{
+			publishingStorage: wrapped,
+			Checkpointer:      checkpointer,
+			Optimizer:         optimizer,
+			OrphanCleaner:     cleaner,
+			SchemaVersioner:   versioner,
+		}
+	}
+	return wrapped
+}
+
+func (s *publishingStorage) publish(eventType, taskID, objectID string) {
+	event := Event{Type: eventType, TaskID: taskID, ObjectID: objectID}
+	if s.pending != nil {
+		*s.pending = append(*s.pending, event)
+		return
+	}
+	s.broker.Publish(event)
+}
+
+// WithTransaction holds back the events of writes made through tx until the
+// transaction commits, so subscribers never see changes that were rolled back
+func (s *publishingStorage) WithTransaction(ctx context.Context, fn func(tx storage.Storage) error) error {
+	var pending []Event
+	err := s.Storage.WithTransaction(ctx, func(tx storage.Storage) error {
+		return fn(&publishingStorage{Storage: tx, broker: s.broker, pending: &pending})
+	})
+	if err != nil {
+		return err
+	}
+	for _, event := range pending {
+		s.publish(event.Type, event.TaskID, event.ObjectID)
+	}
+	return nil
+}
+
+func (s *publishingStorage) CreateTask(ctx context.Context, task *models.Task) error {
+	if err := s.Storage.CreateTask(ctx, task); err != nil {
+		return err
+	}
+	s.publish(TaskCreated, task.ID, "")
+	return nil
+}
+
+func (s *publishingStorage) CreateTaskWithLinks(ctx context.Context, task *models.Task, links []*models.Link) error {
+	if err := s.Storage.CreateTaskWithLinks(ctx, task, links); err != nil {
+		return err
+	}
+	s.publish(TaskCreated, task.ID, "")
+	for _, link := range links {
+		s.publish(LinkCreated, task.ID, link.ID)
+	}
+	return nil
+}
+
+func (s *publishingStorage) UpdateTask(ctx context.Context, task *models.Task) error {
+	if err := s.Storage.UpdateTask(ctx, task); err != nil {
+		return err
+	}
+	s.publish(TaskUpdated, task.ID, "")
+	return nil
+}
+
+func (s *publishingStorage) DeleteTask(ctx context.Context, id string) error {
+	if err := s.Storage.DeleteTask(ctx, id); err != nil {
+		return err
+	}
+	s.publish(TaskDeleted, id, "")
+	return nil
+}
+
+func (s *publishingStorage) PurgeArchived(ctx context.Context, olderThan time.Time) (int, error) {
+	purged, err := s.Storage.PurgeArchived(ctx, olderThan)
+	if err == nil && purged > 0 {
+		s.publish(TasksChanged, "", "")
+	}
+	return purged, err
+}
+
+func (s *publishingStorage) MergeTasks(ctx context.Context, sourceID, targetID string, opts storage.MergeOptions) (*models.Task, error) {
+	merged, err := s.Storage.MergeTasks(ctx, sourceID, targetID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DeleteSource {
+		s.publish(TaskDeleted, sourceID, "")
+	} else {
+		s.publish(TaskUpdated, sourceID, "")
+	}
+	s.publish(TaskUpdated, targetID, "")
+	return merged, nil
+}
+
+func (s *publishingStorage) CreateLink(ctx context.Context, link *models.Link) error {
+	if err := s.Storage.CreateLink(ctx, link); err != nil {
+		return err
+	}
+	s.publish(LinkCreated, link.TaskID, link.ID)
+	return nil
+}
+
+func (s *publishingStorage) UpdateLink(ctx context.Context, link *models.Link) error {
+	if err := s.Storage.UpdateLink(ctx, link); err != nil {
+		return err
+	}
+	s.publish(LinkUpdated, link.TaskID, link.ID)
+	return nil
+}
+
+func (s *publishingStorage) UpdateLinkWithOptions(ctx context.Context, link *models.Link, opts storage.UpdateLinkOptions) error {
+	if err := s.Storage.UpdateLinkWithOptions(ctx, link, opts); err != nil {
+		return err
+	}
+	s.publish(LinkUpdated, link.TaskID, link.ID)
+	return nil
+}
+
+func (s *publishingStorage) DeleteLink(ctx context.Context, id string) error {
+	// Look the link up first so the event can name its task; a failed
+	// lookup still lets the delete report the real error
+	var taskID string
+	if link, err := s.Storage.GetLink(ctx, id); err == nil {
+		taskID = link.TaskID
+	}
+	if err := s.Storage.DeleteLink(ctx, id); err != nil {
+		return err
+	}
+	s.publish(LinkDeleted, taskID, id)
+	return nil
+}
+
+func (s *publishingStorage) CreateComment(ctx context.Context, comment *models.Comment) error {
+	if err := s.Storage.CreateComment(ctx, comment); err != nil {
+		return err
+	}
+	s.publish(CommentCreated, comment.TaskID, comment.ID)
+	return nil
+}
+
+func (s *publishingStorage) CreateComments(ctx context.Context, comments []*models.Comment) error {
+	if err := s.Storage.CreateComments(ctx, comments); err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		s.publish(CommentCreated, comment.TaskID, comment.ID)
+	}
+	return nil
+}
+
+func (s *publishingStorage) DeleteComment(ctx context.Context, id string) error {
+	var taskID string
+	if comment, err := s.Storage.GetComment(ctx, id); err == nil {
+		taskID = comment.TaskID
+	}
+	if err := s.Storage.DeleteComment(ctx, id); err != nil {
+		return err
+	}
+	s.publish(CommentDeleted, taskID, id)
+	return nil
+}
+
+func (s *publishingStorage) RenameTag(ctx context.Context, from, to string) (int, error) {
+	changed, err := s.Storage.RenameTag(ctx, from, to)
+	if err == nil && changed > 0 {
+		s.publish(TasksChanged, "", "")
+	}
+	return changed, err
+}
+
+func (s *publishingStorage) ImportData(ctx context.Context, data *models.ExportData) (*models.ImportResult, error) {
+	result, err := s.Storage.ImportData(ctx, data)
+	if err == nil && result.TasksImported+result.LinksImported+result.CommentsImported > 0 {
+		s.publish(TasksChanged, "", "")
+	}
+	return result, err
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+	"michishirube/internal/storage/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_TransactionPublishesOnCommit(t *testing.T) {
+	ctx := context.Background()
+	broker := NewBroker()
+	store := NewStorage(memory.New(), broker)
+	stream, _, cancel := broker.Subscribe(0)
+	defer cancel()
+
+	// Nothing is published for a rolled back transaction
+	err := store.WithTransaction(ctx, func(tx storage.Storage) error {
+		require.NoError(t, tx.CreateTask(ctx, &models.Task{Title: "Dropped", JiraID: "NO-JIRA"}))
+		return errors.New("abort")
+	})
+	require.Error(t, err)
+	assert.Empty(t, stream)
+
+	task := &models.Task{Title: "Kept", JiraID: "NO-JIRA"}
+	err = store.WithTransaction(ctx, func(tx storage.Storage) error {
+		if err := tx.CreateTask(ctx, task); err != nil {
+			return err
+		}
+		require.Empty(t, stream, "events wait for the commit")
+		return tx.CreateComment(ctx, &models.Comment{TaskID: task.ID, Content: "note"})
+	})
+	require.NoError(t, err)
+
+	require.Len(t, stream, 2)
+	created := <-stream
+	assert.Equal(t, TaskCreated, created.Type)
+	assert.Equal(t, task.ID, created.TaskID)
+	assert.Equal(t, CommentCreated, (<-stream).Type)
+}
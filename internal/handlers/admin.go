@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"michishirube/internal/logger"
+	"michishirube/internal/storage"
+)
+
+// AdminHandler serves database maintenance endpoints
+type AdminHandler struct {
+	storage storage.Storage
+}
+
+func NewAdminHandler(storage storage.Storage) *AdminHandler {
+	return &AdminHandler{storage: storage}
+}
+
+// HandleCheckpoint handles manual WAL checkpoint requests
+func (h *AdminHandler) HandleCheckpoint(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.checkpoint(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleVacuum handles manual database compaction requests
+func (h *AdminHandler) HandleVacuum(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.vacuum(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleOrphans reports links and comments whose task no longer exists
+func (h *AdminHandler) HandleOrphans(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.findOrphans(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleOrphansPurge deletes links and comments whose task no longer exists
+func (h *AdminHandler) HandleOrphansPurge(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.purgeOrphans(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// checkpoint folds the write-ahead log back into the database
+// @Summary Checkpoint the write-ahead log
+// @Description Run a WAL checkpoint (TRUNCATE) and report how many pages were written back
+// @Tags admin
+// @Produce json
+// @Success 200 {object} storage.CheckpointResult
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 501 {object} models.ErrorResponse
+// @Router /admin/checkpoint [post]
+func (h *AdminHandler) checkpoint(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	checkpointer, ok := h.storage.(storage.Checkpointer)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errCodeNotImplemented, "Checkpoints not supported by storage backend")
+		return
+	}
+
+	enabled, err := checkpointer.WALEnabled(r.Context())
+	if err != nil {
+		log.Error("Failed to read journal mode", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to checkpoint")
+		return
+	}
+	if !enabled {
+		writeError(w, http.StatusConflict, errCodeConflict, "WAL mode is not enabled")
+		return
+	}
+
+	result, err := checkpointer.Checkpoint(r.Context())
+	if err != nil {
+		log.Error("Manual WAL checkpoint failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to checkpoint")
+		return
+	}
+
+	log.Info("Manual WAL checkpoint completed",
+		"busy", result.Busy,
+		"log_pages", result.LogPages,
+		"checkpointed", result.Checkpointed)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// vacuum compacts the database and refreshes query planner statistics
+// @Summary Vacuum and optimize the database
+// @Description Run VACUUM and PRAGMA optimize to reclaim space left by deleted rows, and report the database size before and after. Writes wait while it runs
+// @Tags admin
+// @Produce json
+// @Success 200 {object} storage.OptimizeResult
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 501 {object} models.ErrorResponse
+// @Router /admin/vacuum [post]
+func (h *AdminHandler) vacuum(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	optimizer, ok := h.storage.(storage.Optimizer)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errCodeNotImplemented, "Vacuum not supported by storage backend")
+		return
+	}
+
+	var result storage.OptimizeResult
+	var err error
+	if result.SizeBefore, err = optimizer.DatabaseSize(r.Context()); err != nil {
+		log.Error("Failed to read database size", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to vacuum")
+		return
+	}
+	if err := optimizer.Optimize(r.Context()); err != nil {
+		log.Error("Manual vacuum failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to vacuum")
+		return
+	}
+	if result.SizeAfter, err = optimizer.DatabaseSize(r.Context()); err != nil {
+		log.Error("Failed to read database size", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to vacuum")
+		return
+	}
+
+	log.Info("Manual vacuum completed",
+		"size_before", result.SizeBefore,
+		"size_after", result.SizeAfter)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// findOrphans lists links and comments that reference a missing task
+// @Summary Find orphaned links and comments
+// @Description List the IDs of links and comments whose task does not exist, which can happen when data was written with foreign keys disabled
+// @Tags admin
+// @Produce json
+// @Success 200 {object} storage.OrphanReport
+// @Failure 501 {object} models.ErrorResponse
+// @Router /admin/orphans [get]
+func (h *AdminHandler) findOrphans(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	cleaner, ok := h.storage.(storage.OrphanCleaner)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errCodeNotImplemented, "Orphan detection not supported by storage backend")
+		return
+	}
+
+	links, comments, err := cleaner.FindOrphans(r.Context())
+	if err != nil {
+		log.Error("Failed to find orphans", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to find orphans")
+		return
+	}
+
+	writeOrphanReport(w, links, comments)
+}
+
+// purgeOrphans deletes links and comments that reference a missing task
+// @Summary Purge orphaned links and comments
+// @Description Delete links and comments whose task does not exist and return the IDs that were removed
+// @Tags admin
+// @Produce json
+// @Success 200 {object} storage.OrphanReport
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 501 {object} models.ErrorResponse
+// @Router /admin/orphans/purge [post]
+func (h *AdminHandler) purgeOrphans(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	cleaner, ok := h.storage.(storage.OrphanCleaner)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errCodeNotImplemented, "Orphan cleanup not supported by storage backend")
+		return
+	}
+
+	links, comments, err := cleaner.PurgeOrphans(r.Context())
+	if err != nil {
+		log.Error("Failed to purge orphans", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to purge orphans")
+		return
+	}
+
+	log.Info("Purged orphans", "links", len(links), "comments", len(comments))
+	writeOrphanReport(w, links, comments)
+}
+
+func writeOrphanReport(w http.ResponseWriter, links, comments []string) {
+	// Empty lists are sent as [] rather than null
+	report := storage.OrphanReport{Links: append([]string{}, links...), Comments: append([]string{}, comments...)}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
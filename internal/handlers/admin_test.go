@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"michishirube/internal/handlers/mocks"
+	"michishirube/internal/storage"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkpointingStorage combines the storage and checkpointer mocks so the
+// handler's type assertion succeeds
+type checkpointingStorage struct {
+	*mocks.MockStorage
+	*mocks.MockCheckpointer
+}
+
+// optimizingStorage combines the storage and optimizer mocks
+type optimizingStorage struct {
+	*mocks.MockStorage
+	*mocks.MockOptimizer
+}
+
+// cleaningStorage combines the storage and orphan cleaner mocks
+type cleaningStorage struct {
+	*mocks.MockStorage
+	*mocks.MockOrphanCleaner
+}
+
+func TestAdminHandler_Checkpoint_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkpointer := mocks.NewMockCheckpointer(ctrl)
+	handler := NewAdminHandler(checkpointingStorage{mocks.NewMockStorage(ctrl), checkpointer})
+
+	checkpointer.EXPECT().WALEnabled(gomock.Any()).Return(true, nil).Times(1)
+	checkpointer.EXPECT().
+		Checkpoint(gomock.Any()).
+		Return(&storage.CheckpointResult{LogPages: 12, Checkpointed: 12}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/checkpoint", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleCheckpoint(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result storage.CheckpointResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 12, result.Checkpointed)
+	assert.False(t, result.Busy)
+}
+
+func TestAdminHandler_Checkpoint_WALDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkpointer := mocks.NewMockCheckpointer(ctrl)
+	handler := NewAdminHandler(checkpointingStorage{mocks.NewMockStorage(ctrl), checkpointer})
+
+	checkpointer.EXPECT().WALEnabled(gomock.Any()).Return(false, nil).Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/checkpoint", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleCheckpoint(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assertErrorResponse(t, w, errCodeConflict, "WAL mode is not enabled")
+}
+
+func TestAdminHandler_Checkpoint_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkpointer := mocks.NewMockCheckpointer(ctrl)
+	handler := NewAdminHandler(checkpointingStorage{mocks.NewMockStorage(ctrl), checkpointer})
+
+	checkpointer.EXPECT().WALEnabled(gomock.Any()).Return(true, nil).Times(1)
+	checkpointer.EXPECT().Checkpoint(gomock.Any()).Return(nil, errors.New("database is locked")).Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/checkpoint", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleCheckpoint(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestAdminHandler_Checkpoint_NotSupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewAdminHandler(mocks.NewMockStorage(ctrl))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/checkpoint", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleCheckpoint(w, req)
+
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+	assertErrorResponse(t, w, errCodeNotImplemented, "not supported")
+}
+
+func TestAdminHandler_Checkpoint_MethodNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewAdminHandler(mocks.NewMockStorage(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/checkpoint", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleCheckpoint(w, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestAdminHandler_Vacuum_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	optimizer := mocks.NewMockOptimizer(ctrl)
+	handler := NewAdminHandler(optimizingStorage{mocks.NewMockStorage(ctrl), optimizer})
+
+	gomock.InOrder(
+		optimizer.EXPECT().DatabaseSize(gomock.Any()).Return(int64(8192), nil),
+		optimizer.EXPECT().Optimize(gomock.Any()).Return(nil),
+		optimizer.EXPECT().DatabaseSize(gomock.Any()).Return(int64(4096), nil),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/vacuum", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleVacuum(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result storage.OptimizeResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, int64(8192), result.SizeBefore)
+	assert.Equal(t, int64(4096), result.SizeAfter)
+}
+
+func TestAdminHandler_Vacuum_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	optimizer := mocks.NewMockOptimizer(ctrl)
+	handler := NewAdminHandler(optimizingStorage{mocks.NewMockStorage(ctrl), optimizer})
+
+	optimizer.EXPECT().DatabaseSize(gomock.Any()).Return(int64(8192), nil).Times(1)
+	optimizer.EXPECT().Optimize(gomock.Any()).Return(errors.New("database is locked")).Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/vacuum", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleVacuum(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assertErrorResponse(t, w, errCodeInternal, "Failed to vacuum")
+}
+
+func TestAdminHandler_Vacuum_NotSupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewAdminHandler(mocks.NewMockStorage(ctrl))
+
+	for _, method := range []string{http.MethodPost, http.MethodGet} {
+		req := httptest.NewRequest(method, "/api/admin/vacuum", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleVacuum(w, req)
+
+		if method == http.MethodPost {
+			assert.Equal(t, http.StatusNotImplemented, w.Code)
+		} else {
+			assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		}
+	}
+}
+
+func TestAdminHandler_Orphans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cleaner := mocks.NewMockOrphanCleaner(ctrl)
+	handler := NewAdminHandler(cleaningStorage{mocks.NewMockStorage(ctrl), cleaner})
+
+	cleaner.EXPECT().FindOrphans(gomock.Any()).Return([]string{"link-1"}, nil, nil).Times(1)
+
+	w := httptest.NewRecorder()
+	handler.HandleOrphans(w, httptest.NewRequest(http.MethodGet, "/api/admin/orphans", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"links": ["link-1"], "comments": []}`, w.Body.String())
+
+	cleaner.EXPECT().PurgeOrphans(gomock.Any()).Return([]string{"link-1"}, []string{"comment-1"}, nil).Times(1)
+
+	w = httptest.NewRecorder()
+	handler.HandleOrphansPurge(w, httptest.NewRequest(http.MethodPost, "/api/admin/orphans/purge", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var report storage.OrphanReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, []string{"link-1"}, report.Links)
+	assert.Equal(t, []string{"comment-1"}, report.Comments)
+}
+
+func TestAdminHandler_Orphans_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cleaner := mocks.NewMockOrphanCleaner(ctrl)
+	handler := NewAdminHandler(cleaningStorage{mocks.NewMockStorage(ctrl), cleaner})
+
+	cleaner.EXPECT().PurgeOrphans(gomock.Any()).Return(nil, nil, errors.New("database is locked")).Times(1)
+
+	w := httptest.NewRecorder()
+	handler.HandleOrphansPurge(w, httptest.NewRequest(http.MethodPost, "/api/admin/orphans/purge", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assertErrorResponse(t, w, errCodeInternal, "Failed to purge orphans")
+
+	w = httptest.NewRecorder()
+	handler.HandleOrphansPurge(w, httptest.NewRequest(http.MethodGet, "/api/admin/orphans/purge", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	unsupported := NewAdminHandler(mocks.NewMockStorage(ctrl))
+	w = httptest.NewRecorder()
+	unsupported.HandleOrphans(w, httptest.NewRequest(http.MethodGet, "/api/admin/orphans", nil))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
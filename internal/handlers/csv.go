@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"time"
+
+	"michishirube/internal/logger"
+	"michishirube/internal/models"
+)
+
+var taskCSVHeader = []string{"id", "jira_id", "title", "priority", "status", "tags", "created_at", "updated_at"}
+
+// writeTasksCSV streams tasks as a CSV attachment, one row per task
+func (h *TaskHandler) writeTasksCSV(w http.ResponseWriter, r *http.Request, tasks []*models.Task) {
+	log := logger.FromContext(r.Context())
+
+	filename := "michishirube-tasks-" + time.Now().Format("2006-01-02") + ".csv"
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(taskCSVHeader); err != nil {
+		log.Error("Failed to write CSV header", "error", err)
+		return
+	}
+
+	for _, task := range tasks {
+		record := []string{
+			task.ID,
+			task.JiraID,
+			task.Title,
+			string(task.Priority),
+			string(task.Status),
+			strings.Join(task.Tags, "|"),
+			task.CreatedAt.Format(time.RFC3339),
+			task.UpdatedAt.Format(time.RFC3339),
+		}
+		if err := cw.Write(record); err != nil {
+			log.Error("Failed to write CSV row", "error", err, "task_id", task.ID)
+			return
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		log.Error("Failed to flush CSV", "error", err)
+	}
+}
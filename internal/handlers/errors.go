@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"michishirube/internal/models"
+)
+
+// Error codes returned in models.ErrorResponse
+const (
+	errCodeBadRequest       = "BAD_REQUEST"
+	errCodeNotFound         = "NOT_FOUND"
+	errCodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	errCodeConflict         = "CONFLICT"
+	errCodeBodyTooLarge     = "BODY_TOO_LARGE"
+	errCodeInternal         = "INTERNAL_ERROR"
+	errCodeNotImplemented   = "NOT_IMPLEMENTED"
+)
+
+// writeError writes an API error as a JSON models.ErrorResponse
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeErrorResponse(w, status, models.ErrorResponse{Error: message, Code: code})
+}
+
+// writeValidationError writes a 400 for err. When err wraps a
+// models.ValidationError the response names the field and reason; any other
+// error is reported as invalid input without a field.
+func writeValidationError(w http.ResponseWriter, err error) {
+	fieldErr := models.FieldError{Message: err.Error(), Code: models.CodeInvalid}
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		fieldErr = models.FieldError{
+			Field:   validationErr.Field,
+			Message: validationErr.Message,
+			Code:    validationErr.Code,
+		}
+	}
+	writeErrorResponse(w, http.StatusBadRequest, models.ValidationErrorResponse{Error: fieldErr})
+}
+
+// writeFieldError writes a 400 for a request field rejected by the handler
+// itself, before the models get to validate it
+func writeFieldError(w http.ResponseWriter, field, code, message string) {
+	writeErrorResponse(w, http.StatusBadRequest, models.ValidationErrorResponse{
+		Error: models.FieldError{Field: field, Message: message, Code: code},
+	})
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, response any) {
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"michishirube/internal/events"
+	"michishirube/internal/logger"
+)
+
+// eventsHeartbeatInterval is how often an idle stream sends a comment line so
+// proxies don't close the connection
+const eventsHeartbeatInterval = 15 * time.Second
+
+// EventsHandler streams storage changes as server-sent events
+type EventsHandler struct {
+	broker *events.Broker
+}
+
+func NewEventsHandler(broker *events.Broker) *EventsHandler {
+	return &EventsHandler{broker: broker}
+}
+
+// HandleEvents handles the /api/events stream
+func (h *EventsHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.streamEvents(w, r)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodGet)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// streamEvents sends task, link and comment changes until the client disconnects
+// @Summary Stream changes
+// @Description Server-sent events for task, link and comment writes (task.created, task.updated, task.deleted, link.created, link.updated, link.deleted, comment.created, comment.deleted, and tasks.changed for bulk writes). Reconnecting clients send Last-Event-ID to replay recent events they missed. Slow clients are disconnected and should reconnect. Only available when events_enabled is set.
+// @Tags events
+// @Produce text/event-stream
+// @Param Last-Event-ID header int false "ID of the last event received"
+// @Success 200 {object} events.Event "Stream of events"
+// @Failure 400 {object} models.ErrorResponse
+// @Router /events [get]
+func (h *EventsHandler) streamEvents(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	var lastID uint64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid Last-Event-ID")
+			return
+		}
+		lastID = id
+	}
+
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Warn("Failed to clear write deadline for event stream", "error", err)
+	}
+
+	stream, missed, cancel := h.broker.Subscribe(lastID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range missed {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		log.Error("Event stream does not support flushing", "error", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-stream:
+			if !ok {
+				// Dropped for falling behind or closed for shutdown; the
+				// client reconnects with Last-Event-ID
+				log.Warn("Event stream subscription ended")
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes one event in the text/event-stream format
+func writeEvent(w io.Writer, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
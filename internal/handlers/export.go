@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"michishirube/internal/logger"
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+)
+
+const (
+	exportFormatJSON   = "json"
+	exportFormatNDJSON = "ndjson"
+)
+
+// ndjsonRecord is one line of an ndjson export: a record type and its payload
+type ndjsonRecord struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// HandleExport handles full data export requests
+func (h *TaskHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.exportData(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleImport handles bulk data import requests
+func (h *TaskHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.importData(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// exportBufferSize is how much of an export is held back before it is sent.
+// Failures within the first buffer can still be answered with a 500.
+const exportBufferSize = 32 << 10
+
+// exportData streams every task, link and comment
+// @Summary Export all data
+// @Description Stream a backup of all tasks (including archived), links and comments, read from a single consistent snapshot. Use format=ndjson for line-delimited records of the form {"type":"task|link|comment","data":{...}}: every task first, then links, then comments
+// @Tags backup
+// @Produce json
+// @Produce x-ndjson
+// @Param format query string false "Output format" Enums(json, ndjson)
+// @Success 200 {object} models.ExportData
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /export [get]
+func (h *TaskHandler) exportData(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatJSON
+	}
+	if format != exportFormatJSON && format != exportFormatNDJSON {
+		writeError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid format: must be json or ndjson")
+		return
+	}
+
+	if format == exportFormatNDJSON {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	// Tasks, links and comments are read in one transaction so the export is
+	// a consistent snapshot, with one query each however many tasks there are
+	out := &exportWriter{w: w}
+	buf := bufio.NewWriterSize(out, exportBufferSize)
+	var tasks int
+	err := h.storage.WithTransaction(r.Context(), func(tx storage.Storage) error {
+		var err error
+		if format == exportFormatNDJSON {
+			tasks, err = writeNDJSONExport(r.Context(), tx, buf)
+		} else {
+			tasks, err = writeJSONExport(r.Context(), tx, buf)
+		}
+		return err
+	})
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err != nil {
+		// Once the first byte is out the status can no longer change, so the
+		// failure is only logged and the stream is cut short
+		if !out.written {
+			log.Error("Failed to export data", "format", format, "error", err)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to export data")
+			return
+		}
+		log.Error("Export aborted", "format", format, "error", err)
+		return
+	}
+
+	log.Info("Data exported", "format", format, "tasks", tasks)
+}
+
+// exportWriter records whether any of the export has reached the client
+type exportWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	e.written = true
+	return e.w.Write(p)
+}
+
+// writeJSONExport writes a single {tasks, links, comments} document, encoding
+// one record at a time so the full dataset is never held in memory. It
+// returns how many tasks were written.
+func writeJSONExport(ctx context.Context, store storage.Storage, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+
+	if _, err := io.WriteString(w, `{"tasks":[`); err != nil {
+		return 0, err
+	}
+	tasks := 0
+	err := store.StreamTasks(ctx, storage.TaskFilters{IncludeArchived: true}, func(task *models.Task) error {
+		tasks++
+		return writeArrayItem(w, enc, tasks-1, task)
+	})
+	if err != nil {
+		return tasks, fmt.Errorf("failed to stream tasks: %w", err)
+	}
+
+	if _, err := io.WriteString(w, `],"links":[`); err != nil {
+		return tasks, err
+	}
+	n := 0
+	err = store.StreamLinks(ctx, func(link *models.Link) error {
+		n++
+		return writeArrayItem(w, enc, n-1, link)
+	})
+	if err != nil {
+		return tasks, fmt.Errorf("failed to stream links: %w", err)
+	}
+
+	if _, err := io.WriteString(w, `],"comments":[`); err != nil {
+		return tasks, err
+	}
+	n = 0
+	err = store.StreamComments(ctx, func(comment *models.Comment) error {
+		n++
+		return writeArrayItem(w, enc, n-1, comment)
+	})
+	if err != nil {
+		return tasks, fmt.Errorf("failed to stream comments: %w", err)
+	}
+
+	_, err = io.WriteString(w, "]}\n")
+	return tasks, err
+}
+
+// writeNDJSONExport writes every task, then every link, then every comment,
+// one record per line. It returns how many tasks were written.
+func writeNDJSONExport(ctx context.Context, store storage.Storage, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+
+	tasks := 0
+	err := store.StreamTasks(ctx, storage.TaskFilters{IncludeArchived: true}, func(task *models.Task) error {
+		tasks++
+		return writeNDJSONRecord(enc, "task", task)
+	})
+	if err != nil {
+		return tasks, fmt.Errorf("failed to stream tasks: %w", err)
+	}
+
+	err = store.StreamLinks(ctx, func(link *models.Link) error {
+		return writeNDJSONRecord(enc, "link", link)
+	})
+	if err != nil {
+		return tasks, fmt.Errorf("failed to stream links: %w", err)
+	}
+
+	err = store.StreamComments(ctx, func(comment *models.Comment) error {
+		return writeNDJSONRecord(enc, "comment", comment)
+	})
+	if err != nil {
+		return tasks, fmt.Errorf("failed to stream comments: %w", err)
+	}
+	return tasks, nil
+}
+
+// writeArrayItem encodes v as the i-th element of a JSON array
+func writeArrayItem(w io.Writer, enc *json.Encoder, i int, v interface{}) error {
+	if i > 0 {
+		if _, err := io.WriteString(w, ","); err != nil {
+			return err
+		}
+	}
+	return enc.Encode(v)
+}
+
+func writeNDJSONRecord(enc *json.Encoder, recordType string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return enc.Encode(ndjsonRecord{Type: recordType, Data: data})
+}
+
+// importData inserts a previously exported backup
+// @Summary Import data
+// @Description Bulk-insert tasks, links and comments from an export in a single transaction. Records whose ID already exists are skipped. Accepts the JSON document or ndjson (Content-Type application/x-ndjson or format=ndjson)
+// @Tags backup
+// @Accept json
+// @Accept x-ndjson
+// @Produce json
+// @Param format query string false "Input format" Enums(json, ndjson)
+// @Param data body models.ExportData true "Exported data"
+// @Success 200 {object} models.ImportResult
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /import [post]
+func (h *TaskHandler) importData(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	var (
+		data *models.ExportData
+		err  error
+	)
+	if r.URL.Query().Get("format") == exportFormatNDJSON ||
+		strings.Contains(r.Header.Get("Content-Type"), exportFormatNDJSON) {
+		data, err = decodeNDJSONImport(r.Body)
+	} else {
+		data = &models.ExportData{}
+		err = json.NewDecoder(r.Body).Decode(data)
+	}
+	if err != nil {
+		log.Error("Invalid import payload", "error", err)
+		writeError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid JSON")
+		return
+	}
+
+	result, err := h.storage.ImportData(r.Context(), data)
+	if err != nil {
+		if isValidationError(err) {
+			writeValidationError(w, err)
+			return
+		}
+		log.Error("Failed to import data", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to import data")
+		return
+	}
+
+	log.Info("Data imported",
+		"tasks_imported", result.TasksImported,
+		"tasks_skipped", result.TasksSkipped,
+		"links_imported", result.LinksImported,
+		"links_skipped", result.LinksSkipped,
+		"comments_imported", result.CommentsImported,
+		"comments_skipped", result.CommentsSkipped)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// decodeNDJSONImport reads line-delimited export records into an ExportData
+func decodeNDJSONImport(r io.Reader) (*models.ExportData, error) {
+	data := &models.ExportData{}
+	dec := json.NewDecoder(r)
+
+	for {
+		var record ndjsonRecord
+		if err := dec.Decode(&record); err == io.EOF {
+			return data, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch record.Type {
+		case "task":
+			var task models.Task
+			if err := json.Unmarshal(record.Data, &task); err != nil {
+				return nil, err
+			}
+			data.Tasks = append(data.Tasks, &task)
+		case "link":
+			var link models.Link
+			if err := json.Unmarshal(record.Data, &link); err != nil {
+				return nil, err
+			}
+			data.Links = append(data.Links, &link)
+		case "comment":
+			var comment models.Comment
+			if err := json.Unmarshal(record.Data, &comment); err != nil {
+				return nil, err
+			}
+			data.Comments = append(data.Comments, &comment)
+		default:
+			return nil, fmt.Errorf("unknown record type %q", record.Type)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"michishirube/internal/handlers/mocks"
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectExportQueries(mockStorage *mocks.MockStorage, task *models.Task) {
+	mockStorage.EXPECT().
+		WithTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(storage.Storage) error) error {
+			return fn(mockStorage)
+		}).
+		Times(1)
+	mockStorage.EXPECT().
+		StreamTasks(gomock.Any(), storage.TaskFilters{IncludeArchived: true}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ storage.TaskFilters, fn func(*models.Task) error) error {
+			return fn(task)
+		}).
+		Times(1)
+	mockStorage.EXPECT().
+		StreamLinks(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*models.Link) error) error {
+			return fn(createValidLink())
+		}).
+		Times(1)
+	mockStorage.EXPECT().
+		StreamComments(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(*models.Comment) error) error {
+			return fn(createValidComment())
+		}).
+		Times(1)
+}
+
+func TestTaskHandler_HandleExport_JSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+	expectExportQueries(mockStorage, createValidTask())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleExport(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response models.ExportData
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Tasks, 1)
+	assert.Len(t, response.Links, 1)
+	assert.Len(t, response.Comments, 1)
+}
+
+func TestTaskHandler_HandleExport_NDJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+	expectExportQueries(mockStorage, createValidTask())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?format=ndjson", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleExport(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	var types []string
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var record ndjsonRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		types = append(types, record.Type)
+	}
+	assert.Equal(t, []string{"task", "link", "comment"}, types)
+}
+
+func TestTaskHandler_HandleExport_StorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().
+		WithTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(storage.Storage) error) error {
+			return fn(mockStorage)
+		}).
+		Times(1)
+	mockStorage.EXPECT().
+		StreamTasks(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("database locked")).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleExport(w, req)
+
+	// Nothing was sent before the failure, so it can still be reported
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assertErrorResponse(t, w, errCodeInternal, "Failed to export data")
+}
+
+func TestTaskHandler_HandleExport_InvalidFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/export?format=xml", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleExport(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertErrorResponse(t, w, errCodeBadRequest, "Invalid format")
+}
+
+func TestTaskHandler_HandleImport_NDJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().
+		ImportData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, data *models.ExportData) (*models.ImportResult, error) {
+			assert.Len(t, data.Tasks, 1)
+			assert.Len(t, data.Links, 1)
+			assert.Len(t, data.Comments, 0)
+			return &models.ImportResult{TasksImported: 1, LinksSkipped: 1}, nil
+		}).
+		Times(1)
+
+	body := `{"type":"task","data":{"id":"task-123","jira_id":"OCPBUGS-1234","title":"Fix","priority":"high","status":"new"}}
+{"type":"link","data":{"id":"link-123","task_id":"task-123","type":"pull_request","url":"https://github.com/org/repo/pull/1","status":"open"}}
+`
+	req := httptest.NewRequest(http.MethodPost, "/api/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+
+	handler.HandleImport(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ImportResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.TasksImported)
+	assert.Equal(t, 1, response.LinksSkipped)
+}
+
+func TestTaskHandler_HandleImport_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		storageErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "invalid JSON",
+			body:           `{"tasks": [`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "validation error",
+			body:           `{"tasks":[{"title":""}]}`,
+			storageErr:     &models.ValidationError{Field: "title", Message: "title is required"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "storage error",
+			body:           `{"tasks":[]}`,
+			storageErr:     errors.New("database locked"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			handler := NewTaskHandler(mockStorage)
+
+			if tt.storageErr != nil {
+				mockStorage.EXPECT().ImportData(gomock.Any(), gomock.Any()).Return(nil, tt.storageErr).Times(1)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/import", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.HandleImport(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
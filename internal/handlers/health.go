@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"michishirube/internal/logger"
+	"michishirube/internal/storage"
+)
+
+// BuildInfo identifies the running binary
+type BuildInfo struct {
+	Version string
+	Commit  string
+}
+
+// HealthHandler serves the liveness and readiness probes. It needs no
+// templates, so it is available in API-only mode.
+type HealthHandler struct {
+	storage storage.Storage
+	build   BuildInfo
+}
+
+func NewHealthHandler(storage storage.Storage, build BuildInfo) *HealthHandler {
+	return &HealthHandler{storage: storage, build: build}
+}
+
+// HealthCheck - Simple health check endpoint, reporting the deployed build and,
+// for backends with migrations, the database schema version
+func (h *HealthHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	response := map[string]interface{}{
+		"status":    "healthy",
+		"timestamp": time.Now().Format(time.RFC3339),
+		"version":   h.build.Version,
+		"commit":    h.build.Commit,
+	}
+
+	// Liveness doesn't depend on the database, so a failed read only leaves
+	// the version out
+	if versioner, ok := h.storage.(storage.SchemaVersioner); ok {
+		if version, err := versioner.SchemaVersion(); err == nil {
+			response["schema_version"] = version
+			response["expected_schema_version"] = versioner.ExpectedSchemaVersion()
+		} else {
+			logger.FromContext(r.Context()).Warn("Failed to read schema version", "error", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log := logger.FromContext(r.Context())
+		log.Error("Failed to write health check response", "error", err)
+	}
+}
+
+// Ready - Readiness check that verifies the database is reachable and its
+// schema is not behind the version this build expects
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	response := map[string]string{
+		"status":    "ready",
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+	status := http.StatusOK
+
+	// The probe is unauthenticated, so the details stay in the log and the
+	// response only carries a fixed reason
+	if reason, err := h.checkReady(r.Context()); err != nil {
+		log.Warn("Readiness check failed", "reason", reason, "error", err)
+		response["status"] = "unavailable"
+		response["error"] = reason
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Error("Failed to write readiness response", "error", err)
+	}
+}
+
+// Reasons reported by the readiness check when the server is not ready
+const (
+	readyReasonDatabaseUnreachable = "database unreachable"
+	readyReasonSchemaBehind        = "schema behind"
+)
+
+// checkReady pings the database and compares its schema version with the one
+// this build migrates to. On failure it returns the reason to report along
+// with the error to log.
+func (h *HealthHandler) checkReady(ctx context.Context) (string, error) {
+	if err := h.storage.Ping(ctx); err != nil {
+		return readyReasonDatabaseUnreachable, err
+	}
+
+	versioner, ok := h.storage.(storage.SchemaVersioner)
+	if !ok {
+		return "", nil
+	}
+	version, err := versioner.SchemaVersion()
+	if err != nil {
+		return readyReasonDatabaseUnreachable, err
+	}
+	if expected := versioner.ExpectedSchemaVersion(); version < expected {
+		return readyReasonSchemaBehind, fmt.Errorf("database schema version %d is behind the expected version %d", version, expected)
+	}
+	return "", nil
+}
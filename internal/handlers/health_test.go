@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandler_HealthCheck(t *testing.T) {
+	handler := NewHealthHandler(NewMockWebStorage(), BuildInfo{Version: "1.2.3", Commit: "abc1234"})
+
+	req := createTestRequest(http.MethodGet, "/health", "")
+	w := httptest.NewRecorder()
+
+	handler.HealthCheck(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "healthy", body["status"])
+	assert.NotEmpty(t, body["timestamp"])
+	assert.Equal(t, "1.2.3", body["version"])
+	assert.Equal(t, "abc1234", body["commit"])
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	handler := NewHealthHandler(NewMockWebStorage(), BuildInfo{})
+
+	req := createTestRequest(http.MethodGet, "/ready", "")
+	w := httptest.NewRecorder()
+
+	handler.Ready(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "ready", body["status"])
+	assert.NotEmpty(t, body["timestamp"])
+}
+
+func TestHealthHandler_Ready_DatabaseUnavailable(t *testing.T) {
+	mockStorage := NewMockWebStorage()
+	mockStorage.pingErr = errors.New("database is closed")
+	handler := NewHealthHandler(mockStorage, BuildInfo{})
+
+	req := createTestRequest(http.MethodGet, "/ready", "")
+	w := httptest.NewRecorder()
+
+	handler.Ready(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "unavailable", body["status"])
+	assert.Equal(t, "database unreachable", body["error"])
+	assert.NotContains(t, w.Body.String(), "database is closed", "driver errors stay in the log")
+}
+
+// versionedStorage reports a fixed schema version, like a migrated database
+type versionedStorage struct {
+	*MockWebStorage
+	version  int
+	expected int
+}
+
+func (s *versionedStorage) SchemaVersion() (int, error) { return s.version, nil }
+func (s *versionedStorage) ExpectedSchemaVersion() int  { return s.expected }
+
+func TestHealthHandler_HealthCheck_SchemaVersion(t *testing.T) {
+	handler := NewHealthHandler(&versionedStorage{MockWebStorage: NewMockWebStorage(), version: 7, expected: 7}, BuildInfo{})
+
+	req := createTestRequest(http.MethodGet, "/health", "")
+	w := httptest.NewRecorder()
+
+	handler.HealthCheck(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(7), body["schema_version"])
+	assert.Equal(t, float64(7), body["expected_schema_version"])
+}
+
+func TestHealthHandler_Ready_SchemaBehind(t *testing.T) {
+	store := &versionedStorage{MockWebStorage: NewMockWebStorage(), version: 7, expected: 7}
+	handler := NewHealthHandler(store, BuildInfo{})
+
+	w := httptest.NewRecorder()
+	handler.Ready(w, createTestRequest(http.MethodGet, "/ready", ""))
+	assert.Equal(t, http.StatusOK, w.Code, "an up to date schema is ready")
+
+	store.expected = 8
+	w = httptest.NewRecorder()
+	handler.Ready(w, createTestRequest(http.MethodGet, "/ready", ""))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "unavailable", body["status"])
+	assert.Equal(t, "schema behind", body["error"])
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"michishirube/internal/logger"
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+)
+
+// markdownLinkSections lists link types in the order they appear in a task export
+var markdownLinkSections = []struct {
+	linkType models.LinkType
+	heading  string
+}{
+	{models.JiraTicket, "Jira Tickets"},
+	{models.PullRequest, "Pull Requests"},
+	{models.Commit, "Commits"},
+	{models.SlackThread, "Slack Threads"},
+	{models.Incident, "Incidents"},
+	{models.DesignDoc, "Design Docs"},
+	{models.Documentation, "Documentation"},
+	{models.Other, "Other"},
+}
+
+// unsafeFilenameChars matches anything that shouldn't appear in a download filename
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// handleTaskMarkdown serves the /api/tasks/{id}/export.md subresource
+func (h *TaskHandler) handleTaskMarkdown(w http.ResponseWriter, r *http.Request, taskID string) {
+	switch r.Method {
+	case http.MethodGet:
+		h.exportTaskMarkdown(w, r, taskID)
+	case http.MethodHead:
+		h.exportTaskMarkdown(headResponseWriter{w}, r, taskID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodGet)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// exportTaskMarkdown renders one task as a Markdown document
+// @Summary Export task as Markdown
+// @Description Download a task with its comments (oldest first) and its links grouped by type as a Markdown file, for handing the task off to someone else
+// @Tags tasks
+// @Produce text/markdown
+// @Param id path string true "Task ID" format(uuid)
+// @Success 200 {string} string "Markdown document"
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/export.md [get]
+func (h *TaskHandler) exportTaskMarkdown(w http.ResponseWriter, r *http.Request, taskID string) {
+	log := logger.FromContext(r.Context())
+
+	task, err := h.storage.GetTask(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Task not found")
+		} else {
+			log.Error("Failed to get task", "error", err, "task_id", taskID)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get task")
+		}
+		return
+	}
+
+	links, err := h.storage.GetTaskLinks(r.Context(), taskID)
+	if err != nil {
+		log.Error("Failed to get task links for export", "error", err, "task_id", taskID)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to export task")
+		return
+	}
+	comments, err := h.storage.GetTaskComments(r.Context(), taskID)
+	if err != nil {
+		log.Error("Failed to get task comments for export", "error", err, "task_id", taskID)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to export task")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+markdownFilename(task)+`"`)
+	if err := writeTaskMarkdown(w, task, links, comments); err != nil {
+		log.Error("Failed to write task Markdown", "error", err, "task_id", taskID)
+	}
+}
+
+// markdownFilename names the export after the Jira ID, falling back to the
+// task ID for tasks without one
+func markdownFilename(task *models.Task) string {
+	name := task.JiraID
+	if models.IsNoJira(name) {
+		name = "task-" + task.ID
+	}
+	return unsafeFilenameChars.ReplaceAllString(name, "_") + ".md"
+}
+
+func writeTaskMarkdown(w io.Writer, task *models.Task, links []*models.Link, comments []*models.Comment) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", task.Title)
+	if !models.IsNoJira(task.JiraID) {
+		fmt.Fprintf(&b, "- **Jira:** %s\n", task.JiraID)
+	}
+	fmt.Fprintf(&b, "- **Status:** %s\n", task.Status)
+	fmt.Fprintf(&b, "- **Priority:** %s\n", task.Priority)
+	if len(task.Tags) > 0 {
+		fmt.Fprintf(&b, "- **Tags:** %s\n", strings.Join(task.Tags, ", "))
+	}
+	fmt.Fprintf(&b, "- **Created:** %s\n", task.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Updated:** %s\n", task.UpdatedAt.Format(time.RFC3339))
+
+	if len(task.Blockers) > 0 {
+		b.WriteString("\n## Blockers\n\n")
+		for _, blocker := range task.Blockers {
+			fmt.Fprintf(&b, "- %s\n", blocker)
+		}
+	}
+
+	if len(links) > 0 {
+		b.WriteString("\n## Links\n")
+		for _, section := range markdownLinkSections {
+			var sectionLinks []*models.Link
+			for _, link := range links {
+				if link.Type == section.linkType {
+					sectionLinks = append(sectionLinks, link)
+				}
+			}
+			if len(sectionLinks) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(&b, "\n### %s\n\n", section.heading)
+			for _, link := range sectionLinks {
+				title := link.Title
+				if title == "" {
+					title = link.URL
+				}
+				fmt.Fprintf(&b, "- [%s](%s)", title, link.URL)
+				if link.Status != "" {
+					fmt.Fprintf(&b, " (%s)", link.Status)
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	if len(comments) > 0 {
+		b.WriteString("\n## Comments\n")
+		for _, comment := range comments {
+			fmt.Fprintf(&b, "\n**%s**\n\n%s\n", comment.CreatedAt.Format(time.RFC3339), comment.Content)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"michishirube/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskHandler_HandleTask_ExportMarkdown(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockWebStorage()
+	handler := NewTaskHandler(store)
+
+	task := &models.Task{JiraID: "OCPBUGS-1234", Title: "Fix etcd backup", Tags: []string{"etcd"}}
+	require.NoError(t, store.CreateTask(ctx, task))
+	require.NoError(t, store.CreateLink(ctx, &models.Link{
+		TaskID: task.ID,
+		Type:   models.PullRequest,
+		URL:    "https://github.com/org/repo/pull/42",
+		Title:  "Backup fix",
+		Status: "open",
+	}))
+	require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: task.ID, Content: "Reproduced on 4.16"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/"+task.ID+"/export.md", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="OCPBUGS-1234.md"`, w.Header().Get("Content-Disposition"))
+
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "# Fix etcd backup\n"))
+	assert.Contains(t, body, "- **Jira:** OCPBUGS-1234")
+	assert.Contains(t, body, "### Pull Requests\n\n- [Backup fix](https://github.com/org/repo/pull/42) (open)")
+	assert.Contains(t, body, "## Comments")
+	assert.Contains(t, body, "Reproduced on 4.16")
+}
+
+func TestTaskHandler_HandleTask_ExportMarkdownNotFound(t *testing.T) {
+	handler := NewTaskHandler(NewMockWebStorage())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/missing/export.md", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assertErrorResponse(t, w, errCodeNotFound, "Task not found")
+}
+
+func TestMarkdownFilename(t *testing.T) {
+	assert.Equal(t, "OCPBUGS-1.md", markdownFilename(&models.Task{ID: "abc", JiraID: "OCPBUGS-1"}))
+	assert.Equal(t, "task-abc.md", markdownFilename(&models.Task{ID: "abc", JiraID: models.DefaultNoJira}))
+	assert.Equal(t, "a_b.md", markdownFilename(&models.Task{ID: "abc", JiraID: `a"/b`}))
+}
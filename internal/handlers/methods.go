@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// headResponseWriter discards the body so HEAD requests can reuse the GET
+// handler and still get its status and headers
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// writeOptions answers an OPTIONS request with the methods the route supports
+func writeOptions(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"michishirube/internal/logger"
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// wantsNDJSON reports whether the client asked for newline-delimited JSON
+func wantsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ndjsonContentType)
+}
+
+// streamTasksNDJSON writes one task JSON object per line straight from the
+// storage iteration, so large lists don't have to fit in memory. Once the
+// first line is out the status can't change, so later failures are only logged.
+func (h *TaskHandler) streamTasksNDJSON(w http.ResponseWriter, r *http.Request, filters storage.TaskFilters) {
+	log := logger.FromContext(r.Context())
+
+	enc := json.NewEncoder(w)
+	count := 0
+	err := h.storage.StreamTasks(r.Context(), filters, func(task *models.Task) error {
+		if count == 0 {
+			w.Header().Set("Content-Type", ndjsonContentType)
+		}
+		count++
+		return enc.Encode(task)
+	})
+	if err != nil {
+		log.Error("Failed to stream tasks", "error", err, "streamed", count)
+		if count == 0 {
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to list tasks")
+		}
+		return
+	}
+
+	if count == 0 {
+		w.Header().Set("Content-Type", ndjsonContentType)
+		w.WriteHeader(http.StatusOK)
+	}
+	log.Debug("Streamed tasks", "count", count)
+}
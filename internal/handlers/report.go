@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"michishirube/internal/logger"
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+)
+
+// ReportFilters narrows what a status report covers
+type ReportFilters struct {
+	StaleDays int // In-progress tasks not updated for this many days are stale; 0 uses the default of 5
+}
+
+// ReportService classifies tasks into the status report sections. The JSON
+// and Markdown reports and the scheduled report file all build on it.
+type ReportService struct {
+	storage storage.Storage
+	now     func() time.Time
+}
+
+// NewReportService creates a report service over the given storage
+func NewReportService(storage storage.Storage) *ReportService {
+	return &ReportService{storage: storage, now: time.Now}
+}
+
+// Generate builds a report over the non-archived tasks. In progress tasks are
+// both worked on and next up, done tasks are worked on, new tasks are next up
+// and blocked tasks are blockers. Next up is ordered by priority and stale
+// in-progress tasks are also listed, oldest first.
+func (s *ReportService) Generate(ctx context.Context, filters ReportFilters) (*models.ReportResponse, error) {
+	allTasks, err := s.storage.ListTasks(ctx, storage.TaskFilters{IncludeArchived: false})
+	if err != nil {
+		return nil, err
+	}
+
+	staleDays := filters.StaleDays
+	if staleDays <= 0 {
+		staleDays = defaultStaleDays
+	}
+	now := s.now()
+	staleBefore := now.AddDate(0, 0, -staleDays)
+
+	report := &models.ReportResponse{
+		GeneratedAt: now,
+		WorkingOn:   []*models.TaskWithDetails{},
+		NextUp:      []*models.TaskWithDetails{},
+		Blockers:    []*models.TaskWithDetails{},
+		Stale:       []*models.TaskWithDetails{},
+		Summary: &models.ReportSummary{
+			ByStatus:   map[models.Status]int{},
+			ByPriority: map[models.Priority]int{},
+		},
+	}
+
+	for _, task := range allTasks {
+		if task == nil {
+			continue
+		}
+
+		report.Summary.Total++
+		report.Summary.ByStatus[task.Status]++
+		report.Summary.ByPriority[task.Priority]++
+
+		links, _ := s.storage.GetTaskLinks(ctx, task.ID)
+		if links == nil {
+			links = []*models.Link{}
+		}
+		entry := &models.TaskWithDetails{Task: task, Links: links, Comments: []*models.Comment{}}
+
+		switch task.Status {
+		case models.InProgress:
+			// All in_progress tasks go to both working_on and next_up
+			report.WorkingOn = append(report.WorkingOn, entry)
+			report.NextUp = append(report.NextUp, entry)
+			if task.UpdatedAt.Before(staleBefore) {
+				report.Stale = append(report.Stale, entry)
+			}
+
+		case models.Done:
+			// All completed tasks go to working_on
+			report.WorkingOn = append(report.WorkingOn, entry)
+
+		case models.New:
+			// All new tasks go to next_up, ordered by priority
+			report.NextUp = append(report.NextUp, entry)
+
+		case models.Blocked:
+			// All blocked tasks
+			report.Blockers = append(report.Blockers, entry)
+		}
+	}
+
+	// Sort next_up by priority, most urgent first
+	sort.SliceStable(report.NextUp, func(i, j int) bool {
+		return report.NextUp[i].Priority.Order() < report.NextUp[j].Priority.Order()
+	})
+
+	// Oldest first, so the longest-stalled task leads the section
+	sort.SliceStable(report.Stale, func(i, j int) bool {
+		return report.Stale[i].UpdatedAt.Before(report.Stale[j].UpdatedAt)
+	})
+
+	return report, nil
+}
+
+// reportMarkdownSections lists the report sections in the order they appear
+// in the Markdown report
+var reportMarkdownSections = []struct {
+	heading string
+	tasks   func(*models.ReportResponse) []*models.TaskWithDetails
+}{
+	{"Working on", func(r *models.ReportResponse) []*models.TaskWithDetails { return r.WorkingOn }},
+	{"Next up", func(r *models.ReportResponse) []*models.TaskWithDetails { return r.NextUp }},
+	{"Blockers", func(r *models.ReportResponse) []*models.TaskWithDetails { return r.Blockers }},
+	{"Stale", func(r *models.ReportResponse) []*models.TaskWithDetails { return r.Stale }},
+}
+
+// writeReportMarkdown renders a report as a Markdown document
+func writeReportMarkdown(w io.Writer, report *models.ReportResponse) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Status report %s\n\n", report.GeneratedAt.Format("2006-01-02"))
+	fmt.Fprintf(&b, "_Generated %s_\n", report.GeneratedAt.Format(time.RFC3339))
+
+	b.WriteString("\n## Summary\n\n")
+	fmt.Fprintf(&b, "- **Total:** %d\n", report.Summary.Total)
+	for _, status := range []models.Status{models.New, models.InProgress, models.Blocked, models.Done} {
+		fmt.Fprintf(&b, "- **%s:** %d\n", status, report.Summary.ByStatus[status])
+	}
+
+	for _, section := range reportMarkdownSections {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.heading)
+		tasks := section.tasks(report)
+		if len(tasks) == 0 {
+			b.WriteString("_Nothing here._\n")
+			continue
+		}
+		for _, task := range tasks {
+			if models.IsNoJira(task.JiraID) {
+				fmt.Fprintf(&b, "- %s (%s, %s)\n", task.Title, task.Priority, task.Status)
+			} else {
+				fmt.Fprintf(&b, "- [%s] %s (%s, %s)\n", task.JiraID, task.Title, task.Priority, task.Status)
+			}
+			if task.Status == models.Blocked {
+				for _, blocker := range task.Blockers {
+					fmt.Fprintf(&b, "  - Blocked by: %s\n", blocker)
+				}
+			}
+			for _, link := range task.Links {
+				title := link.Title
+				if title == "" {
+					title = link.URL
+				}
+				fmt.Fprintf(&b, "  - [%s](%s)\n", title, link.URL)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// reportFiltersFromQuery reads the report filters from the query string,
+// ignoring values that aren't positive numbers
+func reportFiltersFromQuery(r *http.Request) ReportFilters {
+	var filters ReportFilters
+	if days, err := strconv.Atoi(r.URL.Query().Get("stale_days")); err == nil && days > 0 {
+		filters.StaleDays = days
+	}
+	return filters
+}
+
+// HandleReportMarkdown serves the status report as a Markdown document
+func (h *TaskHandler) HandleReportMarkdown(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.generateReportMarkdown(w, r)
+	case http.MethodHead:
+		h.generateReportMarkdown(headResponseWriter{w}, r)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodGet)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// generateReportMarkdown renders the status report as Markdown
+// @Summary Generate status report as Markdown
+// @Description Render the status report (summary, working on, next up, blockers and stale) as a Markdown document, the same one written to report_output_path on a schedule
+// @Tags report
+// @Produce text/markdown
+// @Param stale_days query int false "Days without updates before an in_progress task is stale" default(5)
+// @Success 200 {string} string "Markdown document"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /report.md [get]
+func (h *TaskHandler) generateReportMarkdown(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	report, err := NewReportService(h.storage).Generate(r.Context(), reportFiltersFromQuery(r))
+	if err != nil {
+		log.Error("Failed to get tasks for report", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to generate report")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	if err := writeReportMarkdown(w, report); err != nil {
+		log.Error("Failed to write report Markdown", "error", err)
+	}
+}
+
+// WriteFile renders the Markdown status report and writes it to path. The
+// file is replaced atomically, so readers never see a partial report.
+func (s *ReportService) WriteFile(ctx context.Context, path string) error {
+	report, err := s.Generate(ctx, ReportFilters{})
+	if err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".report-*.md")
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := writeReportMarkdown(tmp, report); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace report file: %w", err)
+	}
+	return nil
+}
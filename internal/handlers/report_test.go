@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"michishirube/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportTitles lists the titles of a report section, in order
+func reportTitles(tasks []*models.TaskWithDetails) []string {
+	titles := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+func TestReportService_Generate_Classification(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockWebStorage()
+	now := time.Now()
+
+	for _, task := range []*models.Task{
+		{Title: "New", Status: models.New, Priority: models.Normal},
+		{Title: "In progress", Status: models.InProgress, Priority: models.Normal},
+		{Title: "Blocked", Status: models.Blocked, Priority: models.Normal},
+		{Title: "Done", Status: models.Done, Priority: models.Normal},
+		{Title: "Archived", Status: models.Archived, Priority: models.Normal},
+	} {
+		task.UpdatedAt = now
+		require.NoError(t, store.CreateTask(ctx, task))
+	}
+
+	report, err := NewReportService(store).Generate(ctx, ReportFilters{})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"In progress", "Done"}, reportTitles(report.WorkingOn))
+	assert.ElementsMatch(t, []string{"New", "In progress"}, reportTitles(report.NextUp))
+	assert.Equal(t, []string{"Blocked"}, reportTitles(report.Blockers))
+	assert.Empty(t, report.Stale)
+
+	assert.Equal(t, 4, report.Summary.Total, "archived tasks are left out")
+	assert.Equal(t, 1, report.Summary.ByStatus[models.InProgress])
+	assert.Equal(t, 4, report.Summary.ByPriority[models.Normal])
+}
+
+func TestReportService_Generate_PrioritySort(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockWebStorage()
+
+	for _, task := range []*models.Task{
+		{Title: "Minor", Status: models.New, Priority: models.Minor},
+		{Title: "Normal", Status: models.InProgress, Priority: models.Normal},
+		{Title: "Critical", Status: models.New, Priority: models.Critical},
+		{Title: "High", Status: models.New, Priority: models.High},
+	} {
+		require.NoError(t, store.CreateTask(ctx, task))
+	}
+
+	report, err := NewReportService(store).Generate(ctx, ReportFilters{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Critical", "High", "Normal", "Minor"}, reportTitles(report.NextUp))
+
+	// Teams with a reversed scale can configure the order
+	require.NoError(t, models.Configure(models.Limits{PriorityOrder: []models.Priority{models.Minor, models.Normal, models.High, models.Critical}}))
+	defer func() { _ = models.Configure(models.Limits{}) }()
+
+	report, err = NewReportService(store).Generate(ctx, ReportFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Minor", "Normal", "High", "Critical"}, reportTitles(report.NextUp))
+}
+
+func TestReportService_Generate_Stale(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockWebStorage()
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for _, task := range []*models.Task{
+		{Title: "Fresh", Status: models.InProgress, UpdatedAt: now.AddDate(0, 0, -1)},
+		{Title: "Old", Status: models.InProgress, UpdatedAt: now.AddDate(0, 0, -6)},
+		{Title: "Oldest", Status: models.InProgress, UpdatedAt: now.AddDate(0, 0, -20)},
+		{Title: "Old but new", Status: models.New, UpdatedAt: now.AddDate(0, 0, -20)},
+	} {
+		task.Priority = models.Normal
+		require.NoError(t, store.CreateTask(ctx, task))
+	}
+
+	service := NewReportService(store)
+	service.now = func() time.Time { return now }
+
+	report, err := service.Generate(ctx, ReportFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Oldest", "Old"}, reportTitles(report.Stale))
+	assert.Equal(t, now, report.GeneratedAt)
+
+	report, err = service.Generate(ctx, ReportFilters{StaleDays: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Oldest"}, reportTitles(report.Stale))
+}
+
+func TestReportService_WriteFile(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockWebStorage()
+
+	working := &models.Task{JiraID: "OCPBUGS-1", Title: "Fix etcd backup", Status: models.InProgress, Priority: models.High}
+	next := &models.Task{Title: "Write runbook", Status: models.New, Priority: models.Critical}
+	blocked := &models.Task{JiraID: "OCPBUGS-2", Title: "Upgrade cluster", Status: models.Blocked, Priority: models.Normal, Blockers: []string{"Waiting on infra"}}
+	for _, task := range []*models.Task{working, next, blocked} {
+		task.UpdatedAt = time.Now()
+		require.NoError(t, store.CreateTask(ctx, task))
+	}
+	require.NoError(t, store.CreateLink(ctx, &models.Link{
+		TaskID: working.ID,
+		Type:   models.PullRequest,
+		URL:    "https://github.com/org/repo/pull/42",
+		Title:  "Backup fix",
+	}))
+
+	path := filepath.Join(t.TempDir(), "report.md")
+	require.NoError(t, NewReportService(store).WriteFile(ctx, path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	body := string(data)
+
+	assert.True(t, strings.HasPrefix(body, "# Status report "))
+	for _, heading := range []string{"## Summary", "## Working on", "## Next up", "## Blockers", "## Stale"} {
+		assert.Contains(t, body, "\n"+heading+"\n")
+	}
+	assert.Contains(t, body, "- **Total:** 3")
+	assert.Contains(t, body, "- [OCPBUGS-1] Fix etcd backup (high, in_progress)\n  - [Backup fix](https://github.com/org/repo/pull/42)")
+	assert.Contains(t, body, "- [OCPBUGS-2] Upgrade cluster (normal, blocked)\n  - Blocked by: Waiting on infra")
+
+	// Next up leads with the critical task
+	nextUp := body[strings.Index(body, "## Next up"):strings.Index(body, "## Blockers")]
+	assert.Less(t, strings.Index(nextUp, "Write runbook"), strings.Index(nextUp, "Fix etcd backup"))
+
+	// Nothing is stale, and rewriting replaces the file rather than appending
+	assert.Contains(t, body, "## Stale\n\n_Nothing here._\n")
+	require.NoError(t, NewReportService(store).WriteFile(ctx, path))
+	again, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(again), "# Status report "))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func TestTaskHandler_HandleReportMarkdown(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockWebStorage()
+	handler := NewTaskHandler(store)
+	require.NoError(t, store.CreateTask(ctx, &models.Task{Title: "Write runbook", Status: models.New, Priority: models.Normal}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/report.md", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleReportMarkdown(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "## Next up\n\n- Write runbook (normal, new)")
+
+	w = httptest.NewRecorder()
+	handler.HandleReportMarkdown(w, httptest.NewRequest(http.MethodPost, "/api/report.md", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"michishirube/internal/logger"
+)
+
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+// HandleSuggest handles autocomplete requests
+func (h *TaskHandler) HandleSuggest(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.suggest(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// suggest returns previously used values of a task field
+// @Summary Suggest field values
+// @Description Get the distinct values of a task field used so far, most used first, for autocomplete. Archived tasks count too; the placeholder Jira ID is left out
+// @Tags tasks
+// @Produce json
+// @Param field query string true "Field to suggest values for" Enums(jira_id)
+// @Param prefix query string false "Only values starting with this, ignoring case" example("OCP")
+// @Param limit query int false "Maximum number of suggestions" default(10) minimum(1) maximum(50)
+// @Success 200 {array} string
+// @Failure 400 {object} models.ErrorResponse
+// @Router /suggest [get]
+func (h *TaskHandler) suggest(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	query := r.URL.Query()
+
+	limit := defaultSuggestLimit
+	if n, err := strconv.Atoi(query.Get("limit")); err == nil && n > 0 {
+		limit = min(n, maxSuggestLimit)
+	}
+
+	values, err := h.storage.DistinctValues(r.Context(), query.Get("field"), query.Get("prefix"), limit)
+	if err != nil {
+		if isValidationError(err) {
+			writeValidationError(w, err)
+			return
+		}
+		log.Error("Failed to suggest values", "error", err, "field", query.Get("field"))
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to suggest values")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(values); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"michishirube/internal/models"
+	"michishirube/internal/storage/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskHandler_HandleSuggest(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	for _, jiraID := range []string{"OCPBUGS-1", "OCPBUGS-2", "OCPBUGS-2", "HOSTEDCP-7"} {
+		require.NoError(t, store.CreateTask(ctx, &models.Task{JiraID: jiraID, Title: "Task", Priority: models.Normal, Status: models.New}))
+	}
+	handler := NewTaskHandler(store)
+
+	suggest := func(query string) []string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.HandleSuggest(w, httptest.NewRequest(http.MethodGet, "/api/suggest?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var values []string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &values))
+		return values
+	}
+
+	assert.Equal(t, []string{"OCPBUGS-2", "HOSTEDCP-7", "OCPBUGS-1"}, suggest("field=jira_id"))
+	assert.Equal(t, []string{"OCPBUGS-2", "OCPBUGS-1"}, suggest("field=jira_id&prefix=OCP"))
+	assert.Equal(t, []string{"OCPBUGS-2"}, suggest("field=jira_id&prefix=OCP&limit=1"))
+	assert.Empty(t, suggest("field=jira_id&prefix=NOPE"))
+}
+
+func TestTaskHandler_HandleSuggest_Errors(t *testing.T) {
+	handler := NewTaskHandler(memory.New())
+
+	for _, query := range []string{"field=title", "field=assignee", ""} {
+		w := httptest.NewRecorder()
+		handler.HandleSuggest(w, httptest.NewRequest(http.MethodGet, "/api/suggest?"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assertValidationErrorResponse(t, w, "does not support suggestions")
+	}
+
+	w := httptest.NewRecorder()
+	handler.HandleSuggest(w, httptest.NewRequest(http.MethodPost, "/api/suggest?field=jira_id", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
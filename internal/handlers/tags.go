@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"michishirube/internal/logger"
+	"michishirube/internal/models"
+)
+
+// HandleTags handles tag listing requests
+func (h *TaskHandler) HandleTags(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listTags(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// listTags returns every tag in use with its task count
+// @Summary List tags
+// @Description Get every tag used by a non-archived task, mapped to the number of tasks using it
+// @Tags tags
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tags [get]
+func (h *TaskHandler) listTags(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	tags, err := h.storage.ListTags(r.Context())
+	if err != nil {
+		log.Error("Failed to list tags", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to list tags")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tags); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// HandleRenameTag handles tag rename requests
+func (h *TaskHandler) HandleRenameTag(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.renameTag(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// renameTag rewrites a tag on every task
+// @Summary Rename a tag
+// @Description Replace a tag on every task, including archived ones. Tasks that already have the new tag keep a single copy, so this also merges two tags
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param rename body models.RenameTagRequest true "Tag to rename"
+// @Success 200 {object} models.RenameTagResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tags/rename [post]
+func (h *TaskHandler) renameTag(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	var req models.RenameTagRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		log.Error("Failed to decode rename tag JSON", "error", err)
+		writeDecodeError(w, err, "Invalid JSON")
+		return
+	}
+
+	affected, err := h.storage.RenameTag(r.Context(), req.From, req.To)
+	if err != nil {
+		if isValidationError(err) {
+			writeValidationError(w, err)
+			return
+		}
+		log.Error("Failed to rename tag", "error", err, "from", req.From, "to", req.To)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to rename tag")
+		return
+	}
+
+	log.Info("Tag renamed", "from", req.From, "to", req.To, "affected", affected)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(models.RenameTagResponse{Affected: affected}); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
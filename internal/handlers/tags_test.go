@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"michishirube/internal/handlers/mocks"
+	"michishirube/internal/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskHandler_HandleTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().
+		ListTags(gomock.Any()).
+		Return(map[string]int{"k8s": 3, "network": 1}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tags", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTags(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]int{"k8s": 3, "network": 1}, response)
+}
+
+func TestTaskHandler_HandleTags_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().ListTags(gomock.Any()).Return(nil, errors.New("database locked")).Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tags", nil)
+	w := httptest.NewRecorder()
+	handler.HandleTags(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/tags", nil)
+	w = httptest.NewRecorder()
+	handler.HandleTags(w, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestTaskHandler_HandleRenameTag(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		affected       int
+		storageErr     error
+		expectStorage  bool
+		expectedStatus int
+	}{
+		{
+			name:           "renamed",
+			body:           `{"from":"k8s","to":"kubernetes"}`,
+			affected:       2,
+			expectStorage:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{"from":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "validation error",
+			body:           `{"from":"k8s","to":"k8s"}`,
+			storageErr:     &models.ValidationError{Field: "to", Message: "cannot rename a tag to itself"},
+			expectStorage:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "storage error",
+			body:           `{"from":"k8s","to":"kubernetes"}`,
+			storageErr:     errors.New("database locked"),
+			expectStorage:  true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			handler := NewTaskHandler(mockStorage)
+
+			if tt.expectStorage {
+				mockStorage.EXPECT().
+					RenameTag(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(tt.affected, tt.storageErr).
+					Times(1)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tags/rename", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.HandleRenameTag(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.RenameTagResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.affected, response.Affected)
+			}
+		})
+	}
+}
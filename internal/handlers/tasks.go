@@ -1,34 +1,110 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"michishirube/internal/logger"
 	"michishirube/internal/models"
 	"michishirube/internal/storage"
 )
 
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+
+	// relatedTasksLimit caps the related tasks returned with a task
+	relatedTasksLimit = 5
+
+	// defaultStaleDays is how long an in_progress task may go untouched
+	// before the report flags it as stale
+	defaultStaleDays = 5
+
+	// customFieldParamPrefix marks task list parameters that filter on a
+	// custom field, as in ?custom.sprint=12
+	customFieldParamPrefix = "custom."
+
+	// DefaultMaxBodyBytes caps JSON request bodies unless WithMaxBodyBytes overrides it
+	DefaultMaxBodyBytes int64 = 1 << 20
+)
+
 type TaskHandler struct {
-	storage storage.Storage
+	storage         storage.Storage
+	defaultPageSize int
+	maxPageSize     int
+	maxBodyBytes    int64
+	strictJSON      bool
+}
+
+// TaskHandlerOption customizes a TaskHandler
+type TaskHandlerOption func(*TaskHandler)
+
+// WithPageSizes sets the limit applied when a list request gives none and
+// the largest limit a request may ask for. Non-positive values keep the defaults.
+func WithPageSizes(defaultSize, maxSize int) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		if defaultSize > 0 {
+			h.defaultPageSize = defaultSize
+		}
+		if maxSize > 0 {
+			h.maxPageSize = maxSize
+		}
+	}
+}
+
+// WithMaxBodyBytes sets the largest JSON request body the create and update
+// endpoints will read. Non-positive values keep the default.
+func WithMaxBodyBytes(n int64) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		if n > 0 {
+			h.maxBodyBytes = n
+		}
+	}
 }
 
-func NewTaskHandler(storage storage.Storage) *TaskHandler {
-	return &TaskHandler{storage: storage}
+// WithStrictJSON makes the create and update endpoints reject bodies with
+// fields they don't know instead of silently ignoring them
+func WithStrictJSON(strict bool) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.strictJSON = strict
+	}
+}
+
+func NewTaskHandler(storage storage.Storage, opts ...TaskHandlerOption) *TaskHandler {
+	h := &TaskHandler{
+		storage:         storage,
+		defaultPageSize: DefaultPageSize,
+		maxPageSize:     MaxPageSize,
+		maxBodyBytes:    DefaultMaxBodyBytes,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.defaultPageSize > h.maxPageSize {
+		h.defaultPageSize = h.maxPageSize
+	}
+	return h
 }
 
 func (h *TaskHandler) HandleTasks(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		h.listTasks(w, r)
+	case http.MethodHead:
+		h.listTasks(headResponseWriter{w}, r)
 	case http.MethodPost:
 		h.createTask(w, r)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodGet, http.MethodPost)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 	}
 }
 
@@ -36,23 +112,58 @@ func (h *TaskHandler) HandleTask(w http.ResponseWriter, r *http.Request) {
 	// Extract task ID from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/api/tasks/")
 	if path == "" {
-		http.Error(w, "Task ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, errCodeBadRequest, "Task ID required")
 		return
 	}
 
-	taskID := strings.Split(path, "/")[0]
+	parts := strings.Split(path, "/")
+	taskID := parts[0]
+
+	if len(parts) > 1 && parts[1] != "" {
+		switch parts[1] {
+		case "links":
+			h.handleTaskLinks(w, r, taskID)
+		case "activity":
+			h.handleTaskActivity(w, r, taskID)
+		case "comments":
+			if len(parts) > 2 && parts[2] == "bulk" {
+				h.handleTaskCommentsBulk(w, r, taskID)
+				return
+			}
+			h.handleTaskComments(w, r, taskID)
+		case "archive":
+			h.handleTaskAction(w, r, taskID, h.archiveTask)
+		case "unarchive":
+			h.handleTaskAction(w, r, taskID, h.unarchiveTask)
+		case "star":
+			h.handleTaskAction(w, r, taskID, h.starTask)
+		case "unstar":
+			h.handleTaskAction(w, r, taskID, h.unstarTask)
+		case "duplicate":
+			h.handleTaskAction(w, r, taskID, h.duplicateTask)
+		case "export.md":
+			h.handleTaskMarkdown(w, r, taskID)
+		default:
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Not found")
+		}
+		return
+	}
 
 	switch r.Method {
 	case http.MethodGet:
-		h.getTask(w, taskID)
+		h.getTask(w, r, taskID)
+	case http.MethodHead:
+		h.getTask(headResponseWriter{w}, r, taskID)
 	case http.MethodPut:
 		h.updateTask(w, r, taskID)
 	case http.MethodPatch:
 		h.patchTask(w, r, taskID)
 	case http.MethodDelete:
-		h.deleteTask(w, taskID)
+		h.deleteTask(w, r, taskID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 	}
 }
 
@@ -62,16 +173,32 @@ func (h *TaskHandler) HandleTask(w http.ResponseWriter, r *http.Request) {
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
 // @Param status query string false "Filter by status (comma-separated)" example("new,in_progress")
 // @Param priority query string false "Filter by priority (comma-separated)" example("high,critical")
 // @Param tags query string false "Filter by tags (comma-separated)" example("k8s,memory")
+// @Param starred query boolean false "Only starred (true) or unstarred (false) tasks"
+// @Param custom.{key} query string false "Only tasks whose custom field {key} has exactly this value, e.g. custom.sprint=12; repeat for several fields"
 // @Param include_archived query boolean false "Include archived tasks" default(false)
 // @Param limit query int false "Maximum number of results" default(50) minimum(1) maximum(200)
 // @Param offset query int false "Number of results to skip" default(0) minimum(0)
+// @Param after query string false "Cursor from a previous response's next_cursor; faster than offset for deep pages"
+// @Param created_after query string false "Only tasks created at or after this RFC3339 time" example("2024-01-08T00:00:00Z")
+// @Param created_before query string false "Only tasks created before this RFC3339 time" example("2024-01-15T00:00:00Z")
+// @Param updated_after query string false "Only tasks updated at or after this RFC3339 time"
+// @Param updated_before query string false "Only tasks updated before this RFC3339 time"
+// @Param format query string false "Response format; csv streams a spreadsheet instead of JSON" Enums(json, csv)
+// @Param Accept header string false "application/x-ndjson streams every matching task, one JSON object per line, unless limit is given"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.TaskListResponse
+// @Header 200 {integer} X-Total-Count "Number of tasks matching the filters across all pages"
+// @Header 200 {string} Link "URLs of the next and previous pages (rel=next, rel=prev)"
+// @Success 304 "Not modified"
 // @Failure 400 {object} models.ErrorResponse
 // @Router /tasks [get]
 func (h *TaskHandler) listTasks(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
 	filters := storage.TaskFilters{}
 	query := r.URL.Query()
 
@@ -82,6 +209,18 @@ func (h *TaskHandler) listTasks(w http.ResponseWriter, r *http.Request) {
 		}
 		value := values[0]
 
+		if key, ok := strings.CutPrefix(param, customFieldParamPrefix); ok {
+			if !models.IsValidCustomFieldKey(key) {
+				writeError(w, http.StatusBadRequest, errCodeBadRequest, fmt.Sprintf("Invalid custom field %q", key))
+				return
+			}
+			if filters.CustomFields == nil {
+				filters.CustomFields = make(map[string]string)
+			}
+			filters.CustomFields[key] = value
+			continue
+		}
+
 		switch param {
 		case "status":
 			statusStrings := strings.Split(value, ",")
@@ -94,7 +233,16 @@ func (h *TaskHandler) listTasks(w http.ResponseWriter, r *http.Request) {
 				filters.Priority = append(filters.Priority, models.Priority(strings.TrimSpace(p)))
 			}
 		case "tags":
-			filters.Tags = strings.Split(value, ",")
+			filters.Tags = models.ParseTags(value)
+		case "starred":
+			switch value {
+			case "true", "1":
+				starred := true
+				filters.Starred = &starred
+			case "false", "0":
+				starred := false
+				filters.Starred = &starred
+			}
 		case "include_archived":
 			switch value {
 			case "true", "1":
@@ -108,85 +256,246 @@ func (h *TaskHandler) listTasks(w http.ResponseWriter, r *http.Request) {
 			if offset, err := strconv.Atoi(value); err == nil && offset >= 0 {
 				filters.Offset = offset
 			}
+		case "after":
+			cursor, err := storage.DecodeTaskCursor(value)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, errCodeBadRequest, "Invalid cursor")
+				return
+			}
+			filters.After = cursor
+		case "created_after":
+			filters.CreatedAfter = parseTimeParam(log, param, value)
+		case "created_before":
+			filters.CreatedBefore = parseTimeParam(log, param, value)
+		case "updated_after":
+			filters.UpdatedAfter = parseTimeParam(log, param, value)
+		case "updated_before":
+			filters.UpdatedBefore = parseTimeParam(log, param, value)
 		}
 	}
 
-	tasks, err := h.storage.ListTasks(filters)
+	// Streams hold no more than one task in memory, so they are only paged
+	// when the client asks for it
+	if query.Get("format") == "" && wantsNDJSON(r) {
+		h.streamTasksNDJSON(w, r, filters)
+		return
+	}
+
+	filters.Limit = h.pageSize(filters.Limit)
+
+	tasks, err := h.storage.ListTasks(r.Context(), filters)
+	if err != nil {
+		log.Error("Failed to list tasks", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to list tasks")
+		return
+	}
+
+	total, err := h.storage.CountTasks(r.Context(), filters)
+	if err != nil {
+		log.Error("Failed to count tasks", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to list tasks")
+		return
+	}
+	setPaginationHeaders(w, r, filters, len(tasks), total)
+
+	if query.Get("format") == "csv" {
+		h.writeTasksCSV(w, r, tasks)
+		return
+	}
+
+	items, err := h.taskListItems(r.Context(), tasks)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Error("Failed to count task links and comments", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to list tasks")
 		return
 	}
 
 	response := map[string]interface{}{
-		"tasks":  tasks,
+		"tasks":  items,
 		"total":  len(tasks),
 		"limit":  filters.Limit,
 		"offset": filters.Offset,
 	}
+	// A full page may have more behind it; a short page is the last one
+	if len(tasks) > 0 && len(tasks) == filters.Limit {
+		response["next_cursor"] = storage.CursorFor(tasks[len(tasks)-1]).Encode()
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	writeJSONWithETag(w, r, response)
+}
+
+// setPaginationHeaders sets X-Total-Count and an RFC 8288 Link header with
+// the next and previous offset pages. Cursor requests only get the count,
+// since an offset link would not follow on from a cursor page.
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, filters storage.TaskFilters, pageLen, total int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if filters.After != nil {
 		return
 	}
+
+	pageURL := func(offset int) string {
+		query := r.URL.Query()
+		query.Set("limit", strconv.Itoa(filters.Limit))
+		query.Set("offset", strconv.Itoa(offset))
+		return r.URL.Path + "?" + query.Encode()
+	}
+
+	var links []string
+	if next := filters.Offset + pageLen; pageLen > 0 && next < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(next)))
+	}
+	if filters.Offset > 0 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(max(filters.Offset-filters.Limit, 0))))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// taskListItems attaches link and comment counts to a page of tasks
+func (h *TaskHandler) taskListItems(ctx context.Context, tasks []*models.Task) ([]*models.TaskListItem, error) {
+	items := make([]*models.TaskListItem, len(tasks))
+	if len(tasks) == 0 {
+		return items, nil
+	}
+
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	counts, err := h.storage.CountTaskRelations(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, task := range tasks {
+		items[i] = &models.TaskListItem{
+			Task:         *task,
+			LinkCount:    counts[task.ID].Links,
+			CommentCount: counts[task.ID].Comments,
+		}
+	}
+	return items, nil
+}
+
+// pageSize applies the default limit when none was requested and caps
+// larger requests at the configured maximum
+func (h *TaskHandler) pageSize(requested int) int {
+	switch {
+	case requested <= 0:
+		return h.defaultPageSize
+	case requested > h.maxPageSize:
+		return h.maxPageSize
+	default:
+		return requested
+	}
+}
+
+// parseTimeParam parses an RFC3339 query value, returning the zero time
+// (no filter) and logging a warning when it is malformed
+func parseTimeParam(log *slog.Logger, param, value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Warn("Ignoring invalid time filter", "param", param, "value", value, "error", err)
+		return time.Time{}
+	}
+	return t
+}
+
+// createTaskPayload is a task plus the links to create alongside it
+type createTaskPayload struct {
+	models.Task
+	Links []*models.Link `json:"links"`
 }
 
 // createTask creates a new task
 // @Summary Create a new task
-// @Description Create a new task with the provided information
+// @Description Create a new task with the provided information. Links listed in the body are created in the same transaction and echoed back in the response; if any link is invalid nothing is stored
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param task body models.CreateTaskRequest true "Task to create"
 // @Success 201 {object} models.Task
+// @Header 201 {string} Location "URL of the created task"
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
 // @Router /tasks [post]
 func (h *TaskHandler) createTask(w http.ResponseWriter, r *http.Request) {
-	var task models.Task
-	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	log := logger.FromContext(r.Context())
+
+	var payload createTaskPayload
+	if err := h.decodeJSON(w, r, &payload); err != nil {
+		writeDecodeError(w, err, "Invalid JSON")
 		return
 	}
+	task := &payload.Task
+
+	var (
+		response interface{} = task
+		err      error
+	)
+	if len(payload.Links) == 0 {
+		err = h.storage.CreateTask(r.Context(), task)
+	} else {
+		for _, link := range payload.Links {
+			applyLinkDefaults(link)
+		}
+		err = h.storage.CreateTaskWithLinks(r.Context(), task, payload.Links)
+		response = struct {
+			*models.Task
+			Links []*models.Link `json:"links"`
+		}{task, payload.Links}
+	}
 
-	err := h.storage.CreateTask(&task)
 	switch {
 	case err == nil:
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/tasks/"+task.ID)
 		w.WriteHeader(http.StatusCreated)
-		if err := json.NewEncoder(w).Encode(task); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
 			return
 		}
 	case isValidationError(err):
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeValidationError(w, err)
 	default:
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Error("Failed to create task", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create task")
 	}
 }
 
 // getTask retrieves a specific task by ID
 // @Summary Get task by ID
-// @Description Retrieve a specific task with its links and comments
+// @Description Retrieve a specific task with its links, comments and up to 5 related tasks (sharing tags)
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path string true "Task ID" format(uuid)
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.TaskWithDetails
+// @Success 304 "Not modified"
 // @Failure 404 {object} models.ErrorResponse
 // @Router /tasks/{id} [get]
-func (h *TaskHandler) getTask(w http.ResponseWriter, taskID string) {
-	task, err := h.storage.GetTask(taskID)
+func (h *TaskHandler) getTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	log := logger.FromContext(r.Context())
+
+	task, err := h.storage.GetTask(r.Context(), taskID)
 	switch {
 	case err == nil:
 		// Get related links and comments
-		links, _ := h.storage.GetTaskLinks(taskID)
+		links, _ := h.storage.GetTaskLinks(r.Context(), taskID)
 		if links == nil {
 			links = []*models.Link{}
 		}
-		comments, _ := h.storage.GetTaskComments(taskID)
+		comments, _ := h.storage.GetTaskComments(r.Context(), taskID)
 		if comments == nil {
 			comments = []*models.Comment{}
 		}
+		related, _ := h.storage.GetRelatedTasks(r.Context(), taskID, relatedTasksLimit)
+		if related == nil {
+			related = []*models.Task{}
+		}
 
 		response := map[string]interface{}{
 			"id":         task.ID,
@@ -196,21 +505,23 @@ func (h *TaskHandler) getTask(w http.ResponseWriter, taskID string) {
 			"status":     task.Status,
 			"tags":       task.Tags,
 			"blockers":   task.Blockers,
+			"starred":    task.Starred,
 			"created_at": task.CreatedAt,
 			"updated_at": task.UpdatedAt,
 			"links":      links,
 			"comments":   comments,
+			"related":    related,
 		}
-
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-			return
+		if len(task.CustomFields) > 0 {
+			response["custom_fields"] = task.CustomFields
 		}
-	case strings.Contains(err.Error(), "not found"):
-		http.Error(w, "Task not found", http.StatusNotFound)
+
+		writeJSONWithETag(w, r, response)
+	case errors.Is(err, storage.ErrNotFound):
+		writeError(w, http.StatusNotFound, errCodeNotFound, "Task not found")
 	default:
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Error("Failed to get task", "error", err, "task_id", taskID)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get task")
 	}
 }
 
@@ -222,33 +533,49 @@ func (h *TaskHandler) getTask(w http.ResponseWriter, taskID string) {
 // @Produce json
 // @Param id path string true "Task ID" format(uuid)
 // @Param task body models.Task true "Task data"
+// @Param force query boolean false "Skip the status transition check when enforce_transitions is on" default(false)
 // @Success 200 {object} models.Task
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /tasks/{id} [put]
 func (h *TaskHandler) updateTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	log := logger.FromContext(r.Context())
+
 	var task models.Task
-	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := h.decodeJSON(w, r, &task); err != nil {
+		writeDecodeError(w, err, "Invalid JSON")
 		return
 	}
 
 	task.ID = taskID
-	err := h.storage.UpdateTask(&task)
+	err := h.storage.UpdateTask(updateContext(r), &task)
 	switch {
 	case err == nil:
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(task); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
 			return
 		}
 	case isValidationError(err):
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeValidationError(w, err)
 	default:
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Error("Failed to update task", "error", err, "task_id", taskID)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to update task")
 	}
 }
 
+// updateContext returns the context for a task update, letting ?force=true
+// bypass the status transition check
+func updateContext(r *http.Request) context.Context {
+	switch r.URL.Query().Get("force") {
+	case "true", "1":
+		return storage.WithForcedTransition(r.Context())
+	}
+	return r.Context()
+}
+
 // patchTask partially updates a task
 // @Summary Update task fields
 // @Description Partially update a task with the provided fields (PATCH)
@@ -257,8 +584,11 @@ func (h *TaskHandler) updateTask(w http.ResponseWriter, r *http.Request, taskID
 // @Produce json
 // @Param id path string true "Task ID" format(uuid)
 // @Param task body models.PatchTaskRequest true "Fields to update"
+// @Param force query boolean false "Skip the status transition check when enforce_transitions is on" default(false)
 // @Success 200 {object} models.Task
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /tasks/{id} [patch]
 func (h *TaskHandler) patchTask(w http.ResponseWriter, r *http.Request, taskID string) {
@@ -266,27 +596,37 @@ func (h *TaskHandler) patchTask(w http.ResponseWriter, r *http.Request, taskID s
 	log.Debug("Patching task", "task_id", taskID)
 
 	// Get the existing task first
-	existingTask, err := h.storage.GetTask(taskID)
+	existingTask, err := h.storage.GetTask(r.Context(), taskID)
 	if err != nil {
 		log.Error("Failed to get existing task for patch", "error", err, "task_id", taskID)
-		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, "Task not found", http.StatusNotFound)
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Task not found")
 		} else {
-			http.Error(w, "Failed to get task", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get task")
 		}
 		return
 	}
 
 	// Parse the partial update data
 	var patchData map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&patchData); err != nil {
+	if err := h.decodeJSON(w, r, &patchData); err != nil {
 		log.Error("Failed to decode patch JSON", "error", err, "task_id", taskID)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		writeDecodeError(w, err, "Invalid JSON")
 		return
 	}
 
 	log.Debug("Patch data received", "task_id", taskID, "patch_data", patchData)
 
+	// A map accepts any key, so strict mode checks the keys itself
+	if h.strictJSON {
+		for field := range patchData {
+			if !patchableTaskFields[field] {
+				writeError(w, http.StatusBadRequest, errCodeBadRequest, fmt.Sprintf("Unknown field %q", field))
+				return
+			}
+		}
+	}
+
 	// Apply patches to existing task
 	if status, ok := patchData["status"]; ok {
 		if statusStr, ok := status.(string); ok {
@@ -320,6 +660,31 @@ func (h *TaskHandler) patchTask(w http.ResponseWriter, r *http.Request, taskID s
 		}
 	}
 
+	if starred, ok := patchData["starred"]; ok {
+		if starredBool, ok := starred.(bool); ok {
+			existingTask.Starred = starredBool
+		}
+	}
+
+	if fields, ok := patchData["custom_fields"]; ok {
+		fieldsMap, ok := fields.(map[string]interface{})
+		if !ok {
+			writeFieldError(w, "custom_fields", models.CodeInvalid, "custom_fields must be an object")
+			return
+		}
+		for key, value := range fieldsMap {
+			switch value := value.(type) {
+			case string:
+				existingTask.SetCustomField(key, value)
+			case nil:
+				existingTask.SetCustomField(key, "")
+			default:
+				writeFieldError(w, "custom_fields", models.CodeInvalid, fmt.Sprintf("custom field %q must be a string", key))
+				return
+			}
+		}
+	}
+
 	if blockers, ok := patchData["blockers"]; ok {
 		if blockersArray, ok := blockers.([]interface{}); ok {
 			stringBlockers := make([]string, len(blockersArray))
@@ -333,13 +698,13 @@ func (h *TaskHandler) patchTask(w http.ResponseWriter, r *http.Request, taskID s
 	}
 
 	// Update the task
-	err = h.storage.UpdateTask(existingTask)
+	err = h.storage.UpdateTask(updateContext(r), existingTask)
 	if err != nil {
 		log.Error("Failed to patch task", "error", err, "task_id", taskID)
 		if isValidationError(err) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeValidationError(w, err)
 		} else {
-			http.Error(w, "Failed to update task", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to update task")
 		}
 		return
 	}
@@ -348,196 +713,631 @@ func (h *TaskHandler) patchTask(w http.ResponseWriter, r *http.Request, taskID s
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(existingTask); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
 		return
 	}
 }
 
+// patchableTaskFields are the task fields PATCH applies
+var patchableTaskFields = map[string]bool{
+	"status":        true,
+	"priority":      true,
+	"title":         true,
+	"tags":          true,
+	"blockers":      true,
+	"starred":       true,
+	"custom_fields": true,
+}
+
 // deleteTask removes a task
 // @Summary Delete task
 // @Description Delete a task by ID
 // @Tags tasks
 // @Param id path string true "Task ID" format(uuid)
 // @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
 // @Failure 404 {object} models.ErrorResponse
 // @Router /tasks/{id} [delete]
-func (h *TaskHandler) deleteTask(w http.ResponseWriter, taskID string) {
-	err := h.storage.DeleteTask(taskID)
+func (h *TaskHandler) deleteTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	log := logger.FromContext(r.Context())
+
+	err := h.storage.DeleteTask(r.Context(), taskID)
 	switch err {
 	case nil:
 		w.WriteHeader(http.StatusNoContent)
 	default:
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Error("Failed to delete task", "error", err, "task_id", taskID)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to delete task")
 	}
 }
 
-func isValidationError(err error) bool {
-	var validationErr *models.ValidationError
-	ok := errors.As(err, &validationErr)
-	return ok
-}
-
-// HandleReport generates an automatic status report
-func (h *TaskHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
-	log := logger.FromContext(r.Context())
-	log.Debug("Generating automatic report")
-
+// handleTaskAction routes the archive, unarchive, star, unstar and duplicate shortcuts, which only accept POST
+func (h *TaskHandler) handleTaskAction(w http.ResponseWriter, r *http.Request, taskID string, action func(http.ResponseWriter, *http.Request, string)) {
 	switch r.Method {
-	case http.MethodGet:
-		h.generateReport(w, r)
+	case http.MethodPost:
+		action(w, r, taskID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodPost)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 	}
 }
 
-// generateReport creates automatic status report
-// @Summary Generate status report
-// @Description Generate an automatic status report with working_on, next_up, and blockers sections
-// @Tags report
+// archiveTask moves a task to the archived status
+// @Summary Archive task
+// @Description Set a task's status to archived. Archiving an already archived task is a no-op
+// @Tags tasks
 // @Produce json
-// @Success 200 {object} models.ReportResponse
-// @Failure 500 {object} models.ErrorResponse
-// @Router /report [get]
-func (h *TaskHandler) generateReport(w http.ResponseWriter, r *http.Request) {
-	log := logger.FromContext(r.Context())
+// @Param id path string true "Task ID" format(uuid)
+// @Success 200 {object} models.Task
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/archive [post]
+func (h *TaskHandler) archiveTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	h.setTaskStatus(w, r, taskID, func(task *models.Task) bool {
+		if task.Status == models.Archived {
+			return false
+		}
+		task.Status = models.Archived
+		return true
+	})
+}
 
-	// Get all non-archived tasks
-	allFilters := storage.TaskFilters{
-		IncludeArchived: false,
-	}
+// unarchiveTask restores an archived task to the new status
+// @Summary Unarchive task
+// @Description Restore an archived task to status new. Tasks that are not archived are returned unchanged
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID" format(uuid)
+// @Success 200 {object} models.Task
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/unarchive [post]
+func (h *TaskHandler) unarchiveTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	h.setTaskStatus(w, r, taskID, func(task *models.Task) bool {
+		if task.Status != models.Archived {
+			return false
+		}
+		task.Status = models.New
+		return true
+	})
+}
+
+// setTaskStatus loads a task, lets apply change its status and saves it
+// only when apply reports a change
+func (h *TaskHandler) setTaskStatus(w http.ResponseWriter, r *http.Request, taskID string, apply func(*models.Task) bool) {
+	h.changeTask(w, r, taskID, "Task status changed", func(task *models.Task) []any {
+		previous := task.Status
+		if !apply(task) {
+			return nil
+		}
+		return []any{"from", previous, "to", task.Status}
+	})
+}
+
+// changeTask loads a task and lets apply modify it. apply returns the log
+// attributes describing the change, or nil when nothing changed, in which
+// case the task is returned without being saved
+func (h *TaskHandler) changeTask(w http.ResponseWriter, r *http.Request, taskID, message string, apply func(*models.Task) []any) {
+	log := logger.FromContext(r.Context())
 
-	allTasks, err := h.storage.ListTasks(allFilters)
+	task, err := h.storage.GetTask(r.Context(), taskID)
 	if err != nil {
-		log.Error("Failed to get tasks for report", "error", err)
-		http.Error(w, "Failed to generate report", http.StatusInternalServerError)
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Task not found")
+		} else {
+			log.Error("Failed to get task", "error", err, "task_id", taskID)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get task")
+		}
 		return
 	}
 
-	report := map[string]interface{}{
-		"working_on": []*models.Task{},
-		"next_up":    []*models.Task{},
-		"blockers":   []*models.Task{},
-	}
-
-	// Helper function to get task with links
-	getTaskWithLinks := func(task *models.Task) map[string]interface{} {
-		links, _ := h.storage.GetTaskLinks(task.ID)
-		if links == nil {
-			links = []*models.Link{}
+	if attrs := apply(task); attrs != nil {
+		if err := h.storage.UpdateTask(r.Context(), task); err != nil {
+			log.Error("Failed to update task", "error", err, "task_id", taskID)
+			if isValidationError(err) {
+				writeValidationError(w, err)
+			} else {
+				writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to update task")
+			}
+			return
 		}
+		log.Info(message, append([]any{"task_id", taskID}, attrs...)...)
+	}
 
-		return map[string]interface{}{
-			"id":         task.ID,
-			"jira_id":    task.JiraID,
-			"title":      task.Title,
-			"priority":   task.Priority,
-			"status":     task.Status,
-			"tags":       task.Tags,
-			"blockers":   task.Blockers,
-			"created_at": task.CreatedAt,
-			"updated_at": task.UpdatedAt,
-			"links":      links,
-		}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(task); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
 	}
+}
+
+// starTask marks a task as starred
+// @Summary Star task
+// @Description Star a task so it is listed ahead of unstarred tasks. Starring an already starred task is a no-op
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID" format(uuid)
+// @Success 200 {object} models.Task
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/star [post]
+func (h *TaskHandler) starTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	h.setTaskStarred(w, r, taskID, true)
+}
 
-	workingOn := []map[string]interface{}{}
-	nextUp := []map[string]interface{}{}
-	blockers := []map[string]interface{}{}
+// unstarTask removes a task's star
+// @Summary Unstar task
+// @Description Remove a task's star. Unstarring a task that is not starred is a no-op
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID" format(uuid)
+// @Success 200 {object} models.Task
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/unstar [post]
+func (h *TaskHandler) unstarTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	h.setTaskStarred(w, r, taskID, false)
+}
 
-	for _, task := range allTasks {
-		if task == nil {
-			continue
+func (h *TaskHandler) setTaskStarred(w http.ResponseWriter, r *http.Request, taskID string, starred bool) {
+	h.changeTask(w, r, taskID, "Task star changed", func(task *models.Task) []any {
+		if task.Starred == starred {
+			return nil
 		}
+		task.Starred = starred
+		return []any{"starred", starred}
+	})
+}
 
-		taskWithLinks := getTaskWithLinks(task)
-
-		switch task.Status {
-		case models.InProgress:
-			// All in_progress tasks go to both working_on and next_up
-			workingOn = append(workingOn, taskWithLinks)
-			nextUp = append(nextUp, taskWithLinks)
+// duplicateTask creates a fresh task from an existing one
+// @Summary Duplicate task
+// @Description Create a new task with the source task's title, priority and tags. The copy starts as new without a Jira ID, blockers, comments or history. With links=true the source's links are copied too
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID" format(uuid)
+// @Param links query boolean false "Copy the task's links" default(false)
+// @Param suffix query boolean false "Append (copy) to the title" default(true)
+// @Success 201 {object} models.Task
+// @Header 201 {string} Location "URL of the new task"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/duplicate [post]
+func (h *TaskHandler) duplicateTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	log := logger.FromContext(r.Context())
+	query := r.URL.Query()
 
-		case models.Done:
-			// All completed tasks go to working_on
-			workingOn = append(workingOn, taskWithLinks)
+	source, err := h.storage.GetTask(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Task not found")
+		} else {
+			log.Error("Failed to get task", "error", err, "task_id", taskID)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get task")
+		}
+		return
+	}
 
-		case models.New:
-			// All new tasks go to next_up, ordered by priority
-			nextUp = append(nextUp, taskWithLinks)
+	task := &models.Task{
+		Title:    source.Title,
+		Priority: source.Priority,
+		Status:   models.New,
+		Tags:     append([]string(nil), source.Tags...),
+	}
+	for key, value := range source.CustomFields {
+		task.SetCustomField(key, value)
+	}
+	switch query.Get("suffix") {
+	case "false", "0":
+	default:
+		task.Title += " (copy)"
+	}
 
-		case models.Blocked:
-			// All blocked tasks
-			blockers = append(blockers, taskWithLinks)
+	var (
+		response interface{} = task
+		links    []*models.Link
+	)
+	switch query.Get("links") {
+	case "true", "1":
+		links, err = h.storage.GetTaskLinks(r.Context(), taskID)
+		if err != nil {
+			log.Error("Failed to get task links", "error", err, "task_id", taskID)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to duplicate task")
+			return
+		}
+		for _, link := range links {
+			link.ID = ""
 		}
 	}
 
-	// Sort next_up by priority (critical > high > normal > minor)
-	sort.Slice(nextUp, func(i, j int) bool {
-		priorityOrder := map[string]int{
-			"critical": 0,
-			"high":     1,
-			"normal":   2,
-			"minor":    3,
+	if len(links) == 0 {
+		err = h.storage.CreateTask(r.Context(), task)
+	} else {
+		err = h.storage.CreateTaskWithLinks(r.Context(), task, links)
+		response = struct {
+			*models.Task
+			Links []*models.Link `json:"links"`
+		}{task, links}
+	}
+	if err != nil {
+		log.Error("Failed to duplicate task", "error", err, "task_id", taskID)
+		if isValidationError(err) {
+			writeValidationError(w, err)
+		} else {
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to duplicate task")
 		}
-		priority1 := nextUp[i]["priority"].(models.Priority)
-		priority2 := nextUp[j]["priority"].(models.Priority)
-		return priorityOrder[string(priority1)] < priorityOrder[string(priority2)]
-	})
-
-	report["working_on"] = workingOn
-	report["next_up"] = nextUp
-	report["blockers"] = blockers
+		return
+	}
 
-	log.Debug("Report generated",
-		"working_on_count", len(workingOn),
-		"next_up_count", len(nextUp),
-		"blockers_count", len(blockers))
+	log.Info("Task duplicated", "source_task_id", taskID, "task_id", task.ID, "links", len(links))
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(report); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	w.Header().Set("Location", "/api/tasks/"+task.ID)
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
 		return
 	}
 }
 
-// HandleLinks handles POST requests to create new links
-func (h *TaskHandler) HandleLinks(w http.ResponseWriter, r *http.Request) {
+func isValidationError(err error) bool {
+	var validationErr *models.ValidationError
+	ok := errors.As(err, &validationErr)
+	return ok
+}
+
+// HandleReport generates an automatic status report
+func (h *TaskHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
 	log := logger.FromContext(r.Context())
+	log.Debug("Generating automatic report")
 
 	switch r.Method {
-	case http.MethodPost:
-		h.createLink(w, r)
+	case http.MethodGet:
+		h.generateReport(w, r)
 	default:
-		log.Debug("Method not allowed for links", "method", r.Method)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 	}
 }
 
-// HandleLink handles individual link operations (GET, PUT, DELETE)
-func (h *TaskHandler) HandleLink(w http.ResponseWriter, r *http.Request) {
-	log := logger.FromContext(r.Context())
-
-	// Extract link ID from URL path
+// generateReport creates automatic status report
+// @Summary Generate status report
+// @Description Generate an automatic status report with working_on, next_up, blockers and stale sections, plus summary counts by status and priority. Stale lists in_progress tasks not updated for stale_days, oldest first
+// @Tags report
+// @Produce json
+// @Param stale_days query int false "Days without updates before an in_progress task is stale" default(5)
+// @Success 200 {object} models.ReportResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /report [get]
+func (h *TaskHandler) generateReport(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	report, err := NewReportService(h.storage).Generate(r.Context(), reportFiltersFromQuery(r))
+	if err != nil {
+		log.Error("Failed to get tasks for report", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to generate report")
+		return
+	}
+
+	log.Debug("Report generated",
+		"total", report.Summary.Total,
+		"working_on_count", len(report.WorkingOn),
+		"next_up_count", len(report.NextUp),
+		"blockers_count", len(report.Blockers),
+		"stale_count", len(report.Stale))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// HandleMerge handles requests to merge two tasks
+func (h *TaskHandler) HandleMerge(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.mergeTasks(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// mergeTasks folds a source task into a target task
+// @Summary Merge two tasks
+// @Description Move the source task's links and comments to the target, union tags and blockers, and archive (or delete) the source
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param merge body models.MergeTasksRequest true "Tasks to merge"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/merge [post]
+func (h *TaskHandler) mergeTasks(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	var req models.MergeTasksRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		log.Error("Failed to decode merge JSON", "error", err)
+		writeDecodeError(w, err, "Invalid JSON")
+		return
+	}
+
+	if req.Source == "" || req.Target == "" {
+		field := "source"
+		if req.Source != "" {
+			field = "target"
+		}
+		writeFieldError(w, field, models.CodeRequired, "source and target are required")
+		return
+	}
+
+	opts := storage.MergeOptions{
+		Prefer:       storage.MergePreference(req.Prefer),
+		DeleteSource: req.DeleteSource,
+	}
+	switch opts.Prefer {
+	case "":
+		opts.Prefer = storage.PreferTarget
+	case storage.PreferTarget, storage.PreferSource:
+	default:
+		writeFieldError(w, "prefer", models.CodeInvalid, "prefer must be 'target' or 'source'")
+		return
+	}
+
+	log.Debug("Merging tasks", "source", req.Source, "target", req.Target, "prefer", opts.Prefer)
+
+	task, err := h.storage.MergeTasks(r.Context(), req.Source, req.Target, opts)
+	if err != nil {
+		log.Error("Failed to merge tasks", "error", err, "source", req.Source, "target", req.Target)
+		switch {
+		case isValidationError(err):
+			writeValidationError(w, err)
+		case errors.Is(err, storage.ErrNotFound):
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Task not found")
+		default:
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to merge tasks")
+		}
+		return
+	}
+
+	log.Info("Tasks merged successfully", "source", req.Source, "target", req.Target)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(task); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// HandleGrouped handles requests for tasks bucketed by status
+func (h *TaskHandler) HandleGrouped(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.groupedTasks(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// groupedTasks lists tasks bucketed by status, for board views
+// @Summary List tasks grouped by status
+// @Description Get every task in one call, keyed by status and newest first within each status. All of new, in_progress, blocked and done are present, empty or not; archived is only included with include_archived
+// @Tags tasks
+// @Produce json
+// @Param tags query string false "Filter by tags (comma-separated)" example("k8s,memory")
+// @Param include_archived query boolean false "Include an archived bucket" default(false)
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} map[string][]models.Task
+// @Success 304 "Not modified"
+// @Router /tasks/grouped [get]
+func (h *TaskHandler) groupedTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := storage.TaskFilters{}
+	if tags := query.Get("tags"); tags != "" {
+		filters.Tags = models.ParseTags(tags)
+	}
+	switch query.Get("include_archived") {
+	case "true", "1":
+		filters.IncludeArchived = true
+	}
+
+	tasks, err := h.storage.ListTasks(r.Context(), filters)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("Failed to list tasks", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to list tasks")
+		return
+	}
+
+	statuses := []models.Status{models.New, models.InProgress, models.Blocked, models.Done}
+	if filters.IncludeArchived {
+		statuses = append(statuses, models.Archived)
+	}
+	groups := make(map[models.Status][]*models.Task, len(statuses))
+	for _, status := range statuses {
+		groups[status] = []*models.Task{}
+	}
+	for _, task := range tasks {
+		if group, ok := groups[task.Status]; ok {
+			groups[task.Status] = append(group, task)
+		}
+	}
+
+	writeJSONWithETag(w, r, groups)
+}
+
+// HandleValidate handles dry-run task validation requests
+func (h *TaskHandler) HandleValidate(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.validateTask(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// validateTask checks a task payload without storing it
+// @Summary Validate a task
+// @Description Run the same checks as task creation without persisting anything. Returns the task as it would be stored, with defaults applied and tags normalized
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param task body models.CreateTaskRequest true "Task to validate"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
+// @Router /tasks/validate [post]
+func (h *TaskHandler) validateTask(w http.ResponseWriter, r *http.Request) {
+	var task models.Task
+	if err := h.decodeJSON(w, r, &task); err != nil {
+		writeDecodeError(w, err, "Invalid JSON")
+		return
+	}
+
+	if err := task.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if err := task.ValidateBlockedChange(""); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(task); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// HandleEnsure handles idempotent get-or-create requests keyed by Jira ID
+func (h *TaskHandler) HandleEnsure(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.ensureTask(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// ensureTask returns the task tracking a Jira ID, creating it if needed
+// @Summary Ensure a task exists for a Jira ID
+// @Description Return the existing non-archived task with the given jira_id (200), or create it from the request body (201). Requests with jira_id NO-JIRA (or empty) always create a new task.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param task body models.CreateTaskRequest true "Task to find or create"
+// @Success 200 {object} models.Task
+// @Success 201 {object} models.Task
+// @Header 201 {string} Location "URL of the created task"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
+// @Router /tasks/ensure [post]
+func (h *TaskHandler) ensureTask(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	var task models.Task
+	if err := h.decodeJSON(w, r, &task); err != nil {
+		writeDecodeError(w, err, "Invalid JSON")
+		return
+	}
+
+	// NO-JIRA is shared by unrelated tasks, so it never identifies an existing one
+	if !models.IsNoJira(task.JiraID) {
+		existing, err := h.storage.GetTaskByJiraID(r.Context(), task.JiraID)
+		switch {
+		case err == nil:
+			log.Debug("Found existing task for Jira ID", "jira_id", task.JiraID, "task_id", existing.ID)
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(existing); err != nil {
+				writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+			}
+			return
+		case !errors.Is(err, storage.ErrNotFound):
+			log.Error("Failed to look up task by Jira ID", "error", err, "jira_id", task.JiraID)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to look up task")
+			return
+		}
+	}
+
+	err := h.storage.CreateTask(r.Context(), &task)
+	switch {
+	case err == nil:
+		log.Info("Task ensured by creation", "jira_id", task.JiraID, "task_id", task.ID)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/tasks/"+task.ID)
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(task); err != nil {
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+			return
+		}
+	case isValidationError(err):
+		writeValidationError(w, err)
+	default:
+		log.Error("Failed to create task", "error", err)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create task")
+	}
+}
+
+// HandleLinks handles POST requests to create new links
+func (h *TaskHandler) HandleLinks(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	switch r.Method {
+	case http.MethodPost:
+		h.createLink(w, r)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodPost)
+	default:
+		log.Debug("Method not allowed for links", "method", r.Method)
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleLink handles individual link operations (GET, PUT, DELETE)
+func (h *TaskHandler) HandleLink(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	// Extract link ID from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/api/links/")
 	if path == "" {
 		log.Debug("Link ID required but not provided")
-		http.Error(w, "Link ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, errCodeBadRequest, "Link ID required")
 		return
 	}
 
-	linkID := strings.Split(path, "/")[0]
+	parts := strings.Split(path, "/")
+	linkID := parts[0]
 	log.Debug("HandleLink called", "link_id", linkID, "method", r.Method)
 
+	if len(parts) > 1 && parts[1] != "" {
+		switch parts[1] {
+		case "move":
+			h.handleLinkMove(w, r, linkID)
+		default:
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Not found")
+		}
+		return
+	}
+
 	switch r.Method {
 	case http.MethodGet:
 		h.getLink(w, r, linkID)
+	case http.MethodHead:
+		h.getLink(headResponseWriter{w}, r, linkID)
 	case http.MethodPut:
 		h.updateLink(w, r, linkID)
 	case http.MethodDelete:
 		h.deleteLink(w, r, linkID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodGet, http.MethodPut, http.MethodDelete)
 	default:
 		log.Debug("Method not allowed for link", "method", r.Method, "link_id", linkID)
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 	}
 }
 
@@ -549,19 +1349,69 @@ func (h *TaskHandler) HandleLink(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param link body models.CreateLinkRequest true "Link to create"
 // @Success 201 {object} models.Link
+// @Header 201 {string} Location "URL of the created link"
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
 // @Router /links [post]
 func (h *TaskHandler) createLink(w http.ResponseWriter, r *http.Request) {
 	log := logger.FromContext(r.Context())
 	log.Debug("Creating new link")
 
 	var link models.Link
-	if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+	if err := h.decodeJSON(w, r, &link); err != nil {
 		log.Error("Failed to decode link JSON", "error", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		writeDecodeError(w, err, "Invalid JSON")
 		return
 	}
 
+	h.saveNewLink(w, r, &link)
+}
+
+// decodeJSON decodes the request body into v, reading at most maxBodyBytes.
+// In strict mode fields v doesn't have are an error.
+func (h *TaskHandler) decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes)
+	dec := json.NewDecoder(r.Body)
+	if h.strictJSON {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(v)
+}
+
+// unknownFieldPrefix starts the error encoding/json returns for a field
+// rejected by DisallowUnknownFields
+const unknownFieldPrefix = "json: unknown field "
+
+// writeDecodeError answers a failed decodeJSON: 413 when the body was over the
+// limit, 400 naming the field for an unknown field, otherwise 400 with msg
+func writeDecodeError(w http.ResponseWriter, err error, msg string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, errCodeBodyTooLarge, "Request body too large")
+		return
+	}
+	if field, ok := strings.CutPrefix(err.Error(), unknownFieldPrefix); ok {
+		writeError(w, http.StatusBadRequest, errCodeBadRequest, "Unknown field "+field)
+		return
+	}
+	writeError(w, http.StatusBadRequest, errCodeBadRequest, msg)
+}
+
+// applyLinkDefaults fills in the title and status of a new link when omitted
+func applyLinkDefaults(link *models.Link) {
+	if link.Title == "" {
+		link.Title = link.URL
+	}
+	if link.Status == "" {
+		link.Status = "active"
+	}
+}
+
+// saveNewLink validates, defaults and stores a decoded link, writing the created link as the response
+func (h *TaskHandler) saveNewLink(w http.ResponseWriter, r *http.Request, link *models.Link) {
+	log := logger.FromContext(r.Context())
+
 	log.Debug("Link data received",
 		"task_id", link.TaskID,
 		"type", link.Type,
@@ -571,35 +1421,35 @@ func (h *TaskHandler) createLink(w http.ResponseWriter, r *http.Request) {
 	// Validate required fields
 	if link.TaskID == "" {
 		log.Debug("Missing task_id in link creation")
-		http.Error(w, "task_id is required", http.StatusBadRequest)
+		writeFieldError(w, "task_id", models.CodeRequired, "task_id is required")
 		return
 	}
 	if link.URL == "" {
 		log.Debug("Missing URL in link creation")
-		http.Error(w, "url is required", http.StatusBadRequest)
+		writeFieldError(w, "url", models.CodeRequired, "url is required")
 		return
 	}
 	if link.Type == "" {
 		log.Debug("Missing type in link creation")
-		http.Error(w, "type is required", http.StatusBadRequest)
+		writeFieldError(w, "type", models.CodeRequired, "type is required")
 		return
 	}
 
-	// Set default values
-	if link.Title == "" {
-		link.Title = link.URL
-	}
-	if link.Status == "" {
-		link.Status = "active"
+	applyLinkDefaults(link)
+
+	if err := link.Validate(); err != nil {
+		log.Debug("Invalid link", "error", err)
+		writeValidationError(w, err)
+		return
 	}
 
-	err := h.storage.CreateLink(&link)
+	err := h.storage.CreateLink(r.Context(), link)
 	if err != nil {
 		log.Error("Failed to create link", "error", err, "task_id", link.TaskID)
 		if isValidationError(err) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeValidationError(w, err)
 		} else {
-			http.Error(w, "Failed to create link", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create link")
 		}
 		return
 	}
@@ -607,9 +1457,306 @@ func (h *TaskHandler) createLink(w http.ResponseWriter, r *http.Request) {
 	log.Info("Link created successfully", "link_id", link.ID, "task_id", link.TaskID, "type", link.Type)
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/links/"+link.ID)
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(link); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// handleTaskLinks serves the /api/tasks/{id}/links sub-resource
+func (h *TaskHandler) handleTaskLinks(w http.ResponseWriter, r *http.Request, taskID string) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listTaskLinks(w, r, taskID)
+	case http.MethodHead:
+		h.listTaskLinks(headResponseWriter{w}, r, taskID)
+	case http.MethodPost:
+		h.createTaskLink(w, r, taskID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodGet, http.MethodPost)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// taskExists writes a 404 (or 500) response and returns false when the task cannot be loaded
+func (h *TaskHandler) taskExists(w http.ResponseWriter, r *http.Request, taskID string) bool {
+	_, err := h.storage.GetTask(r.Context(), taskID)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, storage.ErrNotFound):
+		writeError(w, http.StatusNotFound, errCodeNotFound, "Task not found")
+	default:
+		logger.FromContext(r.Context()).Error("Failed to get task", "error", err, "task_id", taskID)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get task")
+	}
+	return false
+}
+
+// listTaskLinks retrieves the links of a task
+// @Summary List task links
+// @Description Get only the links of a task, without loading its comments. Pass type as a comma-separated list to keep only those link types
+// @Tags links
+// @Produce json
+// @Param id path string true "Task ID" format(uuid)
+// @Param type query string false "Comma-separated link types" example(pull_request,jira_ticket)
+// @Success 200 {array} models.Link
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/links [get]
+func (h *TaskHandler) listTaskLinks(w http.ResponseWriter, r *http.Request, taskID string) {
+	var types []models.LinkType
+	for _, t := range strings.Split(r.URL.Query().Get("type"), ",") {
+		if t = strings.TrimSpace(t); t == "" {
+			continue
+		}
+		linkType := models.LinkType(t)
+		if !linkType.IsValid() {
+			writeFieldError(w, "type", models.CodeInvalid, fmt.Sprintf("Invalid link type %q", t))
+			return
+		}
+		types = append(types, linkType)
+	}
+
+	if !h.taskExists(w, r, taskID) {
+		return
+	}
+
+	var (
+		links []*models.Link
+		err   error
+	)
+	if len(types) == 0 {
+		links, err = h.storage.GetTaskLinks(r.Context(), taskID)
+	} else {
+		links, err = h.storage.GetTaskLinksByType(r.Context(), taskID, types...)
+	}
+	if err != nil {
+		logger.FromContext(r.Context()).Error("Failed to get task links", "error", err, "task_id", taskID)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get links")
+		return
+	}
+	if links == nil {
+		links = []*models.Link{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(links); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// createTaskLink creates a link on the task named in the path
+// @Summary Add link to task
+// @Description Create a link for a task; the task ID is taken from the path so the body doesn't need task_id
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID" format(uuid)
+// @Param link body models.CreateLinkRequest true "Link to create (task_id is ignored)"
+// @Success 201 {object} models.Link
+// @Header 201 {string} Location "URL of the created link"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/links [post]
+func (h *TaskHandler) createTaskLink(w http.ResponseWriter, r *http.Request, taskID string) {
+	log := logger.FromContext(r.Context())
+
+	var link models.Link
+	if err := h.decodeJSON(w, r, &link); err != nil {
+		log.Error("Failed to decode link JSON", "error", err)
+		writeDecodeError(w, err, "Invalid JSON")
+		return
+	}
+
+	if !h.taskExists(w, r, taskID) {
+		return
+	}
+
+	link.TaskID = taskID
+	h.saveNewLink(w, r, &link)
+}
+
+// handleTaskComments serves the /api/tasks/{id}/comments sub-resource
+func (h *TaskHandler) handleTaskComments(w http.ResponseWriter, r *http.Request, taskID string) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listTaskComments(w, r, taskID)
+	case http.MethodHead:
+		h.listTaskComments(headResponseWriter{w}, r, taskID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodGet)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// listTaskComments retrieves one page of a task's comments
+// @Summary List task comments
+// @Description Page through a task's comments, oldest first, with the total count
+// @Tags comments
+// @Produce json
+// @Param id path string true "Task ID" format(uuid)
+// @Param limit query int false "Page size" default(50) maximum(200)
+// @Param offset query int false "Number of comments to skip" default(0)
+// @Success 200 {object} models.CommentListResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/comments [get]
+func (h *TaskHandler) listTaskComments(w http.ResponseWriter, r *http.Request, taskID string) {
+	if !h.taskExists(w, r, taskID) {
+		return
+	}
+
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	limit = h.pageSize(limit)
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	comments, total, err := h.storage.GetTaskCommentsPaged(r.Context(), taskID, limit, offset)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("Failed to get task comments", "error", err, "task_id", taskID)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get comments")
+		return
+	}
+	if comments == nil {
+		comments = []*models.Comment{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(models.CommentListResponse{
+		Comments: comments,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	}); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// handleTaskCommentsBulk serves the /api/tasks/{id}/comments/bulk sub-resource
+func (h *TaskHandler) handleTaskCommentsBulk(w http.ResponseWriter, r *http.Request, taskID string) {
+	switch r.Method {
+	case http.MethodPost:
+		h.createTaskComments(w, r, taskID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodPost)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// createTaskComments attaches many comments to a task at once
+// @Summary Bulk import comments
+// @Description Add several comments to a task in one transaction, keeping their original created_at so notes migrated from another tool stay in order. Comments without created_at are stamped with the current time, in request order. If any comment is invalid none are stored.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID" format(uuid)
+// @Param comments body []models.BulkCommentItem true "Comments to add"
+// @Success 201 {object} models.BulkCommentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/comments/bulk [post]
+func (h *TaskHandler) createTaskComments(w http.ResponseWriter, r *http.Request, taskID string) {
+	log := logger.FromContext(r.Context())
+
+	var req []models.BulkCommentItem
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		log.Error("Failed to decode request body", "error", err)
+		writeDecodeError(w, err, "Invalid request body")
+		return
+	}
+	if len(req) == 0 {
+		writeFieldError(w, "comments", models.CodeRequired, "at least one comment is required")
+		return
+	}
+
+	if !h.taskExists(w, r, taskID) {
+		return
+	}
+
+	comments := make([]*models.Comment, len(req))
+	for i, item := range req {
+		comments[i] = &models.Comment{
+			TaskID:  taskID,
+			Content: strings.TrimSpace(item.Content),
+		}
+		if item.CreatedAt != nil {
+			comments[i].CreatedAt = *item.CreatedAt
+		}
+	}
+
+	if err := h.storage.CreateComments(r.Context(), comments); err != nil {
+		log.Error("Failed to create comments", "error", err, "task_id", taskID)
+		if isValidationError(err) {
+			writeValidationError(w, err)
+		} else {
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create comments")
+		}
+		return
+	}
+
+	log.Info("Comments imported", "task_id", taskID, "count", len(comments))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(models.BulkCommentResponse{Comments: comments}); err != nil {
+		log.Error("Failed to encode response", "error", err)
+	}
+}
+
+// handleTaskActivity serves the /api/tasks/{id}/activity sub-resource
+func (h *TaskHandler) handleTaskActivity(w http.ResponseWriter, r *http.Request, taskID string) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getTaskActivity(w, r, taskID)
+	case http.MethodHead:
+		h.getTaskActivity(headResponseWriter{w}, r, taskID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodGet)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getTaskActivity retrieves a task's timeline
+// @Summary Get task activity
+// @Description Chronological feed of what happened to a task: creation, status changes, edits, links added and comments posted
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID" format(uuid)
+// @Success 200 {array} models.Activity
+// @Failure 404 {object} models.ErrorResponse
+// @Router /tasks/{id}/activity [get]
+func (h *TaskHandler) getTaskActivity(w http.ResponseWriter, r *http.Request, taskID string) {
+	if !h.taskExists(w, r, taskID) {
+		return
+	}
+
+	activity, err := h.storage.GetTaskActivity(r.Context(), taskID)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("Failed to get task activity", "error", err, "task_id", taskID)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get activity")
+		return
+	}
+	if activity == nil {
+		activity = []models.Activity{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(activity); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
 		return
 	}
 }
@@ -627,34 +1774,37 @@ func (h *TaskHandler) getLink(w http.ResponseWriter, r *http.Request, linkID str
 	log := logger.FromContext(r.Context())
 	log.Debug("Getting link", "link_id", linkID)
 
-	link, err := h.storage.GetLink(linkID)
+	link, err := h.storage.GetLink(r.Context(), linkID)
 	if err != nil {
 		log.Error("Failed to get link", "error", err, "link_id", linkID)
-		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, "Link not found", http.StatusNotFound)
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Link not found")
 		} else {
-			http.Error(w, "Failed to get link", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get link")
 		}
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(link); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
 		return
 	}
 }
 
 // updateLink updates a link
 // @Summary Update link
-// @Description Update a link with new data
+// @Description Update a link with new data. With merge_metadata=true the metadata object is merged into the stored one (JSON merge patch: nested keys are merged, null removes a key) instead of replacing it, so writers updating different keys don't overwrite each other
 // @Tags links
 // @Accept json
 // @Produce json
 // @Param id path string true "Link ID" format(uuid)
+// @Param merge_metadata query boolean false "Merge metadata instead of replacing it" default(false)
 // @Param link body models.Link true "Link data"
 // @Success 200 {object} models.Link
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /links/{id} [put]
 func (h *TaskHandler) updateLink(w http.ResponseWriter, r *http.Request, linkID string) {
@@ -662,24 +1812,30 @@ func (h *TaskHandler) updateLink(w http.ResponseWriter, r *http.Request, linkID
 	log.Debug("Updating link", "link_id", linkID)
 
 	var link models.Link
-	if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+	if err := h.decodeJSON(w, r, &link); err != nil {
 		log.Error("Failed to decode link JSON for update", "error", err, "link_id", linkID)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		writeDecodeError(w, err, "Invalid JSON")
 		return
 	}
 
 	// Ensure the ID matches the URL parameter
 	link.ID = linkID
 
-	err := h.storage.UpdateLink(&link)
+	var err error
+	switch r.URL.Query().Get("merge_metadata") {
+	case "true", "1":
+		err = h.storage.UpdateLinkWithOptions(r.Context(), &link, storage.UpdateLinkOptions{MergeMetadata: true})
+	default:
+		err = h.storage.UpdateLink(r.Context(), &link)
+	}
 	if err != nil {
 		log.Error("Failed to update link", "error", err, "link_id", linkID)
 		if isValidationError(err) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		} else if strings.Contains(err.Error(), "not found") {
-			http.Error(w, "Link not found", http.StatusNotFound)
+			writeValidationError(w, err)
+		} else if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Link not found")
 		} else {
-			http.Error(w, "Failed to update link", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to update link")
 		}
 		return
 	}
@@ -688,7 +1844,96 @@ func (h *TaskHandler) updateLink(w http.ResponseWriter, r *http.Request, linkID
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(link); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
+		return
+	}
+}
+
+// handleLinkMove serves the /api/links/{id}/move action
+func (h *TaskHandler) handleLinkMove(w http.ResponseWriter, r *http.Request, linkID string) {
+	switch r.Method {
+	case http.MethodPost:
+		h.moveLink(w, r, linkID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodPost)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// moveLink reassigns a link to another task, or clones it there with copy=true
+// @Summary Move or copy link
+// @Description Reassign a link to another task. With copy=true the link is cloned onto the target task and the original is left in place
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param id path string true "Link ID" format(uuid)
+// @Param copy query boolean false "Copy instead of move" default(false)
+// @Param request body models.MoveLinkRequest true "Destination task"
+// @Success 200 {object} models.Link "Moved link"
+// @Success 201 {object} models.Link "Copied link"
+// @Header 201 {string} Location "URL of the copied link"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /links/{id}/move [post]
+func (h *TaskHandler) moveLink(w http.ResponseWriter, r *http.Request, linkID string) {
+	log := logger.FromContext(r.Context())
+
+	var req models.MoveLinkRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err, "Invalid JSON")
+		return
+	}
+	if req.TaskID == "" {
+		writeFieldError(w, "task_id", models.CodeRequired, "task_id is required")
+		return
+	}
+
+	link, err := h.storage.GetLink(r.Context(), linkID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Link not found")
+		} else {
+			log.Error("Failed to get link", "error", err, "link_id", linkID)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to get link")
+		}
+		return
+	}
+
+	if !h.taskExists(w, r, req.TaskID) {
+		return
+	}
+
+	switch r.URL.Query().Get("copy") {
+	case "true", "1":
+		log.Info("Copying link", "link_id", linkID, "from_task_id", link.TaskID, "to_task_id", req.TaskID)
+		link.ID = ""
+		link.TaskID = req.TaskID
+		h.saveNewLink(w, r, link)
+		return
+	}
+
+	fromTaskID := link.TaskID
+	link.TaskID = req.TaskID
+	if err := h.storage.UpdateLink(r.Context(), link); err != nil {
+		log.Error("Failed to move link", "error", err, "link_id", linkID)
+		if isValidationError(err) {
+			writeValidationError(w, err)
+		} else if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Link not found")
+		} else {
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to move link")
+		}
+		return
+	}
+
+	log.Info("Link moved", "link_id", linkID, "from_task_id", fromTaskID, "to_task_id", req.TaskID)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(link); err != nil {
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
 		return
 	}
 }
@@ -699,19 +1944,20 @@ func (h *TaskHandler) updateLink(w http.ResponseWriter, r *http.Request, linkID
 // @Tags links
 // @Param id path string true "Link ID" format(uuid)
 // @Success 204 "No Content"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
 // @Failure 404 {object} models.ErrorResponse
 // @Router /links/{id} [delete]
 func (h *TaskHandler) deleteLink(w http.ResponseWriter, r *http.Request, linkID string) {
 	log := logger.FromContext(r.Context())
 	log.Debug("Deleting link", "link_id", linkID)
 
-	err := h.storage.DeleteLink(linkID)
+	err := h.storage.DeleteLink(r.Context(), linkID)
 	if err != nil {
 		log.Error("Failed to delete link", "error", err, "link_id", linkID)
-		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, "Link not found", http.StatusNotFound)
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errCodeNotFound, "Link not found")
 		} else {
-			http.Error(w, "Failed to delete link", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to delete link")
 		}
 		return
 	}
@@ -725,8 +1971,10 @@ func (h *TaskHandler) HandleComments(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
 		h.createComment(w, r)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodPost)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 	}
 }
 
@@ -741,15 +1989,17 @@ func (h *TaskHandler) HandleComment(w http.ResponseWriter, r *http.Request) {
 	log.Debug("HandleComment called", "comment_id", commentID, "method", r.Method)
 
 	if commentID == "" {
-		http.Error(w, "Comment ID required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, errCodeBadRequest, "Comment ID required")
 		return
 	}
 
 	switch r.Method {
 	case http.MethodDelete:
 		h.deleteComment(w, r, commentID)
+	case http.MethodOptions:
+		writeOptions(w, http.MethodDelete)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, "Method not allowed")
 	}
 }
 
@@ -762,6 +2012,8 @@ func (h *TaskHandler) HandleComment(w http.ResponseWriter, r *http.Request) {
 // @Param comment body models.CreateCommentRequest true "Comment to create"
 // @Success 200 {object} models.CreateCommentResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
+// @Failure 413 {object} models.ErrorResponse
 // @Router /comments [post]
 func (h *TaskHandler) createComment(w http.ResponseWriter, r *http.Request) {
 	log := logger.FromContext(r.Context())
@@ -771,9 +2023,9 @@ func (h *TaskHandler) createComment(w http.ResponseWriter, r *http.Request) {
 		Content string `json:"content"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := h.decodeJSON(w, r, &req); err != nil {
 		log.Error("Failed to decode request body", "error", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeDecodeError(w, err, "Invalid request body")
 		return
 	}
 
@@ -781,12 +2033,12 @@ func (h *TaskHandler) createComment(w http.ResponseWriter, r *http.Request) {
 
 	// Validate input
 	if req.TaskID == "" {
-		http.Error(w, "task_id is required", http.StatusBadRequest)
+		writeFieldError(w, "task_id", models.CodeRequired, "task_id is required")
 		return
 	}
 
 	if strings.TrimSpace(req.Content) == "" {
-		http.Error(w, "content is required", http.StatusBadRequest)
+		writeFieldError(w, "content", models.CodeRequired, "content is required")
 		return
 	}
 
@@ -796,9 +2048,13 @@ func (h *TaskHandler) createComment(w http.ResponseWriter, r *http.Request) {
 		Content: strings.TrimSpace(req.Content),
 	}
 
-	if err := h.storage.CreateComment(comment); err != nil {
+	if err := h.storage.CreateComment(r.Context(), comment); err != nil {
+		if isValidationError(err) {
+			writeValidationError(w, err)
+			return
+		}
 		log.Error("Failed to create comment", "error", err)
-		http.Error(w, "Failed to create comment", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to create comment")
 		return
 	}
 
@@ -809,7 +2065,7 @@ func (h *TaskHandler) createComment(w http.ResponseWriter, r *http.Request) {
 		"id":      comment.ID,
 		"message": "Comment created successfully",
 	}); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
 		return
 	}
 }
@@ -820,6 +2076,7 @@ func (h *TaskHandler) createComment(w http.ResponseWriter, r *http.Request) {
 // @Tags comments
 // @Param id path string true "Comment ID" format(uuid)
 // @Success 200 {object} models.DeleteCommentResponse
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API key"
 // @Failure 404 {object} models.ErrorResponse
 // @Router /comments/{id} [delete]
 func (h *TaskHandler) deleteComment(w http.ResponseWriter, r *http.Request, commentID string) {
@@ -827,9 +2084,9 @@ func (h *TaskHandler) deleteComment(w http.ResponseWriter, r *http.Request, comm
 
 	log.Debug("Deleting comment", "comment_id", commentID)
 
-	if err := h.storage.DeleteComment(commentID); err != nil {
+	if err := h.storage.DeleteComment(r.Context(), commentID); err != nil {
 		log.Error("Failed to delete comment", "error", err, "comment_id", commentID)
-		http.Error(w, "Failed to delete comment", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to delete comment")
 		return
 	}
 
@@ -839,7 +2096,7 @@ func (h *TaskHandler) deleteComment(w http.ResponseWriter, r *http.Request, comm
 	if err := json.NewEncoder(w).Encode(map[string]string{
 		"message": "Comment deleted successfully",
 	}); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, errCodeInternal, "Failed to encode response")
 		return
 	}
 }
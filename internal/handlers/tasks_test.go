@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +16,7 @@ import (
 	"michishirube/internal/handlers/mocks"
 	"michishirube/internal/models"
 	"michishirube/internal/storage"
+	"michishirube/internal/storage/memory"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -34,6 +38,14 @@ func createValidTask() *models.Task {
 	}
 }
 
+// allowTaskRelationCounts lets task lists look up link and comment counts, reporting none
+func allowTaskRelationCounts(mockStorage *mocks.MockStorage) {
+	mockStorage.EXPECT().
+		CountTaskRelations(gomock.Any(), gomock.Any()).
+		Return(map[string]storage.TaskRelationCounts{}, nil).
+		AnyTimes()
+}
+
 func createValidLink() *models.Link {
 	return &models.Link{
 		ID:     "link-123",
@@ -54,6 +66,30 @@ func createValidComment() *models.Comment {
 	}
 }
 
+// assertErrorResponse checks that w holds a JSON models.ErrorResponse with
+// the given code and a message containing message
+func assertErrorResponse(t *testing.T, w *httptest.ResponseRecorder, code, message string) {
+	t.Helper()
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, code, response.Code)
+	assert.Contains(t, response.Error, message)
+}
+
+// assertValidationErrorResponse checks for a validation error body whose
+// "field: message" contains message and returns the reported field error
+func assertValidationErrorResponse(t *testing.T, w *httptest.ResponseRecorder, message string) models.FieldError {
+	t.Helper()
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response models.ValidationErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Error.Field+": "+response.Error.Message, message)
+	return response.Error
+}
+
 func TestNewTaskHandler(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -71,13 +107,15 @@ func TestTaskHandler_HandleTasks_GET(t *testing.T) {
 
 	mockStorage := mocks.NewMockStorage(ctrl)
 	handler := NewTaskHandler(mockStorage)
+	allowTaskRelationCounts(mockStorage)
 
 	expectedTasks := []*models.Task{createValidTask()}
 
 	mockStorage.EXPECT().
-		ListTasks(gomock.Any()).
+		ListTasks(gomock.Any(), gomock.Any()).
 		Return(expectedTasks, nil).
 		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
 	w := httptest.NewRecorder()
@@ -96,19 +134,59 @@ func TestTaskHandler_HandleTasks_GET(t *testing.T) {
 	assert.Len(t, tasks, 1)
 }
 
+func TestTaskHandler_HandleTasks_GET_RelationCounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	busy := createValidTask()
+	quiet := createValidTask()
+	quiet.ID = "task-456"
+
+	mockStorage.EXPECT().
+		ListTasks(gomock.Any(), gomock.Any()).
+		Return([]*models.Task{busy, quiet}, nil).
+		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+	mockStorage.EXPECT().
+		CountTaskRelations(gomock.Any(), []string{"task-123", "task-456"}).
+		Return(map[string]storage.TaskRelationCounts{"task-123": {Links: 3, Comments: 2}, "task-456": {}}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response models.TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Tasks, 2)
+	assert.Equal(t, "task-123", response.Tasks[0].ID)
+	assert.Equal(t, 3, response.Tasks[0].LinkCount)
+	assert.Equal(t, 2, response.Tasks[0].CommentCount)
+	assert.Zero(t, response.Tasks[1].LinkCount)
+	assert.Zero(t, response.Tasks[1].CommentCount)
+	assert.Contains(t, w.Body.String(), `"link_count":0`, "zero counts are still sent")
+}
+
 func TestTaskHandler_HandleTasks_GET_WithFilters(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockStorage := mocks.NewMockStorage(ctrl)
 	handler := NewTaskHandler(mockStorage)
+	allowTaskRelationCounts(mockStorage)
 
 	expectedTasks := []*models.Task{createValidTask()}
 
 	// Verify that filters are parsed correctly
 	mockStorage.EXPECT().
-		ListTasks(gomock.Any()).
-		DoAndReturn(func(filters storage.TaskFilters) ([]*models.Task, error) {
+		ListTasks(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filters storage.TaskFilters) ([]*models.Task, error) {
 			assert.Len(t, filters.Status, 2)
 			assert.Contains(t, filters.Status, models.New)
 			assert.Contains(t, filters.Status, models.InProgress)
@@ -120,6 +198,7 @@ func TestTaskHandler_HandleTasks_GET_WithFilters(t *testing.T) {
 			return expectedTasks, nil
 		}).
 		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks?status=new,in_progress&priority=high&limit=10&offset=5&include_archived=true", nil)
 	w := httptest.NewRecorder()
@@ -137,7 +216,7 @@ func TestTaskHandler_HandleTasks_GET_StorageError(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		ListTasks(gomock.Any()).
+		ListTasks(gomock.Any(), gomock.Any()).
 		Return(nil, fmt.Errorf("database connection failed")).
 		Times(1)
 
@@ -147,7 +226,7 @@ func TestTaskHandler_HandleTasks_GET_StorageError(t *testing.T) {
 	handler.HandleTasks(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "database connection failed")
+	assertErrorResponse(t, w, errCodeInternal, "Failed to list tasks")
 }
 
 func TestTaskHandler_HandleTasks_POST_Success(t *testing.T) {
@@ -164,8 +243,8 @@ func TestTaskHandler_HandleTasks_POST_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		CreateTask(gomock.Any()).
-		DoAndReturn(func(taskArg *models.Task) error {
+		CreateTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, taskArg *models.Task) error {
 			// Verify the task data was parsed correctly
 			assert.Equal(t, task.Title, taskArg.Title)
 			assert.Equal(t, task.JiraID, taskArg.JiraID)
@@ -202,7 +281,74 @@ func TestTaskHandler_HandleTasks_POST_InvalidJSON(t *testing.T) {
 	handler.HandleTasks(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid JSON")
+	assertErrorResponse(t, w, errCodeBadRequest, "Invalid JSON")
+}
+
+func TestTaskHandler_HandleTasks_POST_WithLinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().
+		CreateTaskWithLinks(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, task *models.Task, links []*models.Link) error {
+			assert.Equal(t, "Ship it", task.Title)
+			require.Len(t, links, 2)
+			task.ID = "task-new"
+			for i, link := range links {
+				link.ID = fmt.Sprintf("link-%d", i+1)
+				link.TaskID = task.ID
+			}
+			return nil
+		}).
+		Times(1)
+
+	body := `{"jira_id":"OCPBUGS-1","title":"Ship it","links":[
+		{"type":"pull_request","url":"https://github.com/org/repo/pull/1"},
+		{"type":"jira_ticket","url":"https://issues.redhat.com/browse/OCPBUGS-1","title":"Bug"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/tasks/task-new", w.Header().Get("Location"))
+
+	var response models.TaskWithDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "task-new", response.ID)
+	require.Len(t, response.Links, 2)
+	for _, link := range response.Links {
+		assert.Equal(t, "task-new", link.TaskID)
+		assert.Equal(t, "active", link.Status)
+	}
+	assert.Equal(t, "https://github.com/org/repo/pull/1", response.Links[0].Title)
+	assert.Equal(t, "Bug", response.Links[1].Title)
+}
+
+func TestTaskHandler_HandleTasks_POST_WithInvalidLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().
+		CreateTaskWithLinks(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&models.ValidationError{Field: "links[1].url", Message: "url is required"}).
+		Times(1)
+
+	body := `{"title":"Ship it","links":[{"type":"pull_request","url":"https://github.com/org/repo/pull/1"},{"type":"jira_ticket"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertValidationErrorResponse(t, w, "links[1].url")
 }
 
 func TestTaskHandler_HandleTasks_POST_ValidationError(t *testing.T) {
@@ -220,7 +366,7 @@ func TestTaskHandler_HandleTasks_POST_ValidationError(t *testing.T) {
 
 	validationErr := &models.ValidationError{Message: "Title is required"}
 	mockStorage.EXPECT().
-		CreateTask(gomock.Any()).
+		CreateTask(gomock.Any(), gomock.Any()).
 		Return(validationErr).
 		Times(1)
 
@@ -231,7 +377,20 @@ func TestTaskHandler_HandleTasks_POST_ValidationError(t *testing.T) {
 	handler.HandleTasks(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Title is required")
+	assertValidationErrorResponse(t, w, "Title is required")
+}
+
+func TestTaskHandler_HandleTasks_POST_ValidationDetails(t *testing.T) {
+	handler := NewTaskHandler(memory.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(`{"jira_id": "NO-JIRA"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":{"field":"title","message":"title is required","code":"REQUIRED"}}`, w.Body.String())
 }
 
 func TestTaskHandler_HandleTasks_MethodNotAllowed(t *testing.T) {
@@ -247,7 +406,7 @@ func TestTaskHandler_HandleTasks_MethodNotAllowed(t *testing.T) {
 	handler.HandleTasks(w, req)
 
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
-	assert.Contains(t, w.Body.String(), "Method not allowed")
+	assertErrorResponse(t, w, errCodeMethodNotAllowed, "Method not allowed")
 }
 
 func TestTaskHandler_HandleTask_GET_Success(t *testing.T) {
@@ -261,9 +420,10 @@ func TestTaskHandler_HandleTask_GET_Success(t *testing.T) {
 	links := []*models.Link{createValidLink()}
 	comments := []*models.Comment{createValidComment()}
 
-	mockStorage.EXPECT().GetTask("task-123").Return(task, nil).Times(1)
-	mockStorage.EXPECT().GetTaskLinks("task-123").Return(links, nil).Times(1)
-	mockStorage.EXPECT().GetTaskComments("task-123").Return(comments, nil).Times(1)
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(task, nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), "task-123").Return(links, nil).Times(1)
+	mockStorage.EXPECT().GetTaskComments(gomock.Any(), "task-123").Return(comments, nil).Times(1)
+	mockStorage.EXPECT().GetRelatedTasks(gomock.Any(), "task-123", relatedTasksLimit).Return([]*models.Task{}, nil).Times(1)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123", nil)
 	w := httptest.NewRecorder()
@@ -291,8 +451,8 @@ func TestTaskHandler_HandleTask_GET_NotFound(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		GetTask("nonexistent").
-		Return(nil, fmt.Errorf("task not found")).
+		GetTask(gomock.Any(), "nonexistent").
+		Return(nil, storage.ErrNotFound).
 		Times(1)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks/nonexistent", nil)
@@ -301,7 +461,28 @@ func TestTaskHandler_HandleTask_GET_NotFound(t *testing.T) {
 	handler.HandleTask(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
-	assert.Contains(t, w.Body.String(), "Task not found")
+	assertErrorResponse(t, w, errCodeNotFound, "Task not found")
+}
+
+func TestTaskHandler_HandleTask_GET_StorageErrorMentioningNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	// Only storage.ErrNotFound means 404, whatever the error text says
+	mockStorage.EXPECT().
+		GetTask(gomock.Any(), "task-123").
+		Return(nil, errors.New("no such table: tasks (file not found)")).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
 func TestTaskHandler_HandleTask_PUT_Success(t *testing.T) {
@@ -319,8 +500,8 @@ func TestTaskHandler_HandleTask_PUT_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		UpdateTask(gomock.Any()).
-		DoAndReturn(func(taskArg *models.Task) error {
+		UpdateTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, taskArg *models.Task) error {
 			// Verify the ID was set correctly and title updated
 			assert.Equal(t, "task-123", taskArg.ID)
 			assert.Equal(t, "Updated title", taskArg.Title)
@@ -352,7 +533,7 @@ func TestTaskHandler_HandleTask_DELETE_Success(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		DeleteTask("task-123").
+		DeleteTask(gomock.Any(), "task-123").
 		Return(nil).
 		Times(1)
 
@@ -378,7 +559,7 @@ func TestTaskHandler_HandleTask_NoTaskID(t *testing.T) {
 	handler.HandleTask(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Task ID required")
+	assertErrorResponse(t, w, errCodeBadRequest, "Task ID required")
 }
 
 func TestTaskHandler_HandleTask_MethodNotAllowed(t *testing.T) {
@@ -394,7 +575,7 @@ func TestTaskHandler_HandleTask_MethodNotAllowed(t *testing.T) {
 	handler.HandleTask(w, req)
 
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
-	assert.Contains(t, w.Body.String(), "Method not allowed")
+	assertErrorResponse(t, w, errCodeMethodNotAllowed, "Method not allowed")
 }
 
 func TestIsValidationError(t *testing.T) {
@@ -444,9 +625,10 @@ func BenchmarkTaskHandler_ListTasks(b *testing.B) {
 	}
 
 	mockStorage.EXPECT().
-		ListTasks(gomock.Any()).
+		ListTasks(gomock.Any(), gomock.Any()).
 		Return(tasks, nil).
 		AnyTimes()
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
 
@@ -471,7 +653,7 @@ func BenchmarkTaskHandler_CreateTask(b *testing.B) {
 	require.NoError(b, err)
 
 	mockStorage.EXPECT().
-		CreateTask(gomock.Any()).
+		CreateTask(gomock.Any(), gomock.Any()).
 		Return(nil).
 		AnyTimes()
 
@@ -510,13 +692,13 @@ func TestTaskHandler_HandleTask_PATCH_Success(t *testing.T) {
 
 	// Mock expectations
 	mockStorage.EXPECT().
-		GetTask("task-123").
+		GetTask(gomock.Any(), "task-123").
 		Return(existingTask, nil).
 		Times(1)
 
 	mockStorage.EXPECT().
-		UpdateTask(gomock.Any()).
-		DoAndReturn(func(taskArg *models.Task) error {
+		UpdateTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, taskArg *models.Task) error {
 			// Verify the task was updated correctly
 			assert.Equal(t, models.InProgress, taskArg.Status)
 			assert.Equal(t, models.High, taskArg.Priority)
@@ -558,8 +740,8 @@ func TestTaskHandler_HandleTask_PATCH_TaskNotFound(t *testing.T) {
 
 	// Mock task not found
 	mockStorage.EXPECT().
-		GetTask("nonexistent").
-		Return(nil, fmt.Errorf("task not found")).
+		GetTask(gomock.Any(), "nonexistent").
+		Return(nil, fmt.Errorf("task %w", storage.ErrNotFound)).
 		Times(1)
 
 	req := httptest.NewRequest(http.MethodPatch, "/api/tasks/nonexistent", bytes.NewBuffer(patchJSON))
@@ -569,7 +751,7 @@ func TestTaskHandler_HandleTask_PATCH_TaskNotFound(t *testing.T) {
 	handler.HandleTask(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
-	assert.Contains(t, w.Body.String(), "Task not found")
+	assertErrorResponse(t, w, errCodeNotFound, "Task not found")
 }
 
 func TestTaskHandler_HandleTask_PATCH_InvalidJSON(t *testing.T) {
@@ -584,7 +766,7 @@ func TestTaskHandler_HandleTask_PATCH_InvalidJSON(t *testing.T) {
 	existingTask.ID = "task-123"
 
 	mockStorage.EXPECT().
-		GetTask("task-123").
+		GetTask(gomock.Any(), "task-123").
 		Return(existingTask, nil).
 		Times(1)
 
@@ -595,7 +777,7 @@ func TestTaskHandler_HandleTask_PATCH_InvalidJSON(t *testing.T) {
 	handler.HandleTask(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid JSON")
+	assertErrorResponse(t, w, errCodeBadRequest, "Invalid JSON")
 }
 
 func TestTaskHandler_HandleTask_PATCH_ValidationError(t *testing.T) {
@@ -616,13 +798,13 @@ func TestTaskHandler_HandleTask_PATCH_ValidationError(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		GetTask("task-123").
+		GetTask(gomock.Any(), "task-123").
 		Return(existingTask, nil).
 		Times(1)
 
 	validationErr := &models.ValidationError{Message: "Invalid status"}
 	mockStorage.EXPECT().
-		UpdateTask(gomock.Any()).
+		UpdateTask(gomock.Any(), gomock.Any()).
 		Return(validationErr).
 		Times(1)
 
@@ -633,7 +815,7 @@ func TestTaskHandler_HandleTask_PATCH_ValidationError(t *testing.T) {
 	handler.HandleTask(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid status")
+	assertValidationErrorResponse(t, w, "Invalid status")
 }
 
 func TestTaskHandler_HandleReport_GET_Success(t *testing.T) {
@@ -670,14 +852,14 @@ func TestTaskHandler_HandleReport_GET_Success(t *testing.T) {
 
 	// Mock expectations
 	mockStorage.EXPECT().
-		ListTasks(gomock.Any()).
+		ListTasks(gomock.Any(), gomock.Any()).
 		Return(tasks, nil).
 		Times(1)
 
 	// Mock GetTaskLinks for each task
 	for _, task := range tasks {
 		mockStorage.EXPECT().
-			GetTaskLinks(task.ID).
+			GetTaskLinks(gomock.Any(), task.ID).
 			Return([]*models.Link{}, nil).
 			Times(1)
 	}
@@ -690,23 +872,105 @@ func TestTaskHandler_HandleReport_GET_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 
-	var report map[string]interface{}
+	var report models.ReportResponse
 	err := json.Unmarshal(w.Body.Bytes(), &report)
 	require.NoError(t, err)
 
-	// Verify report structure
-	assert.Contains(t, report, "working_on")
-	assert.Contains(t, report, "next_up")
-	assert.Contains(t, report, "blockers")
+	// Verify task distribution
+	require.Len(t, report.WorkingOn, 1) // Only in_progress tasks
+	require.Len(t, report.NextUp, 2)    // in_progress + new tasks
+	require.Len(t, report.Blockers, 1)  // blocked tasks
+	assert.NotNil(t, report.Stale)
 
-	workingOn := report["working_on"].([]interface{})
-	nextUp := report["next_up"].([]interface{})
-	blockers := report["blockers"].([]interface{})
+	assert.Equal(t, "task-1", report.WorkingOn[0].ID)
+	assert.Equal(t, []string{"frontend"}, report.WorkingOn[0].Tags)
+	assert.NotNil(t, report.WorkingOn[0].Links)
+	assert.Equal(t, "task-3", report.Blockers[0].ID)
 
-	// Verify task distribution
-	assert.Len(t, workingOn, 1) // Only in_progress tasks
-	assert.Len(t, nextUp, 2)    // in_progress + new tasks
-	assert.Len(t, blockers, 1)  // blocked tasks
+	// The critical new task comes before the high priority one in progress
+	assert.Equal(t, models.Critical, report.NextUp[0].Priority)
+	assert.Equal(t, models.High, report.NextUp[1].Priority)
+	assert.False(t, report.GeneratedAt.IsZero())
+}
+
+func TestTaskHandler_HandleReport_Stale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	now := time.Now()
+	tasks := []*models.Task{
+		{ID: "fresh", Title: "Fresh", Status: models.InProgress, Priority: models.Normal, UpdatedAt: now.Add(-time.Hour)},
+		{ID: "stale", Title: "Stale", Status: models.InProgress, Priority: models.Normal, UpdatedAt: now.AddDate(0, 0, -10)},
+		{ID: "stalest", Title: "Stalest", Status: models.InProgress, Priority: models.Normal, UpdatedAt: now.AddDate(0, 0, -30)},
+		{ID: "old-new", Title: "Old but not started", Status: models.New, Priority: models.Normal, UpdatedAt: now.AddDate(0, 0, -30)},
+	}
+
+	mockStorage.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Return(tasks, nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), gomock.Any()).Return([]*models.Link{}, nil).Times(len(tasks))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/report?stale_days=7", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleReport(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report models.ReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+
+	var staleIDs []string
+	for _, task := range report.Stale {
+		staleIDs = append(staleIDs, task.ID)
+	}
+	assert.Equal(t, []string{"stalest", "stale"}, staleIDs)
+}
+
+func TestTaskHandler_HandleReport_Summary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	tasks := []*models.Task{
+		{ID: "t1", Status: models.Blocked, Priority: models.Critical},
+		{ID: "t2", Status: models.Blocked, Priority: models.High},
+		{ID: "t3", Status: models.InProgress, Priority: models.High},
+		{ID: "t4", Status: models.InProgress, Priority: models.Normal},
+		{ID: "t5", Status: models.New, Priority: models.Normal},
+		{ID: "t6", Status: models.Done, Priority: models.Minor},
+	}
+
+	mockStorage.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Return(tasks, nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), gomock.Any()).Return([]*models.Link{}, nil).Times(len(tasks))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/report", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleReport(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report models.ReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	require.NotNil(t, report.Summary)
+
+	assert.Equal(t, 6, report.Summary.Total)
+	assert.Equal(t, map[models.Status]int{
+		models.Blocked:    2,
+		models.InProgress: 2,
+		models.New:        1,
+		models.Done:       1,
+	}, report.Summary.ByStatus)
+	assert.Equal(t, map[models.Priority]int{
+		models.Critical: 1,
+		models.High:     2,
+		models.Normal:   2,
+		models.Minor:    1,
+	}, report.Summary.ByPriority)
 }
 
 func TestTaskHandler_HandleReport_MethodNotAllowed(t *testing.T) {
@@ -722,7 +986,7 @@ func TestTaskHandler_HandleReport_MethodNotAllowed(t *testing.T) {
 	handler.HandleReport(w, req)
 
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
-	assert.Contains(t, w.Body.String(), "Method not allowed")
+	assertErrorResponse(t, w, errCodeMethodNotAllowed, "Method not allowed")
 }
 
 func TestTaskHandler_HandleReport_StorageError(t *testing.T) {
@@ -734,7 +998,7 @@ func TestTaskHandler_HandleReport_StorageError(t *testing.T) {
 
 	// Mock storage error
 	mockStorage.EXPECT().
-		ListTasks(gomock.Any()).
+		ListTasks(gomock.Any(), gomock.Any()).
 		Return(nil, fmt.Errorf("database connection failed")).
 		Times(1)
 
@@ -744,7 +1008,7 @@ func TestTaskHandler_HandleReport_StorageError(t *testing.T) {
 	handler.HandleReport(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "Failed to generate report")
+	assertErrorResponse(t, w, errCodeInternal, "Failed to generate report")
 }
 
 func TestTaskHandler_HandleLinks_POST_Success(t *testing.T) {
@@ -765,8 +1029,8 @@ func TestTaskHandler_HandleLinks_POST_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		CreateLink(gomock.Any()).
-		DoAndReturn(func(linkArg *models.Link) error {
+		CreateLink(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, linkArg *models.Link) error {
 			// Verify the link data was parsed correctly
 			assert.Equal(t, link.TaskID, linkArg.TaskID)
 			assert.Equal(t, link.Type, linkArg.Type)
@@ -799,7 +1063,7 @@ func TestTaskHandler_HandleLinks_MethodNotAllowed(t *testing.T) {
 	handler.HandleLinks(w, req)
 
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
-	assert.Contains(t, w.Body.String(), "Method not allowed")
+	assertErrorResponse(t, w, errCodeMethodNotAllowed, "Method not allowed")
 }
 
 func TestTaskHandler_HandleLink_GET_Success(t *testing.T) {
@@ -820,7 +1084,7 @@ func TestTaskHandler_HandleLink_GET_Success(t *testing.T) {
 	}
 
 	mockStorage.EXPECT().
-		GetLink("link-123").
+		GetLink(gomock.Any(), "link-123").
 		Return(link, nil).
 		Times(1)
 
@@ -852,7 +1116,7 @@ func TestTaskHandler_HandleLink_NoLinkID(t *testing.T) {
 	handler.HandleLink(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Link ID required")
+	assertErrorResponse(t, w, errCodeBadRequest, "Link ID required")
 }
 
 func TestTaskHandler_HandleLink_MethodNotAllowed(t *testing.T) {
@@ -868,7 +1132,7 @@ func TestTaskHandler_HandleLink_MethodNotAllowed(t *testing.T) {
 	handler.HandleLink(w, req)
 
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
-	assert.Contains(t, w.Body.String(), "Method not allowed")
+	assertErrorResponse(t, w, errCodeMethodNotAllowed, "Method not allowed")
 }
 
 func TestTaskHandler_CreateLink_ValidationError(t *testing.T) {
@@ -894,7 +1158,7 @@ func TestTaskHandler_CreateLink_ValidationError(t *testing.T) {
 	handler.HandleLinks(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "task_id is required")
+	assertValidationErrorResponse(t, w, "task_id is required")
 }
 
 func TestTaskHandler_CreateLink_InvalidJSON(t *testing.T) {
@@ -911,7 +1175,7 @@ func TestTaskHandler_CreateLink_InvalidJSON(t *testing.T) {
 	handler.HandleLinks(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid JSON")
+	assertErrorResponse(t, w, errCodeBadRequest, "Invalid JSON")
 }
 
 func TestTaskHandler_HandleComments_POST_Success(t *testing.T) {
@@ -930,8 +1194,8 @@ func TestTaskHandler_HandleComments_POST_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		CreateComment(gomock.Any()).
-		DoAndReturn(func(commentArg *models.Comment) error {
+		CreateComment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, commentArg *models.Comment) error {
 			// Verify the comment data was parsed correctly
 			assert.Equal(t, comment.TaskID, commentArg.TaskID)
 			assert.Equal(t, comment.Content, commentArg.Content)
@@ -962,7 +1226,7 @@ func TestTaskHandler_HandleComments_MethodNotAllowed(t *testing.T) {
 	handler.HandleComments(w, req)
 
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
-	assert.Contains(t, w.Body.String(), "Method not allowed")
+	assertErrorResponse(t, w, errCodeMethodNotAllowed, "Method not allowed")
 }
 
 func TestTaskHandler_HandleComment_DELETE_Success(t *testing.T) {
@@ -973,7 +1237,7 @@ func TestTaskHandler_HandleComment_DELETE_Success(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		DeleteComment("comment-123").
+		DeleteComment(gomock.Any(), "comment-123").
 		Return(nil).
 		Times(1)
 
@@ -1004,7 +1268,7 @@ func TestTaskHandler_HandleComment_NoCommentID(t *testing.T) {
 	handler.HandleComment(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Comment ID required")
+	assertErrorResponse(t, w, errCodeBadRequest, "Comment ID required")
 }
 
 func TestTaskHandler_HandleComment_MethodNotAllowed(t *testing.T) {
@@ -1020,7 +1284,7 @@ func TestTaskHandler_HandleComment_MethodNotAllowed(t *testing.T) {
 	handler.HandleComment(w, req)
 
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
-	assert.Contains(t, w.Body.String(), "Method not allowed")
+	assertErrorResponse(t, w, errCodeMethodNotAllowed, "Method not allowed")
 }
 
 func TestTaskHandler_CreateComment_ValidationError(t *testing.T) {
@@ -1045,7 +1309,9 @@ func TestTaskHandler_CreateComment_ValidationError(t *testing.T) {
 	handler.HandleComments(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "task_id is required")
+	fieldErr := assertValidationErrorResponse(t, w, "task_id is required")
+	assert.Equal(t, "task_id", fieldErr.Field)
+	assert.Equal(t, models.CodeRequired, fieldErr.Code)
 }
 
 func TestTaskHandler_CreateComment_InvalidJSON(t *testing.T) {
@@ -1062,7 +1328,7 @@ func TestTaskHandler_CreateComment_InvalidJSON(t *testing.T) {
 	handler.HandleComments(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid request body")
+	assertErrorResponse(t, w, errCodeBadRequest, "Invalid request body")
 }
 
 func TestTaskHandler_DeleteComment_StorageError(t *testing.T) {
@@ -1073,7 +1339,7 @@ func TestTaskHandler_DeleteComment_StorageError(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		DeleteComment("comment-123").
+		DeleteComment(gomock.Any(), "comment-123").
 		Return(fmt.Errorf("database connection failed")).
 		Times(1)
 
@@ -1083,7 +1349,7 @@ func TestTaskHandler_DeleteComment_StorageError(t *testing.T) {
 	handler.HandleComment(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "Failed to delete comment")
+	assertErrorResponse(t, w, errCodeInternal, "Failed to delete comment")
 }
 
 func TestTaskHandler_UpdateTask_ValidationError(t *testing.T) {
@@ -1100,7 +1366,7 @@ func TestTaskHandler_UpdateTask_ValidationError(t *testing.T) {
 
 	validationErr := &models.ValidationError{Message: "Title is required"}
 	mockStorage.EXPECT().
-		UpdateTask(gomock.Any()).
+		UpdateTask(gomock.Any(), gomock.Any()).
 		Return(validationErr).
 		Times(1)
 
@@ -1111,7 +1377,7 @@ func TestTaskHandler_UpdateTask_ValidationError(t *testing.T) {
 	handler.HandleTask(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Title is required")
+	assertValidationErrorResponse(t, w, "Title is required")
 }
 
 func TestTaskHandler_UpdateTask_StorageError(t *testing.T) {
@@ -1126,7 +1392,7 @@ func TestTaskHandler_UpdateTask_StorageError(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		UpdateTask(gomock.Any()).
+		UpdateTask(gomock.Any(), gomock.Any()).
 		Return(fmt.Errorf("database connection failed")).
 		Times(1)
 
@@ -1137,7 +1403,7 @@ func TestTaskHandler_UpdateTask_StorageError(t *testing.T) {
 	handler.HandleTask(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "database connection failed")
+	assertErrorResponse(t, w, errCodeInternal, "Failed to update task")
 }
 
 func TestTaskHandler_DeleteTask_StorageError(t *testing.T) {
@@ -1148,7 +1414,7 @@ func TestTaskHandler_DeleteTask_StorageError(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		DeleteTask("task-123").
+		DeleteTask(gomock.Any(), "task-123").
 		Return(fmt.Errorf("database connection failed")).
 		Times(1)
 
@@ -1158,7 +1424,7 @@ func TestTaskHandler_DeleteTask_StorageError(t *testing.T) {
 	handler.HandleTask(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "database connection failed")
+	assertErrorResponse(t, w, errCodeInternal, "Failed to delete task")
 }
 
 func TestTaskHandler_ListTasks_WithComplexFilters(t *testing.T) {
@@ -1167,12 +1433,13 @@ func TestTaskHandler_ListTasks_WithComplexFilters(t *testing.T) {
 
 	mockStorage := mocks.NewMockStorage(ctrl)
 	handler := NewTaskHandler(mockStorage)
+	allowTaskRelationCounts(mockStorage)
 
 	expectedTasks := []*models.Task{createValidTask()}
 
 	mockStorage.EXPECT().
-		ListTasks(gomock.Any()).
-		DoAndReturn(func(filters storage.TaskFilters) ([]*models.Task, error) {
+		ListTasks(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filters storage.TaskFilters) ([]*models.Task, error) {
 			// Verify that complex filters are passed correctly
 			assert.True(t, filters.IncludeArchived)
 			assert.Equal(t, 50, filters.Limit)
@@ -1183,8 +1450,10 @@ func TestTaskHandler_ListTasks_WithComplexFilters(t *testing.T) {
 			return expectedTasks, nil
 		}).
 		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
 
-	req := httptest.NewRequest(http.MethodGet, "/api/tasks?status=new,in_progress&priority=high,critical&tags=frontend,backend&include_archived=true&limit=50&offset=10", nil)
+	// Tags are stored normalized, so the filter is normalized the same way
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks?status=new,in_progress&priority=high,critical&tags=Frontend,%20backend%20&include_archived=true&limit=50&offset=10", nil)
 	w := httptest.NewRecorder()
 
 	handler.HandleTasks(w, req)
@@ -1198,13 +1467,15 @@ func TestTaskHandler_ListTasks_InvalidLimit(t *testing.T) {
 
 	mockStorage := mocks.NewMockStorage(ctrl)
 	handler := NewTaskHandler(mockStorage)
+	allowTaskRelationCounts(mockStorage)
 
 	expectedTasks := []*models.Task{createValidTask()}
 
 	mockStorage.EXPECT().
-		ListTasks(gomock.Any()).
+		ListTasks(gomock.Any(), gomock.Any()).
 		Return(expectedTasks, nil).
 		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks?limit=invalid", nil)
 	w := httptest.NewRecorder()
@@ -1221,13 +1492,15 @@ func TestTaskHandler_ListTasks_InvalidOffset(t *testing.T) {
 
 	mockStorage := mocks.NewMockStorage(ctrl)
 	handler := NewTaskHandler(mockStorage)
+	allowTaskRelationCounts(mockStorage)
 
 	expectedTasks := []*models.Task{createValidTask()}
 
 	mockStorage.EXPECT().
-		ListTasks(gomock.Any()).
+		ListTasks(gomock.Any(), gomock.Any()).
 		Return(expectedTasks, nil).
 		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks?offset=invalid", nil)
 	w := httptest.NewRecorder()
@@ -1259,8 +1532,8 @@ func TestTaskHandler_UpdateLink_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		UpdateLink(gomock.Any()).
-		DoAndReturn(func(linkArg *models.Link) error {
+		UpdateLink(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, linkArg *models.Link) error {
 			// Verify the link data was parsed correctly
 			assert.Equal(t, link.ID, linkArg.ID)
 			assert.Equal(t, link.Title, linkArg.Title)
@@ -1279,6 +1552,39 @@ func TestTaskHandler_UpdateLink_Success(t *testing.T) {
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 }
 
+func TestTaskHandler_UpdateLink_MergeMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	link := createValidLink()
+	link.Metadata = `{"b":2}`
+	linkJSON, err := json.Marshal(link)
+	require.NoError(t, err)
+
+	mockStorage.EXPECT().
+		UpdateLinkWithOptions(gomock.Any(), gomock.Any(), storage.UpdateLinkOptions{MergeMetadata: true}).
+		DoAndReturn(func(_ context.Context, linkArg *models.Link, _ storage.UpdateLinkOptions) error {
+			linkArg.Metadata = `{"a":1,"b":2}`
+			return nil
+		}).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/links/"+link.ID+"?merge_metadata=true", bytes.NewBuffer(linkJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.HandleLink(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Link
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, `{"a":1,"b":2}`, response.Metadata)
+}
+
 func TestTaskHandler_UpdateLink_InvalidJSON(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1293,7 +1599,7 @@ func TestTaskHandler_UpdateLink_InvalidJSON(t *testing.T) {
 	handler.HandleLink(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "Invalid JSON")
+	assertErrorResponse(t, w, errCodeBadRequest, "Invalid JSON")
 }
 
 func TestTaskHandler_UpdateLink_ValidationError(t *testing.T) {
@@ -1316,7 +1622,7 @@ func TestTaskHandler_UpdateLink_ValidationError(t *testing.T) {
 
 	validationErr := &models.ValidationError{Message: "URL is required"}
 	mockStorage.EXPECT().
-		UpdateLink(gomock.Any()).
+		UpdateLink(gomock.Any(), gomock.Any()).
 		Return(validationErr).
 		Times(1)
 
@@ -1327,7 +1633,119 @@ func TestTaskHandler_UpdateLink_ValidationError(t *testing.T) {
 	handler.HandleLink(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), "URL is required")
+	assertValidationErrorResponse(t, w, "URL is required")
+}
+
+func setupLinkMove(t *testing.T) (*TaskHandler, *MockWebStorage, *models.Task, *models.Task) {
+	store := NewMockWebStorage()
+	source := &models.Task{Title: "Wrong ticket"}
+	target := &models.Task{Title: "Right ticket"}
+	require.NoError(t, store.CreateTask(context.Background(), source))
+	require.NoError(t, store.CreateTask(context.Background(), target))
+
+	link := createValidLink()
+	link.TaskID = source.ID
+	require.NoError(t, store.CreateLink(context.Background(), link))
+
+	return NewTaskHandler(store), store, source, target
+}
+
+func TestTaskHandler_MoveLink(t *testing.T) {
+	handler, store, source, target := setupLinkMove(t)
+
+	body := fmt.Sprintf(`{"task_id":%q}`, target.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/links/link-123/move", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleLink(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var moved models.Link
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &moved))
+	assert.Equal(t, "link-123", moved.ID)
+	assert.Equal(t, target.ID, moved.TaskID)
+
+	assert.Empty(t, store.links[source.ID])
+	require.Len(t, store.links[target.ID], 1)
+	assert.Equal(t, "link-123", store.links[target.ID][0].ID)
+}
+
+func TestTaskHandler_CopyLink(t *testing.T) {
+	handler, store, source, target := setupLinkMove(t)
+
+	body := fmt.Sprintf(`{"task_id":%q}`, target.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/links/link-123/move?copy=true", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleLink(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var copied models.Link
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &copied))
+	assert.NotEqual(t, "link-123", copied.ID)
+	assert.Equal(t, target.ID, copied.TaskID)
+	assert.Equal(t, "/api/links/"+copied.ID, w.Header().Get("Location"))
+
+	require.Len(t, store.links[source.ID], 1)
+	assert.Equal(t, "link-123", store.links[source.ID][0].ID)
+	require.Len(t, store.links[target.ID], 1)
+	assert.Equal(t, "https://github.com/company/repo/pull/123", store.links[target.ID][0].URL)
+}
+
+func TestTaskHandler_MoveLink_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{"invalid JSON", "/api/links/link-123/move", `{`, http.StatusBadRequest},
+		{"missing task_id", "/api/links/link-123/move", `{}`, http.StatusBadRequest},
+		{"unknown link", "/api/links/missing/move", `{"task_id":"task-2"}`, http.StatusNotFound},
+		{"unknown target task", "/api/links/link-123/move", `{"task_id":"missing"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, store, source, _ := setupLinkMove(t)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.HandleLink(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Len(t, store.links[source.ID], 1)
+		})
+	}
+}
+
+func TestTaskHandler_MoveLink_DeletedWhileMoving(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().
+		GetLink(gomock.Any(), "link-123").
+		Return(&models.Link{ID: "link-123", TaskID: "task-1", Type: models.PullRequest, URL: "https://github.com/company/repo/pull/123"}, nil)
+	mockStorage.EXPECT().
+		GetTask(gomock.Any(), "task-2").
+		Return(&models.Task{ID: "task-2"}, nil)
+	mockStorage.EXPECT().
+		UpdateLink(gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("link %w", storage.ErrNotFound))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/links/link-123/move", strings.NewReader(`{"task_id":"task-2"}`))
+	w := httptest.NewRecorder()
+
+	handler.HandleLink(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assertErrorResponse(t, w, errCodeNotFound, "Link not found")
 }
 
 func TestTaskHandler_DeleteLink_Success(t *testing.T) {
@@ -1338,7 +1756,7 @@ func TestTaskHandler_DeleteLink_Success(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		DeleteLink("link-123").
+		DeleteLink(gomock.Any(), "link-123").
 		Return(nil).
 		Times(1)
 
@@ -1360,7 +1778,7 @@ func TestTaskHandler_DeleteLink_StorageError(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		DeleteLink("link-123").
+		DeleteLink(gomock.Any(), "link-123").
 		Return(fmt.Errorf("database connection failed")).
 		Times(1)
 
@@ -1370,7 +1788,7 @@ func TestTaskHandler_DeleteLink_StorageError(t *testing.T) {
 	handler.HandleLink(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "Failed to delete link")
+	assertErrorResponse(t, w, errCodeInternal, "Failed to delete link")
 }
 
 func TestTaskHandler_GetLink_NotFound(t *testing.T) {
@@ -1381,8 +1799,8 @@ func TestTaskHandler_GetLink_NotFound(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		GetLink("nonexistent").
-		Return(nil, fmt.Errorf("link not found")).
+		GetLink(gomock.Any(), "nonexistent").
+		Return(nil, fmt.Errorf("link %w", storage.ErrNotFound)).
 		Times(1)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/links/nonexistent", nil)
@@ -1391,7 +1809,7 @@ func TestTaskHandler_GetLink_NotFound(t *testing.T) {
 	handler.HandleLink(w, req)
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
-	assert.Contains(t, w.Body.String(), "Link not found")
+	assertErrorResponse(t, w, errCodeNotFound, "Link not found")
 }
 
 func TestTaskHandler_GetLink_StorageError(t *testing.T) {
@@ -1402,7 +1820,7 @@ func TestTaskHandler_GetLink_StorageError(t *testing.T) {
 	handler := NewTaskHandler(mockStorage)
 
 	mockStorage.EXPECT().
-		GetLink("link-123").
+		GetLink(gomock.Any(), "link-123").
 		Return(nil, fmt.Errorf("database connection failed")).
 		Times(1)
 
@@ -1412,7 +1830,7 @@ func TestTaskHandler_GetLink_StorageError(t *testing.T) {
 	handler.HandleLink(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "Failed to get link")
+	assertErrorResponse(t, w, errCodeInternal, "Failed to get link")
 }
 
 func TestTaskHandler_CreateLink_StorageError(t *testing.T) {
@@ -1433,7 +1851,7 @@ func TestTaskHandler_CreateLink_StorageError(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		CreateLink(gomock.Any()).
+		CreateLink(gomock.Any(), gomock.Any()).
 		Return(fmt.Errorf("database connection failed")).
 		Times(1)
 
@@ -1444,7 +1862,7 @@ func TestTaskHandler_CreateLink_StorageError(t *testing.T) {
 	handler.HandleLinks(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "Failed to create link")
+	assertErrorResponse(t, w, errCodeInternal, "Failed to create link")
 }
 
 func TestTaskHandler_CreateComment_StorageError(t *testing.T) {
@@ -1463,7 +1881,7 @@ func TestTaskHandler_CreateComment_StorageError(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		CreateComment(gomock.Any()).
+		CreateComment(gomock.Any(), gomock.Any()).
 		Return(fmt.Errorf("database connection failed")).
 		Times(1)
 
@@ -1474,7 +1892,40 @@ func TestTaskHandler_CreateComment_StorageError(t *testing.T) {
 	handler.HandleComments(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
-	assert.Contains(t, w.Body.String(), "Failed to create comment")
+	assertErrorResponse(t, w, errCodeInternal, "Failed to create comment")
+}
+
+func TestTaskHandler_CreateComment_TooLong(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	// The storage validates the comment, as both backends do
+	mockStorage.EXPECT().
+		CreateComment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, comment *models.Comment) error {
+			return comment.Validate()
+		}).
+		Times(1)
+
+	commentJSON, err := json.Marshal(&models.Comment{
+		TaskID:  "task-123",
+		Content: strings.Repeat("a", models.MaxCommentLen()+1),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/comments", bytes.NewBuffer(commentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.HandleComments(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	fieldErr := assertValidationErrorResponse(t, w, "content must be at most")
+	assert.Equal(t, "content", fieldErr.Field)
+	assert.Equal(t, models.CodeTooLong, fieldErr.Code)
 }
 
 func TestTaskHandler_GetTask_WithLinksAndComments(t *testing.T) {
@@ -1516,9 +1967,10 @@ func TestTaskHandler_GetTask_WithLinksAndComments(t *testing.T) {
 		},
 	}
 
-	mockStorage.EXPECT().GetTask("task-123").Return(task, nil).Times(1)
-	mockStorage.EXPECT().GetTaskLinks("task-123").Return(links, nil).Times(1)
-	mockStorage.EXPECT().GetTaskComments("task-123").Return(comments, nil).Times(1)
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(task, nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), "task-123").Return(links, nil).Times(1)
+	mockStorage.EXPECT().GetTaskComments(gomock.Any(), "task-123").Return(comments, nil).Times(1)
+	mockStorage.EXPECT().GetRelatedTasks(gomock.Any(), "task-123", relatedTasksLimit).Return([]*models.Task{}, nil).Times(1)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123", nil)
 	w := httptest.NewRecorder()
@@ -1557,9 +2009,10 @@ func TestTaskHandler_GetTask_StorageErrorOnLinks(t *testing.T) {
 
 	task := createValidTask()
 
-	mockStorage.EXPECT().GetTask("task-123").Return(task, nil).Times(1)
-	mockStorage.EXPECT().GetTaskLinks("task-123").Return(nil, fmt.Errorf("database error")).Times(1)
-	mockStorage.EXPECT().GetTaskComments("task-123").Return([]*models.Comment{}, nil).Times(1)
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(task, nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), "task-123").Return(nil, fmt.Errorf("database error")).Times(1)
+	mockStorage.EXPECT().GetTaskComments(gomock.Any(), "task-123").Return([]*models.Comment{}, nil).Times(1)
+	mockStorage.EXPECT().GetRelatedTasks(gomock.Any(), "task-123", relatedTasksLimit).Return([]*models.Task{}, nil).Times(1)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123", nil)
 	w := httptest.NewRecorder()
@@ -1587,9 +2040,10 @@ func TestTaskHandler_GetTask_StorageErrorOnComments(t *testing.T) {
 	task := createValidTask()
 	links := []*models.Link{createValidLink()}
 
-	mockStorage.EXPECT().GetTask("task-123").Return(task, nil).Times(1)
-	mockStorage.EXPECT().GetTaskLinks("task-123").Return(links, nil).Times(1)
-	mockStorage.EXPECT().GetTaskComments("task-123").Return(nil, fmt.Errorf("database error")).Times(1)
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(task, nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), "task-123").Return(links, nil).Times(1)
+	mockStorage.EXPECT().GetTaskComments(gomock.Any(), "task-123").Return(nil, fmt.Errorf("database error")).Times(1)
+	mockStorage.EXPECT().GetRelatedTasks(gomock.Any(), "task-123", relatedTasksLimit).Return([]*models.Task{}, nil).Times(1)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123", nil)
 	w := httptest.NewRecorder()
@@ -1607,6 +2061,25 @@ func TestTaskHandler_GetTask_StorageErrorOnComments(t *testing.T) {
 	assert.Empty(t, response["comments"])
 }
 
+func TestTaskHandler_GetTask_StorageError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(nil, fmt.Errorf("disk I/O error at /var/lib/db")).Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assertErrorResponse(t, w, errCodeInternal, "Failed to get task")
+	assert.NotContains(t, w.Body.String(), "/var/lib/db", "storage details stay in the log")
+}
+
 func TestTaskHandler_CreateTask_WithAllFields(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1627,8 +2100,8 @@ func TestTaskHandler_CreateTask_WithAllFields(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		CreateTask(gomock.Any()).
-		DoAndReturn(func(taskArg *models.Task) error {
+		CreateTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, taskArg *models.Task) error {
 			// Verify all fields were parsed correctly
 			assert.Equal(t, task.Title, taskArg.Title)
 			assert.Equal(t, task.Priority, taskArg.Priority)
@@ -1636,6 +2109,7 @@ func TestTaskHandler_CreateTask_WithAllFields(t *testing.T) {
 			assert.Equal(t, task.Tags, taskArg.Tags)
 			assert.Equal(t, task.Blockers, taskArg.Blockers)
 			assert.Equal(t, task.JiraID, taskArg.JiraID)
+			taskArg.ID = "task-999"
 			return nil
 		}).
 		Times(1)
@@ -1648,6 +2122,7 @@ func TestTaskHandler_CreateTask_WithAllFields(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "/api/tasks/task-999", w.Header().Get("Location"))
 }
 
 func TestTaskHandler_UpdateTask_WithAllFields(t *testing.T) {
@@ -1670,8 +2145,8 @@ func TestTaskHandler_UpdateTask_WithAllFields(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		UpdateTask(gomock.Any()).
-		DoAndReturn(func(taskArg *models.Task) error {
+		UpdateTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, taskArg *models.Task) error {
 			// Verify all fields were parsed correctly
 			assert.Equal(t, "task-123", taskArg.ID) // Should be set from URL
 			assert.Equal(t, task.Title, taskArg.Title)
@@ -1714,8 +2189,8 @@ func TestTaskHandler_CreateLink_WithAllFields(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		CreateLink(gomock.Any()).
-		DoAndReturn(func(linkArg *models.Link) error {
+		CreateLink(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, linkArg *models.Link) error {
 			// Verify all fields were parsed correctly
 			assert.Equal(t, link.TaskID, linkArg.TaskID)
 			assert.Equal(t, link.Type, linkArg.Type)
@@ -1723,6 +2198,7 @@ func TestTaskHandler_CreateLink_WithAllFields(t *testing.T) {
 			assert.Equal(t, link.Title, linkArg.Title)
 			assert.Equal(t, link.Status, linkArg.Status)
 			assert.Equal(t, link.Metadata, linkArg.Metadata)
+			linkArg.ID = "link-999"
 			return nil
 		}).
 		Times(1)
@@ -1735,6 +2211,7 @@ func TestTaskHandler_CreateLink_WithAllFields(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "/api/links/link-999", w.Header().Get("Location"))
 }
 
 func TestTaskHandler_CreateComment_WithAllFields(t *testing.T) {
@@ -1753,8 +2230,8 @@ func TestTaskHandler_CreateComment_WithAllFields(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage.EXPECT().
-		CreateComment(gomock.Any()).
-		DoAndReturn(func(commentArg *models.Comment) error {
+		CreateComment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, commentArg *models.Comment) error {
 			// Verify the comment data was parsed correctly
 			assert.Equal(t, comment.TaskID, commentArg.TaskID)
 			assert.Equal(t, comment.Content, commentArg.Content)
@@ -1771,3 +2248,1669 @@ func TestTaskHandler_CreateComment_WithAllFields(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
 }
+
+func TestTaskHandler_HandleMerge_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	merged := createValidTask()
+	merged.Tags = []string{"backend", "api", "k8s"}
+
+	mockStorage.EXPECT().
+		MergeTasks(gomock.Any(), "task-456", "task-123", storage.MergeOptions{Prefer: storage.PreferSource, DeleteSource: true}).
+		Return(merged, nil).
+		Times(1)
+
+	body := `{"source":"task-456","target":"task-123","prefer":"source","delete_source":true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/merge", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleMerge(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Task
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "task-123", response.ID)
+	assert.Equal(t, []string{"backend", "api", "k8s"}, response.Tags)
+}
+
+func TestTaskHandler_HandleMerge_DefaultsToPreferTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().
+		MergeTasks(gomock.Any(), "task-456", "task-123", storage.MergeOptions{Prefer: storage.PreferTarget}).
+		Return(createValidTask(), nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/merge", strings.NewReader(`{"source":"task-456","target":"task-123"}`))
+	w := httptest.NewRecorder()
+
+	handler.HandleMerge(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTaskHandler_HandleMerge_BadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid JSON", `{invalid`},
+		{"missing target", `{"source":"task-456"}`},
+		{"invalid preference", `{"source":"task-456","target":"task-123","prefer":"newest"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/tasks/merge", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.HandleMerge(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestTaskHandler_HandleMerge_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().
+		MergeTasks(gomock.Any(), "missing", "task-123", gomock.Any()).
+		Return(nil, fmt.Errorf("task %w", storage.ErrNotFound)).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/merge", strings.NewReader(`{"source":"missing","target":"task-123"}`))
+	w := httptest.NewRecorder()
+
+	handler.HandleMerge(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTaskHandler_HandleMerge_MethodNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/merge", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleMerge(w, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestTaskHandler_HandleTask_ListLinks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(createValidTask(), nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), "task-123").Return([]*models.Link{createValidLink()}, nil).Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123/links", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.Link
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, "link-123", response[0].ID)
+}
+
+func TestTaskHandler_HandleTask_ListLinks_Empty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(createValidTask(), nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), "task-123").Return(nil, nil).Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123/links", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+}
+
+func TestTaskHandler_HandleTask_LinksByType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	link := createValidLink()
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(createValidTask(), nil).Times(1)
+	mockStorage.EXPECT().
+		GetTaskLinksByType(gomock.Any(), "task-123", models.PullRequest, models.JiraTicket).
+		Return([]*models.Link{link}, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123/links?type=pull_request,%20jira_ticket", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []*models.Link
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, link.ID, response[0].ID)
+}
+
+func TestTaskHandler_HandleTask_LinksInvalidType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewTaskHandler(mocks.NewMockStorage(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123/links?type=pull_request,bogus", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assertValidationErrorResponse(t, w, `Invalid link type "bogus"`)
+}
+
+func TestTaskHandler_HandleTask_CreateLinkImpliedTaskID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(createValidTask(), nil).Times(1)
+	mockStorage.EXPECT().
+		CreateLink(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, link *models.Link) error {
+			assert.Equal(t, "task-123", link.TaskID)
+			link.ID = "link-456"
+			return nil
+		}).
+		Times(1)
+
+	body := `{"type":"pull_request","url":"https://github.com/company/repo/pull/456"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/task-123/links", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.Link
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "link-456", response.ID)
+	assert.Equal(t, "task-123", response.TaskID)
+	assert.Equal(t, "https://github.com/company/repo/pull/456", response.Title)
+}
+
+func TestTaskHandler_HandleTask_LinksTaskNotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+	}{
+		{name: "list", method: http.MethodGet},
+		{name: "create", method: http.MethodPost, body: `{"type":"pull_request","url":"https://github.com/company/repo/pull/456"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			handler := NewTaskHandler(mockStorage)
+
+			mockStorage.EXPECT().
+				GetTask(gomock.Any(), "nonexistent").
+				Return(nil, fmt.Errorf("task %w", storage.ErrNotFound)).
+				Times(1)
+
+			req := httptest.NewRequest(tt.method, "/api/tasks/nonexistent/links", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.HandleTask(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assertErrorResponse(t, w, errCodeNotFound, "Task not found")
+		})
+	}
+}
+
+func TestTaskHandler_ListTasks_DateFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().
+		ListTasks(gomock.Any(), storage.TaskFilters{
+			Limit:         DefaultPageSize,
+			CreatedAfter:  time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+			UpdatedBefore: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		}).
+		Return([]*models.Task{}, nil).
+		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+	// created_before is malformed and ignored
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/tasks?created_after=2024-01-08T00:00:00Z&created_before=last-week&updated_before=2024-01-15T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTaskHandler_GetTask_ETag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	task := createValidTask()
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(task, nil).Times(2)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), "task-123").Return([]*models.Link{}, nil).Times(2)
+	mockStorage.EXPECT().GetTaskComments(gomock.Any(), "task-123").Return([]*models.Comment{}, nil).Times(2)
+	mockStorage.EXPECT().GetRelatedTasks(gomock.Any(), "task-123", relatedTasksLimit).Return([]*models.Task{}, nil).Times(2)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123", nil)
+	w := httptest.NewRecorder()
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.True(t, strings.HasPrefix(etag, `W/"`))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/tasks/task-123", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+}
+
+func TestTaskHandler_ListTasks_ETag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+	allowTaskRelationCounts(mockStorage)
+
+	tasks := []*models.Task{createValidTask()}
+	mockStorage.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Return(tasks, nil).Times(3)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	w := httptest.NewRecorder()
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// Matching ETag (in a list, compared weakly) yields 304 with no body
+	req = httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("If-None-Match", `"other", `+strings.TrimPrefix(etag, "W/"))
+	w = httptest.NewRecorder()
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	// Data changed: a stale ETag gets the full response
+	tasks[0].Title = "Renamed"
+	req = httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), "Renamed")
+}
+
+func TestTaskHandler_HandleTask_Activity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	activity := []models.Activity{
+		{ID: 1, TaskID: "task-123", Type: models.ActivityTaskCreated, Timestamp: time.Now(), Payload: json.RawMessage(`{}`)},
+		{ID: 2, TaskID: "task-123", Type: models.ActivityStatusChanged, Timestamp: time.Now(), Payload: json.RawMessage(`{"from":"new","to":"done"}`)},
+	}
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(createValidTask(), nil).Times(1)
+	mockStorage.EXPECT().GetTaskActivity(gomock.Any(), "task-123").Return(activity, nil).Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123/activity", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.Activity
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 2)
+	assert.Equal(t, models.ActivityStatusChanged, response[1].Type)
+	assert.JSONEq(t, `{"from":"new","to":"done"}`, string(response[1].Payload))
+}
+
+func TestTaskHandler_HandleTask_Comments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(createValidTask(), nil).Times(1)
+	mockStorage.EXPECT().
+		GetTaskCommentsPaged(gomock.Any(), "task-123", 10, 20).
+		Return([]*models.Comment{createValidComment()}, 21, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123/comments?limit=10&offset=20", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.CommentListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Comments, 1)
+	assert.Equal(t, 21, response.Total)
+	assert.Equal(t, 10, response.Limit)
+	assert.Equal(t, 20, response.Offset)
+}
+
+func TestTaskHandler_HandleTask_CommentsDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(createValidTask(), nil).Times(1)
+	mockStorage.EXPECT().
+		GetTaskCommentsPaged(gomock.Any(), "task-123", DefaultPageSize, 0).
+		Return(nil, 0, nil).
+		Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123/comments?offset=-5", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"comments":[],"total":0,"limit":50,"offset":0}`, w.Body.String())
+}
+
+func TestTaskHandler_HandleTask_ActivityTaskNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().GetTask(gomock.Any(), "nonexistent").Return(nil, fmt.Errorf("task %w", storage.ErrNotFound)).Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/nonexistent/activity", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTaskHandler_CreateLink_InvalidURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{name: "scheme-less", url: "github.com/company/repo/pull/1", expected: "url must be an absolute URL"},
+		{name: "disallowed scheme", url: "ftp://files.example.com/log.txt", expected: `url scheme "ftp" is not allowed`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Storage must not be reached for malformed URLs
+			mockStorage := mocks.NewMockStorage(ctrl)
+			handler := NewTaskHandler(mockStorage)
+
+			body := fmt.Sprintf(`{"task_id":"task-123","type":"other","url":%q}`, tt.url)
+			req := httptest.NewRequest(http.MethodPost, "/api/links", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			handler.HandleLinks(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assertValidationErrorResponse(t, w, tt.expected)
+		})
+	}
+}
+
+func TestTaskHandler_ListTasks_CSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	task := createValidTask()
+	task.Title = `Fix "quoted", comma title`
+	task.CreatedAt = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	task.UpdatedAt = time.Date(2024, 1, 16, 9, 30, 0, 0, time.UTC)
+
+	mockStorage.EXPECT().
+		ListTasks(gomock.Any(), storage.TaskFilters{Status: []models.Status{models.New}, Limit: DefaultPageSize}).
+		Return([]*models.Task{task}, nil).
+		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks?format=csv&status=new", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="michishirube-tasks-`)
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{"id", "jira_id", "title", "priority", "status", "tags", "created_at", "updated_at"}, records[0])
+	assert.Equal(t, []string{
+		"task-123",
+		"TASK-123",
+		`Fix "quoted", comma title`,
+		"normal",
+		"new",
+		"backend|api",
+		"2024-01-15T10:00:00Z",
+		"2024-01-16T09:30:00Z",
+	}, records[1])
+}
+
+func TestTaskHandler_HandleEnsure_Found(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	existing := createValidTask()
+	mockStorage.EXPECT().GetTaskByJiraID(gomock.Any(), "TASK-123").Return(existing, nil).Times(1)
+
+	body := `{"jira_id":"TASK-123","title":"Created by CI","priority":"high"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/ensure", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleEnsure(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Task
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "task-123", response.ID)
+	assert.Equal(t, "Implementation task", response.Title)
+}
+
+func TestTaskHandler_HandleEnsure_NotFoundCreates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().GetTaskByJiraID(gomock.Any(), "TASK-456").Return(nil, fmt.Errorf("task %w", storage.ErrNotFound)).Times(1)
+	mockStorage.EXPECT().
+		CreateTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, task *models.Task) error {
+			assert.Equal(t, "TASK-456", task.JiraID)
+			task.ID = "task-456"
+			return nil
+		}).
+		Times(1)
+
+	body := `{"jira_id":"TASK-456","title":"Created by CI","priority":"high"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/ensure", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleEnsure(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/tasks/task-456", w.Header().Get("Location"))
+
+	var response models.Task
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "task-456", response.ID)
+}
+
+func TestTaskHandler_HandleEnsure_NoJiraAlwaysCreates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	// No lookup for the NO-JIRA sentinel
+	mockStorage.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	body := `{"jira_id":"NO-JIRA","title":"Untracked chore"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/ensure", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleEnsure(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestTaskHandler_HandleEnsure_LookupError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	mockStorage.EXPECT().GetTaskByJiraID(gomock.Any(), "TASK-789").Return(nil, fmt.Errorf("database is locked")).Times(1)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/ensure", strings.NewReader(`{"jira_id":"TASK-789","title":"x"}`))
+	w := httptest.NewRecorder()
+
+	handler.HandleEnsure(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestTaskHandler_ListTasks_PageSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []TaskHandlerOption
+		query         string
+		expectedLimit int
+	}{
+		{name: "default applied", query: "", expectedLimit: DefaultPageSize},
+		{name: "invalid limit uses default", query: "?limit=invalid", expectedLimit: DefaultPageSize},
+		{name: "too large is clamped", query: "?limit=100000", expectedLimit: MaxPageSize},
+		{name: "small value honored", query: "?limit=5", expectedLimit: 5},
+		{name: "configured default", opts: []TaskHandlerOption{WithPageSizes(25, 100)}, query: "", expectedLimit: 25},
+		{name: "configured max", opts: []TaskHandlerOption{WithPageSizes(25, 100)}, query: "?limit=150", expectedLimit: 100},
+		{name: "default above max is capped", opts: []TaskHandlerOption{WithPageSizes(500, 100)}, query: "", expectedLimit: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			handler := NewTaskHandler(mockStorage, tt.opts...)
+
+			mockStorage.EXPECT().
+				ListTasks(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, filters storage.TaskFilters) ([]*models.Task, error) {
+					assert.Equal(t, tt.expectedLimit, filters.Limit)
+					return []*models.Task{}, nil
+				}).
+				Times(1)
+			mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tasks"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleTasks(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, float64(tt.expectedLimit), response["limit"])
+		})
+	}
+}
+
+func TestTaskHandler_MaxBodyBytes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage, WithMaxBodyBytes(256))
+
+	t.Run("oversized body is rejected", func(t *testing.T) {
+		body := fmt.Sprintf(`{"title":%q}`, strings.Repeat("x", 512))
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.HandleTasks(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("normal body is accepted", func(t *testing.T) {
+		mockStorage.EXPECT().CreateTask(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(`{"title":"Small task"}`))
+		w := httptest.NewRecorder()
+
+		handler.HandleTasks(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("update is limited too", func(t *testing.T) {
+		body := fmt.Sprintf(`{"title":%q}`, strings.Repeat("x", 512))
+		req := httptest.NewRequest(http.MethodPut, "/api/tasks/task-123", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.HandleTask(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+func TestTaskHandler_StrictJSON(t *testing.T) {
+	const body = `{"title":"Typo task","priortiy":"high"}`
+
+	t.Run("strict mode rejects unknown fields", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// No storage expectations: the body never gets that far
+		handler := NewTaskHandler(mocks.NewMockStorage(ctrl), WithStrictJSON(true))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.HandleTasks(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertErrorResponse(t, w, errCodeBadRequest, `Unknown field "priortiy"`)
+	})
+
+	t.Run("strict mode rejects unknown patch fields", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStorage := mocks.NewMockStorage(ctrl)
+		handler := NewTaskHandler(mockStorage, WithStrictJSON(true))
+		mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(createValidTask(), nil).Times(1)
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/tasks/task-123", strings.NewReader(`{"stauts":"done"}`))
+		w := httptest.NewRecorder()
+
+		handler.HandleTask(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertErrorResponse(t, w, errCodeBadRequest, `Unknown field "stauts"`)
+	})
+
+	t.Run("lenient mode ignores unknown fields", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockStorage := mocks.NewMockStorage(ctrl)
+		handler := NewTaskHandler(mockStorage)
+		mockStorage.EXPECT().
+			CreateTask(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, task *models.Task) error {
+				assert.Equal(t, "Typo task", task.Title)
+				assert.Empty(t, task.Priority)
+				return nil
+			}).
+			Times(1)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.HandleTasks(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+func TestTaskHandler_Options(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		handle        func(h *TaskHandler) http.HandlerFunc
+		expectedAllow string
+	}{
+		{"tasks", "/api/tasks", func(h *TaskHandler) http.HandlerFunc { return h.HandleTasks }, "GET, POST"},
+		{"task", "/api/tasks/task-123", func(h *TaskHandler) http.HandlerFunc { return h.HandleTask }, "GET, PUT, PATCH, DELETE"},
+		{"task links", "/api/tasks/task-123/links", func(h *TaskHandler) http.HandlerFunc { return h.HandleTask }, "GET, POST"},
+		{"task activity", "/api/tasks/task-123/activity", func(h *TaskHandler) http.HandlerFunc { return h.HandleTask }, "GET"},
+		{"links", "/api/links", func(h *TaskHandler) http.HandlerFunc { return h.HandleLinks }, "POST"},
+		{"link", "/api/links/link-123", func(h *TaskHandler) http.HandlerFunc { return h.HandleLink }, "GET, PUT, DELETE"},
+		{"comments", "/api/comments", func(h *TaskHandler) http.HandlerFunc { return h.HandleComments }, "POST"},
+		{"comment", "/api/comments/comment-123", func(h *TaskHandler) http.HandlerFunc { return h.HandleComment }, "DELETE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := NewTaskHandler(mocks.NewMockStorage(ctrl))
+
+			req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			tt.handle(handler)(w, req)
+
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			assert.Equal(t, tt.expectedAllow, w.Header().Get("Allow"))
+			assert.Empty(t, w.Body.String())
+		})
+	}
+}
+
+func TestTaskHandler_HeadTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	task := createValidTask()
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(task, nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), "task-123").Return([]*models.Link{}, nil).AnyTimes()
+	mockStorage.EXPECT().GetTaskComments(gomock.Any(), "task-123").Return([]*models.Comment{}, nil).AnyTimes()
+	mockStorage.EXPECT().GetRelatedTasks(gomock.Any(), "task-123", relatedTasksLimit).Return([]*models.Task{}, nil).AnyTimes()
+
+	req := httptest.NewRequest(http.MethodHead, "/api/tasks/task-123", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestTaskHandler_HeadTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+	allowTaskRelationCounts(mockStorage)
+
+	mockStorage.EXPECT().
+		ListTasks(gomock.Any(), gomock.Any()).
+		Return([]*models.Task{createValidTask()}, nil).
+		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+	req := httptest.NewRequest(http.MethodHead, "/api/tasks", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestTaskHandler_ListTasks_Cursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+	allowTaskRelationCounts(mockStorage)
+
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	page := []*models.Task{
+		{ID: "task-3", CreatedAt: created.Add(2 * time.Minute)},
+		{ID: "task-2", CreatedAt: created.Add(time.Minute)},
+	}
+	after := &storage.TaskCursor{CreatedAt: created.Add(3 * time.Minute), ID: "task-4"}
+
+	mockStorage.EXPECT().
+		ListTasks(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filters storage.TaskFilters) ([]*models.Task, error) {
+			require.NotNil(t, filters.After)
+			assert.Equal(t, "task-4", filters.After.ID)
+			assert.True(t, after.CreatedAt.Equal(filters.After.CreatedAt))
+			return page, nil
+		}).
+		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks?limit=2&after="+after.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response models.TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotEmpty(t, response.NextCursor)
+
+	next, err := storage.DecodeTaskCursor(response.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, "task-2", next.ID)
+}
+
+func TestTaskHandler_ListTasks_CursorLastPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+	allowTaskRelationCounts(mockStorage)
+
+	mockStorage.EXPECT().
+		ListTasks(gomock.Any(), gomock.Any()).
+		Return([]*models.Task{createValidTask()}, nil).
+		Times(1)
+	mockStorage.EXPECT().CountTasks(gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks?limit=2", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotContains(t, response, "next_cursor")
+}
+
+func TestTaskHandler_ListTasks_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewTaskHandler(mocks.NewMockStorage(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks?after=garbage!", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTaskHandler_GetTask_Related(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	related := createValidTask()
+	related.ID = "task-456"
+	related.Title = "Shares the k8s tag"
+
+	mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(createValidTask(), nil).Times(1)
+	mockStorage.EXPECT().GetTaskLinks(gomock.Any(), "task-123").Return([]*models.Link{}, nil).Times(1)
+	mockStorage.EXPECT().GetTaskComments(gomock.Any(), "task-123").Return([]*models.Comment{}, nil).Times(1)
+	mockStorage.EXPECT().GetRelatedTasks(gomock.Any(), "task-123", relatedTasksLimit).Return([]*models.Task{related}, nil).Times(1)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123", nil)
+	w := httptest.NewRecorder()
+
+	handler.HandleTask(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response models.TaskWithDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Related, 1)
+	assert.Equal(t, "task-456", response.Related[0].ID)
+}
+
+func TestTaskHandler_HandleTask_ArchiveShortcuts(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		currentStatus  models.Status
+		expectUpdate   bool
+		expectedStatus models.Status
+	}{
+		{
+			name:           "archive",
+			path:           "/api/tasks/task-123/archive",
+			currentStatus:  models.InProgress,
+			expectUpdate:   true,
+			expectedStatus: models.Archived,
+		},
+		{
+			name:           "archive already archived",
+			path:           "/api/tasks/task-123/archive",
+			currentStatus:  models.Archived,
+			expectUpdate:   false,
+			expectedStatus: models.Archived,
+		},
+		{
+			name:           "unarchive",
+			path:           "/api/tasks/task-123/unarchive",
+			currentStatus:  models.Archived,
+			expectUpdate:   true,
+			expectedStatus: models.New,
+		},
+		{
+			name:           "unarchive active task",
+			path:           "/api/tasks/task-123/unarchive",
+			currentStatus:  models.InProgress,
+			expectUpdate:   false,
+			expectedStatus: models.InProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			handler := NewTaskHandler(mockStorage)
+
+			task := createValidTask()
+			task.Status = tt.currentStatus
+
+			mockStorage.EXPECT().GetTask(gomock.Any(), "task-123").Return(task, nil).Times(1)
+			if tt.expectUpdate {
+				mockStorage.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, updated *models.Task) error {
+						assert.Equal(t, tt.expectedStatus, updated.Status)
+						return nil
+					}).
+					Times(1)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleTask(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response models.Task
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedStatus, response.Status)
+		})
+	}
+}
+
+func TestTaskHandler_HandleTask_ArchiveShortcuts_Errors(t *testing.T) {
+	for _, action := range []string{"archive", "unarchive", "star", "unstar", "duplicate"} {
+		t.Run(action+" not found", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStorage := mocks.NewMockStorage(ctrl)
+			handler := NewTaskHandler(mockStorage)
+
+			mockStorage.EXPECT().
+				GetTask(gomock.Any(), "missing").
+				Return(nil, fmt.Errorf("task %w", storage.ErrNotFound)).
+				Times(1)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tasks/missing/"+action, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleTask(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assertErrorResponse(t, w, errCodeNotFound, "Task not found")
+		})
+
+		t.Run(action+" method not allowed", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := NewTaskHandler(mocks.NewMockStorage(ctrl))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tasks/task-123/"+action, nil)
+			w := httptest.NewRecorder()
+
+			handler.HandleTask(w, req)
+
+			assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		})
+	}
+}
+
+func TestTaskHandler_ListTasks_NDJSON(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	handler := NewTaskHandler(store, WithPageSizes(2, 2))
+
+	titles := []string{"First", "Second", "Third"}
+	for _, title := range titles {
+		require.NoError(t, store.CreateTask(ctx, &models.Task{JiraID: "NO-JIRA", Title: title, Priority: models.Normal, Status: models.New}))
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	w := httptest.NewRecorder()
+
+	handler.HandleTasks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	require.Len(t, lines, 3, "streams aren't cut to the page size")
+	for i, line := range lines {
+		var task models.Task
+		require.NoError(t, json.Unmarshal([]byte(line), &task), "line %d is a JSON object", i)
+		assert.Equal(t, titles[len(titles)-1-i], task.Title, "newest first")
+	}
+
+	// An explicit limit still applies
+	req = httptest.NewRequest(http.MethodGet, "/api/tasks?limit=1", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	w = httptest.NewRecorder()
+	handler.HandleTasks(w, req)
+	assert.Equal(t, 1, strings.Count(w.Body.String(), "\n"))
+
+	// Without the Accept header the response stays a JSON document
+	w = httptest.NewRecorder()
+	handler.HandleTasks(w, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
+
+func TestTaskHandler_HandleTask_Star(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	handler := NewTaskHandler(store)
+
+	older := &models.Task{JiraID: "NO-JIRA", Title: "Older", Priority: models.Normal, Status: models.New}
+	require.NoError(t, store.CreateTask(ctx, older))
+	time.Sleep(2 * time.Millisecond)
+	newer := &models.Task{JiraID: "NO-JIRA", Title: "Newer", Priority: models.Normal, Status: models.New}
+	require.NoError(t, store.CreateTask(ctx, newer))
+
+	post := func(path string) models.Task {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		w := httptest.NewRecorder()
+		handler.HandleTask(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var task models.Task
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &task))
+		return task
+	}
+	list := func(query string) []string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/tasks"+query, nil)
+		w := httptest.NewRecorder()
+		handler.HandleTasks(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response models.TaskListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		titles := make([]string, len(response.Tasks))
+		for i, task := range response.Tasks {
+			titles[i] = task.Title
+		}
+		return titles
+	}
+	get := func(id string) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/tasks/"+id, nil)
+		w := httptest.NewRecorder()
+		handler.HandleTask(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var task map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &task))
+		return task
+	}
+
+	assert.Equal(t, false, get(older.ID)["starred"], "unstarred tasks say so too")
+
+	starred := post("/api/tasks/" + older.ID + "/star")
+	assert.True(t, starred.Starred)
+	assert.True(t, post("/api/tasks/"+older.ID+"/star").Starred, "starring twice is a no-op")
+	assert.Equal(t, true, get(older.ID)["starred"])
+
+	assert.Equal(t, []string{"Older", "Newer"}, list(""), "starred tasks come first")
+	assert.Equal(t, []string{"Older"}, list("?starred=true"))
+	assert.Equal(t, []string{"Newer"}, list("?starred=false"))
+
+	assert.False(t, post("/api/tasks/"+older.ID+"/unstar").Starred)
+	assert.Equal(t, false, get(older.ID)["starred"])
+	assert.Equal(t, []string{"Newer", "Older"}, list(""))
+	assert.Empty(t, list("?starred=true"))
+
+	// PATCH can star a task as well
+	req := httptest.NewRequest(http.MethodPatch, "/api/tasks/"+newer.ID, strings.NewReader(`{"starred": true}`))
+	w := httptest.NewRecorder()
+	handler.HandleTask(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	got, err := store.GetTask(ctx, newer.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Starred)
+	assert.Equal(t, true, get(newer.ID)["starred"])
+}
+
+func TestTaskHandler_HandleTask_Duplicate(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	handler := NewTaskHandler(store)
+
+	source := &models.Task{
+		JiraID:   "OCPBUGS-1",
+		Title:    "Rotate certificates",
+		Priority: models.High,
+		Status:   models.Blocked,
+		Tags:     []string{"security", "etcd"},
+		Blockers: []string{"Waiting on infra"},
+	}
+	require.NoError(t, store.CreateTask(ctx, source))
+	require.NoError(t, store.CreateLink(ctx, &models.Link{TaskID: source.ID, Type: models.PullRequest, URL: "https://github.com/org/repo/pull/1", Status: "merged"}))
+	require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: source.ID, Content: "Only on the original"}))
+	time.Sleep(2 * time.Millisecond)
+
+	t.Run("without links", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks/"+source.ID+"/duplicate", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleTask(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var copied models.Task
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &copied))
+		assert.Equal(t, "/api/tasks/"+copied.ID, w.Header().Get("Location"))
+
+		assert.NotEqual(t, source.ID, copied.ID)
+		assert.True(t, copied.CreatedAt.After(source.CreatedAt))
+		assert.True(t, copied.UpdatedAt.After(source.UpdatedAt))
+		assert.Equal(t, "Rotate certificates (copy)", copied.Title)
+		assert.Equal(t, models.New, copied.Status)
+		assert.Equal(t, models.High, copied.Priority)
+		assert.Equal(t, []string{"security", "etcd"}, copied.Tags)
+		assert.Empty(t, copied.Blockers)
+		assert.Equal(t, models.NoJiraID(), copied.JiraID)
+
+		links, err := store.GetTaskLinks(ctx, copied.ID)
+		require.NoError(t, err)
+		assert.Empty(t, links)
+		comments, err := store.GetTaskComments(ctx, copied.ID)
+		require.NoError(t, err)
+		assert.Empty(t, comments)
+	})
+
+	t.Run("with links and no suffix", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks/"+source.ID+"/duplicate?links=true&suffix=false", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleTask(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code)
+		var copied struct {
+			models.Task
+			Links []*models.Link `json:"links"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &copied))
+		assert.Equal(t, "Rotate certificates", copied.Title)
+		require.Len(t, copied.Links, 1)
+
+		links, err := store.GetTaskLinks(ctx, copied.ID)
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		assert.Equal(t, "https://github.com/org/repo/pull/1", links[0].URL)
+		assert.Equal(t, copied.ID, links[0].TaskID)
+
+		original, err := store.GetTaskLinks(ctx, source.ID)
+		require.NoError(t, err)
+		require.Len(t, original, 1, "source keeps its link")
+		assert.NotEqual(t, original[0].ID, links[0].ID)
+
+		comments, err := store.GetTaskComments(ctx, copied.ID)
+		require.NoError(t, err)
+		assert.Empty(t, comments)
+	})
+}
+
+func TestTaskHandler_ListTasks_PaginationHeaders(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	handler := NewTaskHandler(store)
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, store.CreateTask(ctx, &models.Task{Title: fmt.Sprintf("Task %d", i), Tags: []string{"api"}}))
+	}
+	require.NoError(t, store.CreateTask(ctx, &models.Task{Title: "Archived", Status: models.Archived}))
+
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/tasks"+query, nil)
+		w := httptest.NewRecorder()
+		handler.HandleTasks(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w
+	}
+
+	w := list("?limit=2&offset=2&tags=api")
+	assert.Equal(t, "5", w.Header().Get("X-Total-Count"), "counts every matching task, not just the page")
+	assert.Equal(t,
+		`</api/tasks?limit=2&offset=4&tags=api>; rel="next", </api/tasks?limit=2&offset=0&tags=api>; rel="prev"`,
+		w.Header().Get("Link"))
+
+	w = list("?limit=2")
+	assert.Equal(t, `</api/tasks?limit=2&offset=2>; rel="next"`, w.Header().Get("Link"), "no prev on the first page")
+
+	w = list("?limit=2&offset=4")
+	assert.Equal(t, `</api/tasks?limit=2&offset=2>; rel="prev"`, w.Header().Get("Link"), "no next on the last page")
+
+	w = list("?include_archived=true")
+	assert.Equal(t, "6", w.Header().Get("X-Total-Count"))
+	assert.Empty(t, w.Header().Get("Link"), "everything fits on one page")
+}
+
+func TestTaskHandler_HandleGrouped(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	handler := NewTaskHandler(store)
+
+	for _, task := range []*models.Task{
+		{Title: "Triage", Status: models.New},
+		{Title: "Write patch", Status: models.InProgress, Tags: []string{"frontend"}},
+		{Title: "Review patch", Status: models.InProgress},
+		{Title: "Old work", Status: models.Archived},
+	} {
+		require.NoError(t, store.CreateTask(ctx, task))
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	grouped := func(query string) map[string][]*models.Task {
+		req := httptest.NewRequest(http.MethodGet, "/api/tasks/grouped"+query, nil)
+		w := httptest.NewRecorder()
+		handler.HandleGrouped(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var groups map[string][]*models.Task
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &groups))
+		return groups
+	}
+
+	titles := func(tasks []*models.Task) []string {
+		out := []string{}
+		for _, task := range tasks {
+			out = append(out, task.Title)
+		}
+		return out
+	}
+
+	groups := grouped("")
+	assert.Len(t, groups, 4)
+	assert.Equal(t, []string{"Triage"}, titles(groups["new"]))
+	assert.Equal(t, []string{"Review patch", "Write patch"}, titles(groups["in_progress"]))
+	assert.NotContains(t, groups, "archived")
+
+	groups = grouped("?tags=Frontend")
+	assert.Equal(t, []string{"Write patch"}, titles(groups["in_progress"]), "tag filters ignore case")
+	assert.Empty(t, groups["new"])
+
+	// Empty buckets are sent as [] rather than null or left out
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/grouped", nil)
+	w := httptest.NewRecorder()
+	handler.HandleGrouped(w, req)
+	assert.Contains(t, w.Body.String(), `"blocked":[]`)
+	assert.Contains(t, w.Body.String(), `"done":[]`)
+
+	groups = grouped("?include_archived=true")
+	assert.Len(t, groups, 5)
+	assert.Equal(t, []string{"Old work"}, titles(groups["archived"]))
+
+	req = httptest.NewRequest(http.MethodPost, "/api/tasks/grouped", nil)
+	w = httptest.NewRecorder()
+	handler.HandleGrouped(w, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestTaskHandler_PatchTask_StatusTransitions(t *testing.T) {
+	require.NoError(t, models.Configure(models.Limits{EnforceTransitions: true}))
+	defer func() { _ = models.Configure(models.Limits{}) }()
+
+	ctx := context.Background()
+	store := memory.New()
+	handler := NewTaskHandler(store)
+
+	task := &models.Task{JiraID: "OCPBUGS-1", Title: "Workflow", Status: models.Archived}
+	require.NoError(t, store.CreateTask(ctx, task))
+
+	patch := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/tasks/"+task.ID+query, strings.NewReader(`{"status": "in_progress"}`))
+		w := httptest.NewRecorder()
+		handler.HandleTask(w, req)
+		return w
+	}
+
+	w := patch("")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "cannot change status from archived to in_progress")
+
+	w = patch("?force=true")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	got, err := store.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.InProgress, got.Status)
+}
+
+func TestTaskHandler_BlockedNeedsBlocker(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	handler := NewTaskHandler(store)
+
+	t.Run("create", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(`{"title":"Stuck","status":"blocked"}`))
+		w := httptest.NewRecorder()
+		handler.HandleTasks(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertValidationErrorResponse(t, w, "a blocked task needs at least one blocker")
+
+		req = httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(`{"title":"Stuck","status":"blocked","blockers":["Waiting on infra"]}`))
+		w = httptest.NewRecorder()
+		handler.HandleTasks(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	})
+
+	t.Run("ensure", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks/ensure", strings.NewReader(`{"jira_id":"OCPBUGS-9","title":"Stuck","status":"blocked"}`))
+		w := httptest.NewRecorder()
+		handler.HandleEnsure(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertValidationErrorResponse(t, w, "a blocked task needs at least one blocker")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		task := &models.Task{JiraID: "OCPBUGS-1", Title: "Workflow", Status: models.InProgress}
+		require.NoError(t, store.CreateTask(ctx, task))
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/tasks/"+task.ID, strings.NewReader(`{"status": "blocked"}`))
+		w := httptest.NewRecorder()
+		handler.HandleTask(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertValidationErrorResponse(t, w, "a blocked task needs at least one blocker")
+	})
+}
+
+func TestTaskHandler_HandleTask_CommentsBulk(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	handler := NewTaskHandler(store)
+
+	task := &models.Task{JiraID: "OCPBUGS-1", Title: "Migrated task"}
+	require.NoError(t, store.CreateTask(ctx, task))
+
+	t.Run("keeps order and timestamps", func(t *testing.T) {
+		before := time.Now()
+		body := `[
+			{"content": "Opened upstream", "created_at": "2023-01-10T08:00:00Z"},
+			{"content": "  Reproduced locally  ", "created_at": "2023-01-11T15:30:00Z"},
+			{"content": "Imported without a date"}
+		]`
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID+"/comments/bulk", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.HandleTask(w, req)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp models.BulkCommentResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Comments, 3)
+		for _, c := range resp.Comments {
+			assert.NotEmpty(t, c.ID)
+			assert.Equal(t, task.ID, c.TaskID)
+		}
+
+		comments, err := store.GetTaskComments(ctx, task.ID)
+		require.NoError(t, err)
+		require.Len(t, comments, 3)
+		assert.Equal(t, "Opened upstream", comments[0].Content)
+		assert.Equal(t, time.Date(2023, 1, 10, 8, 0, 0, 0, time.UTC), comments[0].CreatedAt.UTC())
+		assert.Equal(t, "Reproduced locally", comments[1].Content)
+		assert.Equal(t, time.Date(2023, 1, 11, 15, 30, 0, 0, time.UTC), comments[1].CreatedAt.UTC())
+		assert.Equal(t, "Imported without a date", comments[2].Content)
+		assert.False(t, comments[2].CreatedAt.Before(before), "missing timestamps default to now")
+	})
+
+	t.Run("invalid comment stores nothing", func(t *testing.T) {
+		other := &models.Task{JiraID: "OCPBUGS-2", Title: "Other"}
+		require.NoError(t, store.CreateTask(ctx, other))
+
+		body := `[{"content": "fine"}, {"content": "   "}]`
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks/"+other.ID+"/comments/bulk", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.HandleTask(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "comments[1].content")
+		comments, err := store.GetTaskComments(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Empty(t, comments)
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID+"/comments/bulk", strings.NewReader(`[]`))
+		w := httptest.NewRecorder()
+
+		handler.HandleTask(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/tasks/missing/comments/bulk", strings.NewReader(`[{"content": "x"}]`))
+		w := httptest.NewRecorder()
+
+		handler.HandleTask(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/tasks/"+task.ID+"/comments/bulk", nil)
+		w := httptest.NewRecorder()
+
+		handler.HandleTask(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestTaskHandler_HandleValidate_Valid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No storage expectations: validation never persists
+	mockStorage := mocks.NewMockStorage(ctrl)
+	handler := NewTaskHandler(mockStorage)
+
+	body := `{"title":"Check before creating","tags":[" Backend ","backend"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/validate", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.HandleValidate(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Task
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.ID)
+	assert.Equal(t, "Check before creating", response.Title)
+	assert.Equal(t, models.DefaultNoJira, response.JiraID)
+	assert.Equal(t, models.DefaultPriority, response.Priority)
+	assert.Equal(t, models.DefaultStatus, response.Status)
+	assert.Equal(t, []string{"backend"}, response.Tags)
+}
+
+func TestTaskHandler_HandleValidate_Invalid(t *testing.T) {
+	// Validation failures name a field; other errors carry a top-level code
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		field   string
+		code    string
+		message string
+	}{
+		{
+			name:    "missing title",
+			body:    `{"priority":"high"}`,
+			status:  http.StatusBadRequest,
+			field:   "title",
+			code:    models.CodeRequired,
+			message: "title is required",
+		},
+		{
+			name:    "invalid priority",
+			body:    `{"title":"Task","priority":"urgent"}`,
+			status:  http.StatusBadRequest,
+			field:   "priority",
+			code:    models.CodeInvalid,
+			message: "invalid priority",
+		},
+		{
+			name:    "blocked without blocker",
+			body:    `{"title":"Task","status":"blocked"}`,
+			status:  http.StatusBadRequest,
+			field:   "blockers",
+			code:    models.CodeRequired,
+			message: "a blocked task needs at least one blocker",
+		},
+		{
+			name:    "invalid JSON",
+			body:    `{"title":`,
+			status:  http.StatusBadRequest,
+			code:    errCodeBadRequest,
+			message: "Invalid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := NewTaskHandler(mocks.NewMockStorage(ctrl))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tasks/validate", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.HandleValidate(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.field == "" {
+				assertErrorResponse(t, w, tt.code, tt.message)
+				return
+			}
+			fieldErr := assertValidationErrorResponse(t, w, tt.message)
+			assert.Equal(t, models.FieldError{Field: tt.field, Message: tt.message, Code: tt.code}, fieldErr)
+		})
+	}
+}
+
+func TestTaskHandler_CustomFields(t *testing.T) {
+	store := memory.New()
+	handler := NewTaskHandler(store)
+
+	create := func(body string) *models.Task {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.HandleTasks(w, httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body)))
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var task models.Task
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &task))
+		return &task
+	}
+	list := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.HandleTasks(w, httptest.NewRequest(http.MethodGet, "/api/tasks"+query, nil))
+		return w
+	}
+	patch := func(id, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.HandleTask(w, httptest.NewRequest(http.MethodPatch, "/api/tasks/"+id, strings.NewReader(body)))
+		return w
+	}
+
+	sprint12 := create(`{"title": "Sprint 12", "custom_fields": {"sprint": "12", "epic": "etcd"}}`)
+	assert.Equal(t, map[string]string{"sprint": "12", "epic": "etcd"}, sprint12.CustomFields)
+	create(`{"title": "Sprint 13", "custom_fields": {"sprint": "13"}}`)
+
+	w := list("?custom.sprint=12")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response models.TaskListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Tasks, 1)
+	assert.Equal(t, sprint12.ID, response.Tasks[0].ID)
+
+	w = list("?custom.sprint%20number=12")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// PATCH sets and removes single fields, leaving the others alone
+	w = patch(sprint12.ID, `{"custom_fields": {"story_points": "5", "epic": null}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	got, err := store.GetTask(context.Background(), sprint12.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"sprint": "12", "story_points": "5"}, got.CustomFields)
+
+	w = patch(sprint12.ID, `{"custom_fields": {"story_points": 5}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "custom_fields")
+
+	w = patch(sprint12.ID, `{"custom_fields": {"bad key": "1"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), models.CodeInvalidFormat)
+}
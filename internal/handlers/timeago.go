@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeAgo is the "timeago" template function
+func timeAgo(t time.Time) string {
+	return formatTimeAgo(t, time.Now())
+}
+
+// formatTimeAgo describes t relative to now ("5 minutes ago", "3 weeks ago").
+// Anything older than a year, or a zero time, is shown as a date instead.
+func formatTimeAgo(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	d := now.Sub(t)
+	switch {
+	case d < 10*time.Second:
+		// Includes small clock skew that puts t slightly in the future
+		return "just now"
+	case d < time.Minute:
+		return pluralAgo(int(d/time.Second), "second")
+	case d < time.Hour:
+		return pluralAgo(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return pluralAgo(int(d/time.Hour), "hour")
+	case d < 7*24*time.Hour:
+		return pluralAgo(int(d/(24*time.Hour)), "day")
+	case d < 30*24*time.Hour:
+		return pluralAgo(int(d/(7*24*time.Hour)), "week")
+	case d < 365*24*time.Hour:
+		return pluralAgo(int(d/(30*24*time.Hour)), "month")
+	default:
+		return t.Format("Jan 2, 2006")
+	}
+}
+
+func pluralAgo(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s ago", unit)
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTimeAgo(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		ago  time.Duration
+		want string
+	}{
+		{"future", -5 * time.Second, "just now"},
+		{"now", 0, "just now"},
+		{"under ten seconds", 9 * time.Second, "just now"},
+		{"ten seconds", 10 * time.Second, "10 seconds ago"},
+		{"under a minute", 59 * time.Second, "59 seconds ago"},
+		{"one minute", time.Minute, "1 minute ago"},
+		{"under an hour", 59*time.Minute + 59*time.Second, "59 minutes ago"},
+		{"one hour", time.Hour, "1 hour ago"},
+		{"under a day", 23*time.Hour + 59*time.Minute, "23 hours ago"},
+		{"one day", 24 * time.Hour, "1 day ago"},
+		{"under a week", 6*24*time.Hour + 23*time.Hour, "6 days ago"},
+		{"one week", 7 * 24 * time.Hour, "1 week ago"},
+		{"under a month", 29 * 24 * time.Hour, "4 weeks ago"},
+		{"one month", 30 * 24 * time.Hour, "1 month ago"},
+		{"under a year", 364 * 24 * time.Hour, "12 months ago"},
+		{"one year", 365 * 24 * time.Hour, "Jun 16, 2023"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatTimeAgo(now.Add(-tt.ago), now))
+		})
+	}
+
+	assert.Empty(t, formatTimeAgo(time.Time{}, now), "zero time")
+}
@@ -1,15 +1,17 @@
 package handlers
 
 import (
+	_ "embed"
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"michishirube/internal/logger"
 	"michishirube/internal/models"
@@ -24,6 +26,8 @@ type WebHandler struct {
 type PageData struct {
 	PageTitle string
 	CustomJS  string
+	CustomCSS string // Extra stylesheet under /static/css/, loaded after main.css
+	Theme     string // themeLight or themeDark, from the theme cookie
 
 	// Dashboard data
 	Tasks           []*TaskWithRelations
@@ -38,6 +42,9 @@ type PageData struct {
 	TotalCount   int
 	HasMore      bool
 
+	// Board data
+	Columns []*BoardColumn
+
 	// Task detail data
 	Task     *models.Task
 	Links    []*models.Link
@@ -47,8 +54,12 @@ type PageData struct {
 	JiraID   string
 	Title    string
 	Priority string
+	Status   string
 	Tags     []string
 	Notes    string
+
+	// FormError is shown next to the form that failed validation
+	FormError string
 }
 
 type TaskWithRelations struct {
@@ -57,12 +68,62 @@ type TaskWithRelations struct {
 	Comments []*models.Comment
 }
 
-func NewWebHandler(storage storage.Storage) *WebHandler {
-	tmpl := template.New("").Funcs(template.FuncMap{
-		"join": strings.Join,
-		"add":  func(a, b int) int { return a + b },
-		"eq":   func(a, b interface{}) bool { return a == b },
-		"ne":   func(a, b interface{}) bool { return a != b },
+// BoardColumn is one status lane of the Kanban board
+type BoardColumn struct {
+	Status models.Status
+	Label  string
+	Tasks  []*models.Task
+}
+
+// Count returns the number of tasks in the column
+func (c *BoardColumn) Count() int {
+	return len(c.Tasks)
+}
+
+// boardLanes lists the board columns in workflow order
+var boardLanes = []struct {
+	status models.Status
+	label  string
+}{
+	{models.New, "📝 New"},
+	{models.InProgress, "🔄 In Progress"},
+	{models.Blocked, "🚫 Blocked"},
+	{models.Done, "✅ Done"},
+}
+
+// templatesDir holds base.html and the page templates rendered into it
+const templatesDir = "web/templates"
+
+// NewWebHandler parses the templates and checks that every page renders, so a
+// broken template stops the server at startup instead of failing a request
+func NewWebHandler(storage storage.Storage) (*WebHandler, error) {
+	templates, err := template.New("").Funcs(templateFuncs()).ParseGlob(filepath.Join(templatesDir, "*.html"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse templates: %w", err)
+	}
+	if err := checkPageTemplates(); err != nil {
+		return nil, err
+	}
+
+	return &WebHandler{
+		storage:   storage,
+		templates: templates,
+	}, nil
+}
+
+// templateFuncs returns the functions available to every template
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"join":          strings.Join,
+		"isNoJira":      models.IsNoJira,
+		"noJiraID":      models.NoJiraID,
+		"maxTitleLen":   models.MaxTitleLen,
+		"maxCommentLen": models.MaxCommentLen,
+		"timeago":       timeAgo,
+		"tagColor":      models.TagColor,
+		"add":           func(a, b int) int { return a + b },
+		"eq":            func(a, b interface{}) bool { return a == b },
+		"ne":            func(a, b interface{}) bool { return a != b },
 		"len": func(slice interface{}) int {
 			switch s := slice.(type) {
 			case []*models.Link:
@@ -90,21 +151,53 @@ func NewWebHandler(storage storage.Storage) *WebHandler {
 			}
 			return nil
 		},
-	})
+	}
+}
+
+// parsePageTemplate parses base.html together with one page template
+func parsePageTemplate(name string) (*template.Template, error) {
+	return template.New("").Funcs(templateFuncs()).ParseFiles(
+		filepath.Join(templatesDir, "base.html"),
+		filepath.Join(templatesDir, name),
+	)
+}
 
-	templates, err := tmpl.ParseGlob("web/templates/*.html")
+// checkPageTemplates renders every page into base.html with placeholder data.
+// Pages must define the content block that base.html renders.
+func checkPageTemplates() error {
+	pages, err := filepath.Glob(filepath.Join(templatesDir, "*.html"))
 	if err != nil {
-		panic("Failed to parse templates: " + err.Error())
+		return fmt.Errorf("failed to list templates: %w", err)
 	}
 
-	return &WebHandler{
-		storage:   storage,
-		templates: templates,
+	placeholder := &PageData{Task: &models.Task{}, Theme: themeLight}
+	for _, page := range pages {
+		name := filepath.Base(page)
+		if name == "base.html" {
+			continue
+		}
+		tmpl, err := parsePageTemplate(name)
+		if err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+		if tmpl.Lookup("content") == nil {
+			return fmt.Errorf("template %s does not define a content block", name)
+		}
+		if err := tmpl.ExecuteTemplate(io.Discard, "base.html", placeholder); err != nil {
+			return fmt.Errorf("failed to render template %s: %w", name, err)
+		}
 	}
+	return nil
 }
 
 // Dashboard - Main page
 func (h *WebHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	// "/" is the mux's catch-all, so anything unmatched lands here
+	if r.URL.Path != "/" {
+		h.NotFound(w, r)
+		return
+	}
+
 	log := logger.FromContext(r.Context())
 	log.Debug("Dashboard endpoint called",
 		"search", r.URL.Query().Get("search"),
@@ -140,9 +233,9 @@ func (h *WebHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 
 	// Search or list tasks
 	if searchQuery != "" {
-		tasks, err = h.storage.SearchTasks(searchQuery, includeArchived, limit+1)
+		tasks, err = h.storage.SearchTasks(r.Context(), searchQuery, includeArchived, true, limit+1)
 	} else {
-		tasks, err = h.storage.ListTasks(filters)
+		tasks, err = h.storage.ListTasks(r.Context(), filters)
 	}
 
 	if err != nil {
@@ -163,8 +256,8 @@ func (h *WebHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		links, _ := h.storage.GetTaskLinks(task.ID)
-		comments, _ := h.storage.GetTaskComments(task.ID)
+		links, _ := h.storage.GetTaskLinks(r.Context(), task.ID)
+		comments, _ := h.storage.GetTaskComments(r.Context(), task.ID)
 
 		// Ensure we have empty slices instead of nil
 		if links == nil {
@@ -182,7 +275,7 @@ func (h *WebHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get total count (for display)
-	allTasks, _ := h.storage.ListTasks(storage.TaskFilters{IncludeArchived: includeArchived})
+	allTasks, _ := h.storage.ListTasks(r.Context(), storage.TaskFilters{IncludeArchived: includeArchived})
 	totalCount := len(allTasks)
 
 	data := &PageData{
@@ -198,7 +291,47 @@ func (h *WebHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 		HasMore:         hasMore,
 	}
 
-	h.renderTemplate(w, "dashboard.html", data)
+	h.renderTemplate(w, r, "dashboard.html", data)
+}
+
+// Board - Kanban view of non-archived tasks grouped by status
+func (h *WebHandler) Board(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	log.Debug("Board endpoint called")
+
+	tasks, err := h.storage.ListTasks(r.Context(), storage.TaskFilters{})
+	if err != nil {
+		http.Error(w, "Failed to load tasks: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := &PageData{
+		PageTitle: "Board",
+		Columns:   groupTasksByStatus(tasks),
+		TaskCount: len(tasks),
+	}
+
+	h.renderTemplate(w, r, "board.html", data)
+}
+
+// groupTasksByStatus splits tasks into board columns, preserving their order
+// within each column. Tasks whose status has no column are left out.
+func groupTasksByStatus(tasks []*models.Task) []*BoardColumn {
+	columns := make([]*BoardColumn, 0, len(boardLanes))
+	byStatus := make(map[models.Status]*BoardColumn, len(boardLanes))
+	for _, lane := range boardLanes {
+		column := &BoardColumn{Status: lane.status, Label: lane.label, Tasks: []*models.Task{}}
+		columns = append(columns, column)
+		byStatus[lane.status] = column
+	}
+
+	for _, task := range tasks {
+		if column, ok := byStatus[task.Status]; ok {
+			column.Tasks = append(column.Tasks, task)
+		}
+	}
+
+	return columns
 }
 
 // TaskDetail - Show individual task
@@ -207,7 +340,19 @@ func (h *WebHandler) TaskDetail(w http.ResponseWriter, r *http.Request) {
 
 	// Extract task ID from URL path
 	path := strings.TrimPrefix(r.URL.Path, "/task/")
-	taskID := strings.Split(path, "/")[0]
+	parts := strings.Split(path, "/")
+	taskID := parts[0]
+
+	if len(parts) > 1 {
+		switch parts[1] {
+		case "edit":
+			h.EditTask(w, r)
+			return
+		case "comment":
+			h.PostComment(w, r)
+			return
+		}
+	}
 
 	log.Debug("TaskDetail endpoint called", "task_id", taskID)
 
@@ -216,25 +361,19 @@ func (h *WebHandler) TaskDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get task
-	task, err := h.storage.GetTask(taskID)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			http.Error(w, "Task not found", http.StatusNotFound)
-		} else {
-			http.Error(w, "Failed to load task: "+err.Error(), http.StatusInternalServerError)
-		}
-		return
-	}
-
+	task := h.loadTaskForWeb(w, r, taskID)
 	if task == nil {
-		http.Error(w, "Task not found", http.StatusNotFound)
 		return
 	}
 
+	h.renderTaskDetail(w, r, task, "", http.StatusOK)
+}
+
+// renderTaskDetail renders task.html with the task's links and comments
+func (h *WebHandler) renderTaskDetail(w http.ResponseWriter, r *http.Request, task *models.Task, formError string, status int) {
 	// Get related data
-	links, _ := h.storage.GetTaskLinks(taskID)
-	comments, _ := h.storage.GetTaskComments(taskID)
+	links, _ := h.storage.GetTaskLinks(r.Context(), task.ID)
+	comments, _ := h.storage.GetTaskComments(r.Context(), task.ID)
 
 	// Ensure we have empty slices instead of nil
 	if links == nil {
@@ -250,9 +389,58 @@ func (h *WebHandler) TaskDetail(w http.ResponseWriter, r *http.Request) {
 		Task:      task,
 		Links:     links,
 		Comments:  comments,
+		FormError: formError,
+	}
+
+	h.renderTemplateWithStatus(w, r, "task.html", data, status)
+}
+
+// PostComment - Add a comment from the task detail page (POST /task/{id}/comment)
+func (h *WebHandler) PostComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	log := logger.FromContext(r.Context())
+	taskID := strings.Split(strings.TrimPrefix(r.URL.Path, "/task/"), "/")[0]
+
+	if err := r.ParseForm(); err != nil {
+		log.Error("Failed to parse form data", "error", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	task := h.loadTaskForWeb(w, r, taskID)
+	if task == nil {
+		return
+	}
+
+	content := strings.TrimSpace(r.FormValue("content"))
+	if content == "" {
+		h.renderTaskDetail(w, r, task, "Comment cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	comment := &models.Comment{
+		TaskID:  task.ID,
+		Content: content,
+	}
+	if err := h.storage.CreateComment(r.Context(), comment); err != nil {
+		log.Error("Failed to create comment", "error", err, "task_id", task.ID)
+		var validationErr *models.ValidationError
+		if errors.As(err, &validationErr) {
+			h.renderTaskDetail(w, r, task, validationErr.Message, http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to create comment: "+err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	h.renderTemplate(w, "task.html", data)
+	log.Info("Comment added from task page", "comment_id", comment.ID, "task_id", task.ID)
+
+	// Post/Redirect/Get back to the comments section
+	http.Redirect(w, r, "/task/"+task.ID+"#comments", http.StatusSeeOther)
 }
 
 // NewTask - Show new task form
@@ -274,10 +462,10 @@ func (h *WebHandler) showNewTaskForm(w http.ResponseWriter, r *http.Request) {
 	data := &PageData{
 		PageTitle: "New Task",
 		CustomJS:  "new_task.js",
-		Priority:  "normal", // Default priority
+		Priority:  string(models.NewTaskPriority()),
 	}
 
-	h.renderTemplate(w, "new_task.html", data)
+	h.renderTemplate(w, r, "new_task.html", data)
 }
 
 func (h *WebHandler) createNewTask(w http.ResponseWriter, r *http.Request) {
@@ -307,18 +495,7 @@ func (h *WebHandler) createNewTask(w http.ResponseWriter, r *http.Request) {
 
 	// Set default Jira ID if empty
 	if jiraID == "" {
-		jiraID = models.DefaultNoJira
-	}
-
-	// Parse tags
-	var tags []string
-	if tagsStr != "" {
-		for _, tag := range strings.Split(tagsStr, ",") {
-			tag = strings.TrimSpace(tag)
-			if tag != "" {
-				tags = append(tags, tag)
-			}
-		}
+		jiraID = models.NoJiraID()
 	}
 
 	// Create task
@@ -326,38 +503,13 @@ func (h *WebHandler) createNewTask(w http.ResponseWriter, r *http.Request) {
 		JiraID:   jiraID,
 		Title:    title,
 		Priority: models.Priority(priority),
-		Status:   models.New,
-		Tags:     tags,
+		Status:   models.NewTaskStatus(),
+		Tags:     models.ParseTags(tagsStr),
 		Blockers: []string{}, // Empty initially
 	}
 
-	err = h.storage.CreateTask(task)
-	if err != nil {
-		log.Error("Failed to create task", "error", err, "title", task.Title)
-		// If validation error, show form with error
-		var validationErr *models.ValidationError
-		if errors.As(err, &validationErr) {
-			h.showNewTaskFormWithError(w, task)
-			return
-		}
-		http.Error(w, "Failed to create task: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	log.Info("Task created successfully", "task_id", task.ID, "title", task.Title, "priority", task.Priority)
-
-	// Create initial comment if notes provided
-	if notes != "" {
-		comment := &models.Comment{
-			TaskID:  task.ID,
-			Content: notes,
-		}
-		if err := h.storage.CreateComment(comment); err != nil {
-			log.Warn("Failed to create initial comment", "error", err)
-		}
-	}
-
-	// Process initial links if provided
+	// Collect initial links if provided
+	var links []*models.Link
 	linkTypes := r.Form["link_types[]"]
 	linkURLs := r.Form["link_urls[]"]
 	linkTitles := r.Form["link_titles[]"]
@@ -372,24 +524,57 @@ func (h *WebHandler) createNewTask(w http.ResponseWriter, r *http.Request) {
 				title = linkURLs[i] // Use URL as title if not provided
 			}
 
-			link := &models.Link{
-				TaskID: task.ID,
+			links = append(links, &models.Link{
 				Type:   models.LinkType(linkType),
 				URL:    linkURLs[i],
 				Title:  title,
 				Status: "active",
+			})
+		}
+	}
+
+	// The task, its initial comment and links are stored together, so a bad
+	// link can't leave a half-created task behind
+	err = h.storage.WithTransaction(r.Context(), func(tx storage.Storage) error {
+		if err := tx.CreateTask(r.Context(), task); err != nil {
+			return err
+		}
+		if notes != "" {
+			comment := &models.Comment{
+				TaskID:  task.ID,
+				Content: notes,
 			}
-			if err := h.storage.CreateLink(link); err != nil {
-				log.Warn("Failed to create initial link", "error", err, "url", linkURLs[i])
+			if err := tx.CreateComment(r.Context(), comment); err != nil {
+				return err
+			}
+		}
+		for _, link := range links {
+			link.TaskID = task.ID
+			if err := tx.CreateLink(r.Context(), link); err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to create task", "error", err, "title", task.Title)
+		// If validation error, show form with error
+		var validationErr *models.ValidationError
+		if errors.As(err, &validationErr) {
+			h.showNewTaskFormWithError(w, r, task)
+			return
+		}
+		http.Error(w, "Failed to create task: "+err.Error(), http.StatusInternalServerError)
+		return
 	}
 
+	log.Info("Task created successfully", "task_id", task.ID, "title", task.Title, "priority", task.Priority, "links", len(links))
+
 	// Redirect to the new task
 	http.Redirect(w, r, "/task/"+task.ID, http.StatusSeeOther)
 }
 
-func (h *WebHandler) showNewTaskFormWithError(w http.ResponseWriter, task *models.Task) {
+func (h *WebHandler) showNewTaskFormWithError(w http.ResponseWriter, r *http.Request, task *models.Task) {
 	data := &PageData{
 		PageTitle: "New Task",
 		CustomJS:  "new_task.js",
@@ -401,55 +586,162 @@ func (h *WebHandler) showNewTaskFormWithError(w http.ResponseWriter, task *model
 
 	// Set error status but show the form
 	w.WriteHeader(http.StatusBadRequest)
-	h.renderTemplate(w, "new_task.html", data)
+	h.renderTemplate(w, r, "new_task.html", data)
+}
+
+// EditTask - Show and submit the edit form for /task/{id}/edit
+func (h *WebHandler) EditTask(w http.ResponseWriter, r *http.Request) {
+	taskID := strings.Split(strings.TrimPrefix(r.URL.Path, "/task/"), "/")[0]
+	if taskID == "" {
+		http.Error(w, "Task ID required", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.showEditTaskForm(w, r, taskID)
+	case http.MethodPost:
+		h.updateExistingTask(w, r, taskID)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// loadTaskForWeb fetches a task, writing a 404/500 page and returning nil when it can't
+func (h *WebHandler) loadTaskForWeb(w http.ResponseWriter, r *http.Request, taskID string) *models.Task {
+	task, err := h.storage.GetTask(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			http.Error(w, "Task not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to load task: "+err.Error(), http.StatusInternalServerError)
+		}
+		return nil
+	}
+	if task == nil {
+		http.Error(w, "Task not found", http.StatusNotFound)
+	}
+	return task
+}
+
+func (h *WebHandler) showEditTaskForm(w http.ResponseWriter, r *http.Request, taskID string) {
+	log := logger.FromContext(r.Context())
+	log.Debug("Edit task form requested", "task_id", taskID)
+
+	task := h.loadTaskForWeb(w, r, taskID)
+	if task == nil {
+		return
+	}
+
+	h.renderTemplate(w, r, "edit_task.html", editTaskPageData(task))
+}
+
+func (h *WebHandler) updateExistingTask(w http.ResponseWriter, r *http.Request, taskID string) {
+	log := logger.FromContext(r.Context())
+	log.Debug("Updating task from form", "task_id", taskID)
+
+	if err := r.ParseForm(); err != nil {
+		log.Error("Failed to parse form data", "error", err)
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	title := strings.TrimSpace(r.FormValue("title"))
+	if title == "" {
+		http.Error(w, "Title is required", http.StatusBadRequest)
+		return
+	}
+
+	task := h.loadTaskForWeb(w, r, taskID)
+	if task == nil {
+		return
+	}
+
+	jiraID := strings.TrimSpace(r.FormValue("jira_id"))
+	if jiraID == "" {
+		jiraID = models.NoJiraID()
+	}
+
+	task.JiraID = jiraID
+	task.Title = title
+	task.Priority = models.Priority(r.FormValue("priority"))
+	task.Status = models.Status(r.FormValue("status"))
+	task.Tags = models.ParseTags(r.FormValue("tags"))
+	task.Blockers = parseBlockers(r.FormValue("blockers"))
+
+	if err := h.storage.UpdateTask(r.Context(), task); err != nil {
+		log.Error("Failed to update task", "error", err, "task_id", taskID)
+		var validationErr *models.ValidationError
+		if errors.As(err, &validationErr) {
+			h.showEditTaskFormWithError(w, r, task, validationErr.Message)
+			return
+		}
+		http.Error(w, "Failed to update task: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Info("Task updated from form", "task_id", task.ID, "status", task.Status, "priority", task.Priority)
+
+	http.Redirect(w, r, "/task/"+task.ID, http.StatusSeeOther)
+}
+
+// parseBlockers splits the edit form's blockers, one per line, dropping blank lines
+func parseBlockers(s string) []string {
+	blockers := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		if blocker := strings.TrimSpace(line); blocker != "" {
+			blockers = append(blockers, blocker)
+		}
+	}
+	return blockers
+}
+
+// showEditTaskFormWithError re-renders the form with the submitted values and
+// says why they were rejected
+func (h *WebHandler) showEditTaskFormWithError(w http.ResponseWriter, r *http.Request, task *models.Task, message string) {
+	data := editTaskPageData(task)
+	data.FormError = message
+	h.renderTemplateWithStatus(w, r, "edit_task.html", data, http.StatusBadRequest)
+}
+
+func editTaskPageData(task *models.Task) *PageData {
+	// NO-JIRA is stored as a placeholder; show it as an empty field
+	jiraID := task.JiraID
+	if models.IsNoJira(jiraID) {
+		jiraID = ""
+	}
+
+	return &PageData{
+		PageTitle: "Edit " + task.Title,
+		Task:      task,
+		JiraID:    jiraID,
+		Title:     task.Title,
+		Priority:  string(task.Priority),
+		Status:    string(task.Status),
+		Tags:      task.Tags,
+	}
 }
 
 // Helper method to render templates
-func (h *WebHandler) renderTemplate(w http.ResponseWriter, templateName string, data *PageData) {
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+func (h *WebHandler) renderTemplate(w http.ResponseWriter, r *http.Request, templateName string, data *PageData) {
+	h.renderTemplateWithStatus(w, r, templateName, data, http.StatusOK)
+}
 
-	// Parse the specific templates for this page
-	tmpl := template.New("").Funcs(template.FuncMap{
-		"join": strings.Join,
-		"add":  func(a, b int) int { return a + b },
-		"eq":   func(a, b interface{}) bool { return a == b },
-		"ne":   func(a, b interface{}) bool { return a != b },
-		"len": func(slice interface{}) int {
-			switch s := slice.(type) {
-			case []*models.Link:
-				return len(s)
-			case []*models.Comment:
-				return len(s)
-			case []string:
-				return len(s)
-			default:
-				return 0
-			}
-		},
-		"string": func(v interface{}) string {
-			return fmt.Sprintf("%v", v)
-		},
-		"slice": func() []*models.Link {
-			return make([]*models.Link, 0)
-		},
-		"append": func(slice []*models.Link, item *models.Link) []*models.Link {
-			return append(slice, item)
-		},
-		"index": func(slice []*models.Link, index int) *models.Link {
-			if index >= 0 && index < len(slice) {
-				return slice[index]
-			}
-			return nil
-		},
-	})
+// renderTemplateWithStatus renders a page with a status other than 200 OK
+func (h *WebHandler) renderTemplateWithStatus(w http.ResponseWriter, r *http.Request, templateName string, data *PageData, status int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	data.Theme = themeFromRequest(r)
 
 	// Parse base template and the specific page template
-	pageTemplate, err := tmpl.ParseFiles("web/templates/base.html", "web/templates/"+templateName)
+	pageTemplate, err := parsePageTemplate(templateName)
 	if err != nil {
 		http.Error(w, "Template parse error: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
 	err = pageTemplate.ExecuteTemplate(w, "base.html", data)
 	if err != nil {
 		http.Error(w, "Template error: "+err.Error(), http.StatusInternalServerError)
@@ -457,20 +749,97 @@ func (h *WebHandler) renderTemplate(w http.ResponseWriter, templateName string,
 	}
 }
 
+const (
+	themeCookieName = "theme"
+	themeLight      = "light"
+	themeDark       = "dark"
+)
+
+// themeFromRequest reads the theme cookie, defaulting to the light theme
+func themeFromRequest(r *http.Request) string {
+	if cookie, err := r.Cookie(themeCookieName); err == nil && cookie.Value == themeDark {
+		return themeDark
+	}
+	return themeLight
+}
+
+// ToggleTheme - Flip the theme cookie between light and dark and go back to the referring page
+func (h *WebHandler) ToggleTheme(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodPost:
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	theme := themeDark
+	if themeFromRequest(r) == themeDark {
+		theme = themeLight
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     themeCookieName,
+		Value:    theme,
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	logger.FromContext(r.Context()).Debug("Theme toggled", "theme", theme)
+	http.Redirect(w, r, refererPath(r), http.StatusSeeOther)
+}
+
+// refererPath returns the local path of the Referer header, or "/". Only the
+// path and query are kept so the redirect can never leave the site.
+func refererPath(r *http.Request) string {
+	referer, err := url.Parse(r.Referer())
+	if err != nil || referer.Path == "" || !strings.HasPrefix(referer.Path, "/") {
+		return "/"
+	}
+	return referer.RequestURI()
+}
+
 // StaticFileHandler - Serve static files
 func (h *WebHandler) StaticFileHandler() http.Handler {
 	fileServer := http.FileServer(http.Dir("web/static/"))
 	return http.StripPrefix("/static/", fileServer)
 }
 
-// HealthCheck - Simple health check endpoint
-func (h *WebHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write([]byte(`{"status": "healthy", "timestamp": "` + time.Now().Format(time.RFC3339) + `"}`)); err != nil {
+// favicon is embedded so the icon is served even without web/static
+//
+//go:embed assets/favicon.svg
+var favicon []byte
+
+// Favicon - Serve the site icon browsers request from /favicon.ico
+func (h *WebHandler) Favicon(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := w.Write(favicon); err != nil {
 		log := logger.FromContext(r.Context())
-		log.Error("Failed to write health check response", "error", err)
+		log.Error("Failed to write favicon", "error", err)
+	}
+}
+
+// NotFound - Render the 404 page for unknown paths. Unknown /api/ paths get a
+// JSON error like the rest of the API.
+func (h *WebHandler) NotFound(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		writeError(w, http.StatusNotFound, errCodeNotFound, "Not found")
+		return
+	}
+
+	data := &PageData{
+		PageTitle: "Page Not Found",
 	}
+	h.renderTemplateWithStatus(w, r, "404.html", data, http.StatusNotFound)
 }
 
 // OpenAPISpec - Serve the OpenAPI specification
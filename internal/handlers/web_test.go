@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -10,9 +9,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"michishirube/internal/models"
 	"michishirube/internal/storage"
+	"michishirube/internal/storage/memory"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,6 +24,7 @@ type MockWebStorage struct {
 	tasks    map[string]*models.Task
 	links    map[string][]*models.Link
 	comments map[string][]*models.Comment
+	pingErr  error
 }
 
 func NewMockWebStorage() *MockWebStorage {
@@ -33,7 +35,7 @@ func NewMockWebStorage() *MockWebStorage {
 	}
 }
 
-func (m *MockWebStorage) CreateTask(task *models.Task) error {
+func (m *MockWebStorage) CreateTask(_ context.Context, task *models.Task) error {
 	if task.Title == "" {
 		return &models.ValidationError{Message: "Title is required"}
 	}
@@ -42,15 +44,89 @@ func (m *MockWebStorage) CreateTask(task *models.Task) error {
 	return nil
 }
 
-func (m *MockWebStorage) GetTask(id string) (*models.Task, error) {
+func (m *MockWebStorage) CreateTaskWithLinks(ctx context.Context, task *models.Task, links []*models.Link) error {
+	if err := m.CreateTask(ctx, task); err != nil {
+		return err
+	}
+	for _, link := range links {
+		link.TaskID = task.ID
+		m.links[task.ID] = append(m.links[task.ID], link)
+	}
+	return nil
+}
+
+func (m *MockWebStorage) PurgeArchived(_ context.Context, olderThan time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *MockWebStorage) GetTaskCommentsPaged(_ context.Context, taskID string, limit, offset int) ([]*models.Comment, int, error) {
+	comments := m.comments[taskID]
+	total := len(comments)
+	if offset > total {
+		offset = total
+	}
+	comments = comments[offset:]
+	if limit > 0 && limit < len(comments) {
+		comments = comments[:limit]
+	}
+	return comments, total, nil
+}
+
+func (m *MockWebStorage) GetTask(_ context.Context, id string) (*models.Task, error) {
 	task, exists := m.tasks[id]
 	if !exists {
-		return nil, errors.New("task not found")
+		return nil, fmt.Errorf("task %w", storage.ErrNotFound)
 	}
 	return task, nil
 }
 
-func (m *MockWebStorage) ListTasks(filters storage.TaskFilters) ([]*models.Task, error) {
+func (m *MockWebStorage) GetTasks(_ context.Context, ids []string) (map[string]*models.Task, error) {
+	tasks := make(map[string]*models.Task, len(ids))
+	for _, id := range ids {
+		if task, exists := m.tasks[id]; exists {
+			tasks[id] = task
+		}
+	}
+	return tasks, nil
+}
+
+func (m *MockWebStorage) GetTaskByJiraID(_ context.Context, jiraID string) (*models.Task, error) {
+	for _, task := range m.tasks {
+		if task.JiraID == jiraID && task.Status != models.Archived {
+			return task, nil
+		}
+	}
+	return nil, fmt.Errorf("task %w", storage.ErrNotFound)
+}
+
+func (m *MockWebStorage) CountTasks(_ context.Context, filters storage.TaskFilters) (int, error) {
+	count := 0
+	for _, task := range m.tasks {
+		if filters.IncludeArchived || task.Status != models.Archived {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockWebStorage) DistinctValues(_ context.Context, field, prefix string, limit int) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockWebStorage) StreamTasks(ctx context.Context, filters storage.TaskFilters, fn func(*models.Task) error) error {
+	tasks, err := m.ListTasks(ctx, filters)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockWebStorage) ListTasks(_ context.Context, filters storage.TaskFilters) ([]*models.Task, error) {
 	var tasks []*models.Task
 	for _, task := range m.tasks {
 		if filters.IncludeArchived || task.Status != models.Archived {
@@ -64,15 +140,15 @@ func (m *MockWebStorage) ListTasks(filters storage.TaskFilters) ([]*models.Task,
 		return []*models.Task{}, nil
 	}
 
-	end := start + filters.Limit
-	if end > len(tasks) {
-		end = len(tasks)
+	end := len(tasks)
+	if filters.Limit > 0 && start+filters.Limit < end {
+		end = start + filters.Limit
 	}
 
 	return tasks[start:end], nil
 }
 
-func (m *MockWebStorage) SearchTasks(query string, includeArchived bool, limit int) ([]*models.Task, error) {
+func (m *MockWebStorage) SearchTasks(_ context.Context, query string, includeArchived, includeComments bool, limit int) ([]*models.Task, error) {
 	var results []*models.Task
 	for _, task := range m.tasks {
 		if !includeArchived && task.Status == models.Archived {
@@ -91,7 +167,7 @@ func (m *MockWebStorage) SearchTasks(query string, includeArchived bool, limit i
 	return results, nil
 }
 
-func (m *MockWebStorage) GetTaskLinks(taskID string) ([]*models.Link, error) {
+func (m *MockWebStorage) GetTaskLinks(_ context.Context, taskID string) ([]*models.Link, error) {
 	links, exists := m.links[taskID]
 	if !exists {
 		return []*models.Link{}, nil
@@ -99,7 +175,24 @@ func (m *MockWebStorage) GetTaskLinks(taskID string) ([]*models.Link, error) {
 	return links, nil
 }
 
-func (m *MockWebStorage) GetTaskComments(taskID string) ([]*models.Comment, error) {
+func (m *MockWebStorage) GetTaskLinksByType(ctx context.Context, taskID string, types ...models.LinkType) ([]*models.Link, error) {
+	links, _ := m.GetTaskLinks(ctx, taskID)
+	if len(types) == 0 {
+		return links, nil
+	}
+	filtered := []*models.Link{}
+	for _, link := range links {
+		for _, linkType := range types {
+			if link.Type == linkType {
+				filtered = append(filtered, link)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+func (m *MockWebStorage) GetTaskComments(_ context.Context, taskID string) ([]*models.Comment, error) {
 	comments, exists := m.comments[taskID]
 	if !exists {
 		return []*models.Comment{}, nil
@@ -107,7 +200,7 @@ func (m *MockWebStorage) GetTaskComments(taskID string) ([]*models.Comment, erro
 	return comments, nil
 }
 
-func (m *MockWebStorage) GetComment(id string) (*models.Comment, error) {
+func (m *MockWebStorage) GetComment(_ context.Context, id string) (*models.Comment, error) {
 	return nil, nil
 }
 
@@ -116,27 +209,102 @@ func (m *MockWebStorage) RunMigrations() error {
 }
 
 // Implement other required methods with minimal functionality
-func (m *MockWebStorage) UpdateTask(task *models.Task) error { return nil }
-func (m *MockWebStorage) DeleteTask(id string) error         { return nil }
-func (m *MockWebStorage) CreateLink(link *models.Link) error {
+func (m *MockWebStorage) UpdateTask(_ context.Context, task *models.Task) error {
+	return task.Validate()
+}
+func (m *MockWebStorage) DeleteTask(_ context.Context, id string) error { return nil }
+func (m *MockWebStorage) GetRelatedTasks(_ context.Context, taskID string, limit int) ([]*models.Task, error) {
+	return nil, nil
+}
+func (m *MockWebStorage) MergeTasks(_ context.Context, sourceID, targetID string, opts storage.MergeOptions) (*models.Task, error) {
+	return nil, nil
+}
+func (m *MockWebStorage) CountTaskRelations(_ context.Context, taskIDs []string) (map[string]storage.TaskRelationCounts, error) {
+	counts := make(map[string]storage.TaskRelationCounts)
+	for _, id := range taskIDs {
+		if _, ok := m.tasks[id]; ok {
+			counts[id] = storage.TaskRelationCounts{Links: len(m.links[id]), Comments: len(m.comments[id])}
+		}
+	}
+	return counts, nil
+}
+func (m *MockWebStorage) CreateLink(_ context.Context, link *models.Link) error {
 	if link.TaskID == "" {
 		return &models.ValidationError{Message: "TaskID is required"}
 	}
+	if link.ID == "" {
+		link.ID = fmt.Sprintf("%s-link-%d", link.TaskID, len(m.links[link.TaskID])+1)
+	}
 	m.links[link.TaskID] = append(m.links[link.TaskID], link)
 	return nil
 }
-func (m *MockWebStorage) GetLink(id string) (*models.Link, error) { return nil, nil }
-func (m *MockWebStorage) UpdateLink(link *models.Link) error      { return nil }
-func (m *MockWebStorage) DeleteLink(id string) error              { return nil }
-func (m *MockWebStorage) CreateComment(comment *models.Comment) error {
-	if comment.TaskID == "" {
-		return &models.ValidationError{Message: "TaskID is required"}
+func (m *MockWebStorage) GetLink(_ context.Context, id string) (*models.Link, error) {
+	for _, links := range m.links {
+		for _, link := range links {
+			if link.ID == id {
+				found := *link
+				return &found, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("link %w", storage.ErrNotFound)
+}
+func (m *MockWebStorage) UpdateLink(_ context.Context, link *models.Link) error {
+	for taskID, links := range m.links {
+		for i, existing := range links {
+			if existing.ID == link.ID {
+				m.links[taskID] = append(links[:i], links[i+1:]...)
+				m.links[link.TaskID] = append(m.links[link.TaskID], link)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("link %w", storage.ErrNotFound)
+}
+func (m *MockWebStorage) UpdateLinkWithOptions(ctx context.Context, link *models.Link, _ storage.UpdateLinkOptions) error {
+	return m.UpdateLink(ctx, link)
+}
+func (m *MockWebStorage) DeleteLink(_ context.Context, id string) error { return nil }
+func (m *MockWebStorage) CreateComment(_ context.Context, comment *models.Comment) error {
+	if err := comment.Validate(); err != nil {
+		return err
 	}
 	m.comments[comment.TaskID] = append(m.comments[comment.TaskID], comment)
 	return nil
 }
-func (m *MockWebStorage) DeleteComment(id string) error { return nil }
-func (m *MockWebStorage) Close() error                  { return nil }
+
+func (m *MockWebStorage) CreateComments(ctx context.Context, comments []*models.Comment) error {
+	for _, comment := range comments {
+		if err := m.CreateComment(ctx, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (m *MockWebStorage) DeleteComment(_ context.Context, id string) error { return nil }
+func (m *MockWebStorage) ListTags(_ context.Context) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+func (m *MockWebStorage) RenameTag(_ context.Context, from, to string) (int, error) {
+	return 0, nil
+}
+func (m *MockWebStorage) GetTaskActivity(_ context.Context, taskID string) ([]models.Activity, error) {
+	return nil, nil
+}
+func (m *MockWebStorage) ImportData(_ context.Context, data *models.ExportData) (*models.ImportResult, error) {
+	return &models.ImportResult{}, nil
+}
+func (m *MockWebStorage) StreamLinks(_ context.Context, fn func(*models.Link) error) error {
+	return nil
+}
+func (m *MockWebStorage) StreamComments(_ context.Context, fn func(*models.Comment) error) error {
+	return nil
+}
+func (m *MockWebStorage) WithTransaction(_ context.Context, fn func(tx storage.Storage) error) error {
+	return fn(m)
+}
+func (m *MockWebStorage) Ping(_ context.Context) error { return m.pingErr }
+func (m *MockWebStorage) Close() error                 { return nil }
 
 // Helper function to create test context
 func createTestContext() context.Context {
@@ -211,10 +379,27 @@ func createHandlerWithSymlinkTemplates(t *testing.T) *WebHandler {
 	})
 
 	mockStorage := NewMockWebStorage()
-	handler := NewWebHandler(mockStorage)
+	handler, err := NewWebHandler(mockStorage)
+	require.NoError(t, err)
 	return handler
 }
 
+// writeMinimalTemplates writes a base layout and a single page into dir
+func writeMinimalTemplates(t *testing.T, dir string) {
+	t.Helper()
+	templates := map[string]string{
+		"base.html": `<!DOCTYPE html>
+<html>
+<head><title>{{.PageTitle}}</title></head>
+<body>{{template "content" .}}</body>
+</html>`,
+		"test.html": `{{define "content"}}{{.PageTitle}}{{end}}`,
+	}
+	for filename, content := range templates {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filename), []byte(content), 0644))
+	}
+}
+
 // Helper function to create test handler with templates
 func createTestHandler(t *testing.T) *WebHandler {
 	// Create a temporary directory structure
@@ -277,7 +462,8 @@ func createTestHandler(t *testing.T) *WebHandler {
 	require.NoError(t, err)
 
 	mockStorage := NewMockWebStorage()
-	handler := NewWebHandler(mockStorage)
+	handler, err := NewWebHandler(mockStorage)
+	require.NoError(t, err)
 
 	// Restore original directory
 	err = os.Chdir(originalDir)
@@ -309,14 +495,7 @@ func TestNewWebHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	// Create a simple test template
-	templateContent := `<!DOCTYPE html>
-<html>
-<head><title>{{.PageTitle}}</title></head>
-<body>{{.PageTitle}}</body>
-</html>`
-
-	err = os.WriteFile(filepath.Join(templatesDir, "test.html"), []byte(templateContent), 0644)
-	require.NoError(t, err)
+	writeMinimalTemplates(t, templatesDir)
 
 	// Change to temp directory temporarily
 	originalDir, err := os.Getwd()
@@ -334,27 +513,40 @@ func TestNewWebHandler(t *testing.T) {
 	mockStorage := NewMockWebStorage()
 
 	// Test that NewWebHandler doesn't panic
-	handler := NewWebHandler(mockStorage)
+	handler, err := NewWebHandler(mockStorage)
+	require.NoError(t, err)
 
 	assert.NotNil(t, handler)
 	assert.NotNil(t, handler.storage)
 	assert.NotNil(t, handler.templates)
 }
 
-func TestWebHandler_HealthCheck(t *testing.T) {
-	handler := createTestHandler(t)
-
-	req := createTestRequest(http.MethodGet, "/health", "")
-	w := httptest.NewRecorder()
-
-	handler.HealthCheck(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+func TestNewWebHandler_BrokenTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    string
+		wantErr string
+	}{
+		{"unknown function", `{{define "content"}}{{shout .PageTitle}}{{end}}`, `function "shout" not defined`},
+		{"missing content block", `<p>{{.PageTitle}}</p>`, "does not define a content block"},
+		{"unknown field", `{{define "content"}}{{.Task.Nickname}}{{end}}`, "failed to render template broken.html"},
+	}
 
-	body := w.Body.String()
-	assert.Contains(t, body, `"status": "healthy"`)
-	assert.Contains(t, body, `"timestamp"`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			templatesDir := filepath.Join(tempDir, "web", "templates")
+			require.NoError(t, os.MkdirAll(templatesDir, 0755))
+			writeMinimalTemplates(t, templatesDir)
+			require.NoError(t, os.WriteFile(filepath.Join(templatesDir, "broken.html"), []byte(tt.page), 0644))
+			t.Chdir(tempDir)
+
+			handler, err := NewWebHandler(NewMockWebStorage())
+			require.Error(t, err)
+			assert.Nil(t, handler)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
 }
 
 func TestWebHandler_OpenAPISpec_Success(t *testing.T) {
@@ -373,14 +565,7 @@ func TestWebHandler_OpenAPISpec_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	// Create a simple test template
-	templateContent := `<!DOCTYPE html>
-<html>
-<head><title>{{.PageTitle}}</title></head>
-<body>{{.PageTitle}}</body>
-</html>`
-
-	err = os.WriteFile(filepath.Join(templatesDir, "test.html"), []byte(templateContent), 0644)
-	require.NoError(t, err)
+	writeMinimalTemplates(t, templatesDir)
 
 	// Create docs directory and swagger.yaml file
 	docsDir := filepath.Join(tempDir, "docs")
@@ -408,7 +593,8 @@ info:
 	require.NoError(t, err)
 
 	mockStorage := NewMockWebStorage()
-	handler := NewWebHandler(mockStorage)
+	handler, err := NewWebHandler(mockStorage)
+	require.NoError(t, err)
 
 	req := createTestRequest(http.MethodGet, "/openapi.yaml", "")
 	w := httptest.NewRecorder()
@@ -440,14 +626,7 @@ func TestWebHandler_OpenAPISpec_NotFound(t *testing.T) {
 	require.NoError(t, err)
 
 	// Create a simple test template
-	templateContent := `<!DOCTYPE html>
-<html>
-<head><title>{{.PageTitle}}</title></head>
-<body>{{.PageTitle}}</body>
-</html>`
-
-	err = os.WriteFile(filepath.Join(templatesDir, "test.html"), []byte(templateContent), 0644)
-	require.NoError(t, err)
+	writeMinimalTemplates(t, templatesDir)
 
 	// Change to temp directory temporarily
 	originalDir, err := os.Getwd()
@@ -462,7 +641,8 @@ func TestWebHandler_OpenAPISpec_NotFound(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage := NewMockWebStorage()
-	handler := NewWebHandler(mockStorage)
+	handler, err := NewWebHandler(mockStorage)
+	require.NoError(t, err)
 
 	req := createTestRequest(http.MethodGet, "/openapi.yaml", "")
 	w := httptest.NewRecorder()
@@ -527,6 +707,40 @@ func TestWebHandler_CreateNewTask_Success(t *testing.T) {
 	assert.NotEqual(t, http.StatusBadRequest, w.Code)
 }
 
+func TestWebHandler_CreateNewTask_NormalizesTags(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+
+	req := createTestRequest(http.MethodPost, "/new", "title=Tagged task&priority=normal&tags=a, b ,,c")
+	w := httptest.NewRecorder()
+
+	handler.NewTask(w, req)
+
+	require.Equal(t, http.StatusSeeOther, w.Code)
+	tasks, err := handler.storage.ListTasks(context.Background(), storage.TaskFilters{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, []string{"a", "b", "c"}, tasks[0].Tags)
+}
+
+func TestWebHandler_CreateNewTask_InvalidLinkRollsBack(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+	// The mock storage has no real transactions
+	handler.storage = memory.New()
+
+	formData := "title=Linked task&priority=normal&notes=Some notes" +
+		"&link_types[]=pull_request&link_urls[]=https://github.com/org/repo/pull/1" +
+		"&link_types[]=other&link_urls[]=not a url"
+	req := createTestRequest(http.MethodPost, "/new", formData)
+	w := httptest.NewRecorder()
+
+	handler.NewTask(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	tasks, err := handler.storage.ListTasks(context.Background(), storage.TaskFilters{})
+	require.NoError(t, err)
+	assert.Empty(t, tasks, "no task is left behind by a failed link")
+}
+
 func TestWebHandler_CreateNewTask_MissingTitle(t *testing.T) {
 	handler := createHandlerWithSymlinkTemplates(t)
 
@@ -585,6 +799,307 @@ func TestWebHandler_CreateNewTask_InvalidFormData(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Title is required")
 }
 
+func createWebTestTask(t *testing.T, handler *WebHandler) *models.Task {
+	t.Helper()
+
+	task := &models.Task{
+		JiraID:   "PROJ-42",
+		Title:    "Original title",
+		Priority: models.Normal,
+		Status:   models.New,
+		Tags:     []string{"backend"},
+	}
+	require.NoError(t, handler.storage.CreateTask(context.Background(), task))
+	return task
+}
+
+func TestWebHandler_EditTask_GET(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+	task := createWebTestTask(t, handler)
+
+	req := createTestRequest(http.MethodGet, "/task/"+task.ID+"/edit", "")
+	w := httptest.NewRecorder()
+
+	handler.TaskDetail(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `action="/task/`+task.ID+`/edit"`)
+	assert.Contains(t, body, `value="Original title"`)
+	assert.Contains(t, body, `value="PROJ-42"`)
+	assert.Contains(t, body, `value="backend"`)
+	assert.Contains(t, body, `<option value="new" selected>`)
+}
+
+func TestWebHandler_EditTask_GET_NotFound(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+
+	req := createTestRequest(http.MethodGet, "/task/missing/edit", "")
+	w := httptest.NewRecorder()
+
+	handler.EditTask(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebHandler_EditTask_POST_Success(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+	task := createWebTestTask(t, handler)
+
+	formData := "jira_id=&title=Updated title&priority=high&status=in_progress&tags=frontend, urgent ,"
+	req := createTestRequest(http.MethodPost, "/task/"+task.ID+"/edit", formData)
+	w := httptest.NewRecorder()
+
+	handler.EditTask(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/task/"+task.ID, w.Header().Get("Location"))
+
+	updated, err := handler.storage.GetTask(context.Background(), task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultNoJira, updated.JiraID)
+	assert.Equal(t, "Updated title", updated.Title)
+	assert.Equal(t, models.High, updated.Priority)
+	assert.Equal(t, models.InProgress, updated.Status)
+	assert.Equal(t, []string{"frontend", "urgent"}, updated.Tags)
+}
+
+func TestWebHandler_EditTask_POST_Blocked(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+	task := createWebTestTask(t, handler)
+
+	formData := "title=Original title&priority=normal&status=blocked&blockers=Waiting on API%0D%0A%0D%0A  Needs review  "
+	req := createTestRequest(http.MethodPost, "/task/"+task.ID+"/edit", formData)
+	w := httptest.NewRecorder()
+
+	handler.EditTask(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+
+	updated, err := handler.storage.GetTask(context.Background(), task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Blocked, updated.Status)
+	assert.Equal(t, []string{"Waiting on API", "Needs review"}, updated.Blockers)
+
+	// The form shows the blockers back, one per line
+	req = createTestRequest(http.MethodGet, "/task/"+task.ID+"/edit", "")
+	w = httptest.NewRecorder()
+	handler.EditTask(w, req)
+	assert.Contains(t, w.Body.String(), ">Waiting on API\nNeeds review</textarea>")
+}
+
+func TestWebHandler_EditTask_POST_MissingTitle(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+	task := createWebTestTask(t, handler)
+
+	req := createTestRequest(http.MethodPost, "/task/"+task.ID+"/edit", "title=&priority=high")
+	w := httptest.NewRecorder()
+
+	handler.EditTask(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Title is required")
+}
+
+func TestWebHandler_EditTask_POST_ValidationError(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+	task := createWebTestTask(t, handler)
+
+	title := strings.Repeat("t", models.MaxTitleLen()+1)
+	formData := "jira_id=&title=" + title + "&priority=high&status=new"
+	req := createTestRequest(http.MethodPost, "/task/"+task.ID+"/edit", formData)
+	w := httptest.NewRecorder()
+
+	handler.EditTask(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.Contains(t, body, `class="form-error"`)
+	assert.Contains(t, body, "title must be at most")
+	assert.Contains(t, body, `value="`+title+`"`, "the submitted values are kept")
+}
+
+func TestWebHandler_PostComment_Success(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+	task := createWebTestTask(t, handler)
+
+	req := createTestRequest(http.MethodPost, "/task/"+task.ID+"/comment", "content=  Looks good to me  ")
+	w := httptest.NewRecorder()
+
+	handler.TaskDetail(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/task/"+task.ID+"#comments", w.Header().Get("Location"))
+
+	comments, err := handler.storage.GetTaskComments(context.Background(), task.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "Looks good to me", comments[0].Content)
+}
+
+func TestWebHandler_PostComment_EmptyContent(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+	task := createWebTestTask(t, handler)
+
+	req := createTestRequest(http.MethodPost, "/task/"+task.ID+"/comment", "content=%20%20")
+	w := httptest.NewRecorder()
+
+	handler.PostComment(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Comment cannot be empty")
+	assert.Contains(t, w.Body.String(), "Original title")
+
+	comments, err := handler.storage.GetTaskComments(context.Background(), task.ID)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+}
+
+func TestWebHandler_PostComment_TooLong(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+	task := createWebTestTask(t, handler)
+
+	content := strings.Repeat("a", models.MaxCommentLen()+1)
+	req := createTestRequest(http.MethodPost, "/task/"+task.ID+"/comment", "content="+content)
+	w := httptest.NewRecorder()
+
+	handler.PostComment(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "content must be at most")
+}
+
+func TestWebHandler_PostComment_MethodNotAllowed(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+
+	req := createTestRequest(http.MethodGet, "/task/task-1/comment", "")
+	w := httptest.NewRecorder()
+
+	handler.PostComment(w, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestWebHandler_Board(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+
+	seed := map[string]models.Status{
+		"Write design doc":   models.New,
+		"Implement endpoint": models.InProgress,
+		"Waiting on infra":   models.Blocked,
+		"Fix flaky test":     models.Done,
+		"Ship release":       models.Done,
+		"Old cleanup":        models.Archived,
+	}
+	for title, status := range seed {
+		require.NoError(t, handler.storage.CreateTask(context.Background(), &models.Task{
+			JiraID:   "PROJ-1",
+			Title:    title,
+			Priority: models.Normal,
+			Status:   status,
+		}))
+	}
+
+	req := createTestRequest(http.MethodGet, "/board", "")
+	w := httptest.NewRecorder()
+
+	handler.Board(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+
+	// Split the page into one chunk per status column
+	columns := map[string]string{}
+	for _, chunk := range strings.Split(body, `<section class="board-column" data-status="`)[1:] {
+		status := chunk[:strings.Index(chunk, `"`)]
+		columns[status] = chunk[:strings.Index(chunk, "</section>")]
+	}
+	require.Len(t, columns, 4)
+
+	for title, status := range seed {
+		for column, html := range columns {
+			if column == string(status) {
+				assert.Contains(t, html, title, "%q should be in the %s column", title, column)
+			} else {
+				assert.NotContains(t, html, title, "%q should not be in the %s column", title, column)
+			}
+		}
+	}
+	assert.NotContains(t, body, "Old cleanup")
+	assert.Contains(t, columns["done"], `<span class="board-column-count">2</span>`)
+}
+
+func TestWebHandler_ToggleTheme(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+
+	req := createTestRequest(http.MethodGet, "/toggle-theme", "")
+	req.Header.Set("Referer", "http://localhost:8080/board?x=1")
+	w := httptest.NewRecorder()
+
+	handler.ToggleTheme(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/board?x=1", w.Header().Get("Location"))
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, themeCookieName, cookies[0].Name)
+	assert.Equal(t, themeDark, cookies[0].Value)
+
+	// The next page render picks the theme up from the cookie
+	req = createTestRequest(http.MethodGet, "/board", "")
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+
+	handler.Board(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `data-theme="dark"`)
+
+	// Toggling again switches back; without a Referer it returns to the dashboard
+	req = createTestRequest(http.MethodPost, "/toggle-theme", "")
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+
+	handler.ToggleTheme(w, req)
+
+	assert.Equal(t, "/", w.Header().Get("Location"))
+	cookies = w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, themeLight, cookies[0].Value)
+}
+
+func TestWebHandler_Board_DefaultTheme(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+
+	req := createTestRequest(http.MethodGet, "/board", "")
+	w := httptest.NewRecorder()
+
+	handler.Board(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `data-theme="light"`)
+}
+
+func TestGroupTasksByStatus(t *testing.T) {
+	tasks := []*models.Task{
+		{ID: "a", Status: models.Done},
+		{ID: "b", Status: models.New},
+		{ID: "c", Status: models.Done},
+		{ID: "d", Status: models.Archived},
+	}
+
+	columns := groupTasksByStatus(tasks)
+
+	require.Len(t, columns, 4)
+	assert.Equal(t, models.New, columns[0].Status)
+	assert.Equal(t, []*models.Task{tasks[1]}, columns[0].Tasks)
+	assert.Empty(t, columns[1].Tasks)
+	assert.Empty(t, columns[2].Tasks)
+	assert.Equal(t, []*models.Task{tasks[0], tasks[2]}, columns[3].Tasks)
+}
+
 func TestWebHandler_StaticFileHandler(t *testing.T) {
 	handler := createTestHandler(t)
 
@@ -596,6 +1111,52 @@ func TestWebHandler_StaticFileHandler(t *testing.T) {
 	_ = staticHandler
 }
 
+func TestWebHandler_NotFound(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+
+	// Unknown paths reach the dashboard through the "/" catch-all
+	req := createTestRequest(http.MethodGet, "/no/such/page", "")
+	w := httptest.NewRecorder()
+
+	handler.Dashboard(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "Page not found")
+	assert.Contains(t, w.Body.String(), "<title>Page Not Found - Michishirube</title>")
+}
+
+func TestWebHandler_NotFound_API(t *testing.T) {
+	handler := createHandlerWithSymlinkTemplates(t)
+
+	req := createTestRequest(http.MethodGet, "/api/nothing-here", "")
+	w := httptest.NewRecorder()
+
+	handler.Dashboard(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"Not found","code":"NOT_FOUND"}`, w.Body.String())
+}
+
+func TestWebHandler_Favicon(t *testing.T) {
+	handler := createTestHandler(t)
+
+	req := createTestRequest(http.MethodGet, "/favicon.ico", "")
+	w := httptest.NewRecorder()
+
+	handler.Favicon(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<svg")
+
+	req = createTestRequest(http.MethodPost, "/favicon.ico", "")
+	w = httptest.NewRecorder()
+	handler.Favicon(w, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
 func TestWebHandler_SwaggerJSON(t *testing.T) {
 	// Create a temporary directory for templates and docs
 	tempDir, err := os.MkdirTemp("", "test_docs")
@@ -612,14 +1173,7 @@ func TestWebHandler_SwaggerJSON(t *testing.T) {
 	require.NoError(t, err)
 
 	// Create a simple test template
-	templateContent := `<!DOCTYPE html>
-<html>
-<head><title>{{.PageTitle}}</title></head>
-<body>{{.PageTitle}}</body>
-</html>`
-
-	err = os.WriteFile(filepath.Join(templatesDir, "test.html"), []byte(templateContent), 0644)
-	require.NoError(t, err)
+	writeMinimalTemplates(t, templatesDir)
 
 	// Create docs directory and swagger.json file
 	docsDir := filepath.Join(tempDir, "docs")
@@ -644,7 +1198,8 @@ func TestWebHandler_SwaggerJSON(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage := NewMockWebStorage()
-	handler := NewWebHandler(mockStorage)
+	handler, err := NewWebHandler(mockStorage)
+	require.NoError(t, err)
 
 	req := createTestRequest(http.MethodGet, "/swagger.json", "")
 	w := httptest.NewRecorder()
@@ -718,7 +1273,8 @@ func TestWebHandler_SwaggerJSON(t *testing.T) {
 	require.NoError(t, err)
 
 	mockStorage := NewMockWebStorage()
-	handler := NewWebHandler(mockStorage)
+	handler, err := NewWebHandler(mockStorage)
+	require.NoError(t, err)
 
 	// Keep the handler in the temp directory context
 	// Don't restore original directory yet
@@ -17,6 +17,7 @@ import (
 	"michishirube/internal/handlers"
 	"michishirube/internal/logger"
 	"michishirube/internal/models"
+	"michishirube/internal/storage"
 	"michishirube/internal/storage/sqlite"
 	"michishirube/testdata"
 
@@ -67,6 +68,7 @@ func setupIntegrationTest(t *testing.T) (*IntegrationTestSuite, func()) {
 // loadFixtureData loads test data from fixtures
 func (suite *IntegrationTestSuite) loadFixtureData(t *testing.T) {
 	t.Helper()
+	ctx := context.Background()
 
 	// Load fixture data
 	var tasks []models.Task
@@ -80,22 +82,23 @@ func (suite *IntegrationTestSuite) loadFixtureData(t *testing.T) {
 
 	// Insert fixture data
 	for _, task := range tasks {
-		err := suite.storage.CreateTask(&task)
+		err := suite.storage.CreateTask(ctx, &task)
 		require.NoError(t, err, "Failed to insert fixture task: %s", task.ID)
 	}
 
 	for _, link := range links {
-		err := suite.storage.CreateLink(&link)
+		err := suite.storage.CreateLink(ctx, &link)
 		require.NoError(t, err, "Failed to insert fixture link: %s", link.ID)
 	}
 
 	for _, comment := range comments {
-		err := suite.storage.CreateComment(&comment)
+		err := suite.storage.CreateComment(ctx, &comment)
 		require.NoError(t, err, "Failed to insert fixture comment: %s", comment.ID)
 	}
 }
 
 func TestIntegration_FullTaskWorkflow(t *testing.T) {
+	ctx := context.Background()
 	suite, cleanup := setupIntegrationTest(t)
 	defer cleanup()
 
@@ -131,7 +134,7 @@ func TestIntegration_FullTaskWorkflow(t *testing.T) {
 	assert.Equal(t, newTask.JiraID, createdTask.JiraID)
 
 	// Verify task was stored in database
-	storedTask, err := suite.storage.GetTask(taskID)
+	storedTask, err := suite.storage.GetTask(ctx, taskID)
 	require.NoError(t, err)
 	assert.Equal(t, createdTask.Title, storedTask.Title)
 
@@ -183,7 +186,7 @@ func TestIntegration_FullTaskWorkflow(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code)
 
 	// Verify task was deleted from database
-	_, err = suite.storage.GetTask(taskID)
+	_, err = suite.storage.GetTask(ctx, taskID)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
@@ -276,6 +279,7 @@ func TestIntegration_TaskListingWithFilters(t *testing.T) {
 }
 
 func TestIntegration_SearchFunctionality(t *testing.T) {
+	ctx := context.Background()
 	suite, cleanup := setupIntegrationTest(t)
 	defer cleanup()
 
@@ -295,14 +299,14 @@ func TestIntegration_SearchFunctionality(t *testing.T) {
 
 	// Insert scenario tasks
 	for _, task := range scenario.Tasks {
-		err := suite.storage.CreateTask(&task)
+		err := suite.storage.CreateTask(ctx, &task)
 		require.NoError(t, err)
 	}
 
 	// Test each search case
 	for _, searchCase := range scenario.SearchCases {
 		t.Run(searchCase.Description, func(t *testing.T) {
-			results, err := suite.storage.SearchTasks(searchCase.Query, false, 10)
+			results, err := suite.storage.SearchTasks(ctx, searchCase.Query, false, false, 10)
 			require.NoError(t, err)
 
 			var resultIDs []string
@@ -317,6 +321,7 @@ func TestIntegration_SearchFunctionality(t *testing.T) {
 }
 
 func TestIntegration_DatabaseMigrations(t *testing.T) {
+	ctx := context.Background()
 	// Test that migrations work correctly on a fresh database
 	tempDir := t.TempDir()
 	tempDBPath := filepath.Join(tempDir, "migration_test.db")
@@ -340,11 +345,11 @@ func TestIntegration_DatabaseMigrations(t *testing.T) {
 		Blockers: []string{},
 	}
 
-	err = storage.CreateTask(task)
+	err = storage.CreateTask(ctx, task)
 	assert.NoError(t, err, "Should be able to create task after migrations")
 
 	// Verify task can be retrieved
-	retrievedTask, err := storage.GetTask(task.ID)
+	retrievedTask, err := storage.GetTask(ctx, task.ID)
 	require.NoError(t, err)
 	assert.Equal(t, task.Title, retrievedTask.Title)
 }
@@ -461,7 +466,86 @@ func TestIntegration_ErrorHandling(t *testing.T) {
 	}
 }
 
+func TestIntegration_ExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for _, format := range []string{"json", "ndjson"} {
+		t.Run(format, func(t *testing.T) {
+			source, cleanupSource := setupIntegrationTest(t)
+			defer cleanupSource()
+			source.loadFixtureData(t)
+
+			// Export the seeded database
+			req := httptest.NewRequest(http.MethodGet, "/api/export?format="+format, nil)
+			w := httptest.NewRecorder()
+			source.taskHandler.HandleExport(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+			exported := w.Body.Bytes()
+
+			// Import into a fresh database
+			target, cleanupTarget := setupIntegrationTest(t)
+			defer cleanupTarget()
+
+			req = httptest.NewRequest(http.MethodPost, "/api/import?format="+format, bytes.NewReader(exported))
+			w = httptest.NewRecorder()
+			target.taskHandler.HandleImport(w, req)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var result models.ImportResult
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+			assert.Zero(t, result.TasksSkipped)
+			assert.Zero(t, result.LinksSkipped)
+			assert.Zero(t, result.CommentsSkipped)
+
+			sourceTasks, err := source.storage.ListTasks(ctx, storage.TaskFilters{IncludeArchived: true})
+			require.NoError(t, err)
+			targetTasks, err := target.storage.ListTasks(ctx, storage.TaskFilters{IncludeArchived: true})
+			require.NoError(t, err)
+			assert.Len(t, targetTasks, len(sourceTasks))
+			assert.Equal(t, len(sourceTasks), result.TasksImported)
+
+			var sourceLinks, targetLinks, sourceComments, targetComments int
+			for _, task := range sourceTasks {
+				links, err := source.storage.GetTaskLinks(ctx, task.ID)
+				require.NoError(t, err)
+				sourceLinks += len(links)
+
+				links, err = target.storage.GetTaskLinks(ctx, task.ID)
+				require.NoError(t, err)
+				targetLinks += len(links)
+
+				comments, err := source.storage.GetTaskComments(ctx, task.ID)
+				require.NoError(t, err)
+				sourceComments += len(comments)
+
+				comments, err = target.storage.GetTaskComments(ctx, task.ID)
+				require.NoError(t, err)
+				targetComments += len(comments)
+			}
+			assert.Positive(t, sourceLinks)
+			assert.Positive(t, sourceComments)
+			assert.Equal(t, sourceLinks, targetLinks)
+			assert.Equal(t, sourceComments, targetComments)
+			assert.Equal(t, sourceLinks, result.LinksImported)
+			assert.Equal(t, sourceComments, result.CommentsImported)
+
+			// Importing the same backup again skips every record
+			req = httptest.NewRequest(http.MethodPost, "/api/import?format="+format, bytes.NewReader(exported))
+			w = httptest.NewRecorder()
+			target.taskHandler.HandleImport(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var again models.ImportResult
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&again))
+			assert.Zero(t, again.TasksImported)
+			assert.Equal(t, len(sourceTasks), again.TasksSkipped)
+			assert.Equal(t, sourceLinks, again.LinksSkipped)
+			assert.Equal(t, sourceComments, again.CommentsSkipped)
+		})
+	}
+}
+
 func TestIntegration_PerformanceWithLargeDataset(t *testing.T) {
+	ctx := context.Background()
 	if testing.Short() {
 		t.Skip("Skipping performance test in short mode")
 	}
@@ -486,7 +570,7 @@ func TestIntegration_PerformanceWithLargeDataset(t *testing.T) {
 			Blockers: []string{},
 		}
 
-		err := suite.storage.CreateTask(task)
+		err := suite.storage.CreateTask(ctx, task)
 		require.NoError(t, err)
 		tasks[i] = task
 	}
@@ -517,7 +601,7 @@ func TestIntegration_PerformanceWithLargeDataset(t *testing.T) {
 	// Test search performance
 	start = time.Now()
 
-	results, err := suite.storage.SearchTasks("performance", false, 50)
+	results, err := suite.storage.SearchTasks(ctx, "performance", false, false, 50)
 	require.NoError(t, err)
 
 	searchDuration := time.Since(start)
@@ -564,6 +648,7 @@ func setupIntegrationTestForBench(b *testing.B) (*IntegrationTestSuite, func())
 
 // Benchmark for integration performance monitoring
 func BenchmarkIntegration_TaskCRUD(b *testing.B) {
+	ctx := context.Background()
 	suite, cleanup := setupIntegrationTestForBench(b)
 	defer cleanup()
 
@@ -581,26 +666,26 @@ func BenchmarkIntegration_TaskCRUD(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		// Create
 		task.ID = "" // Reset ID for new creation
-		err := suite.storage.CreateTask(task)
+		err := suite.storage.CreateTask(ctx, task)
 		if err != nil {
 			b.Fatal(err)
 		}
 
 		// Read
-		_, err = suite.storage.GetTask(task.ID)
+		_, err = suite.storage.GetTask(ctx, task.ID)
 		if err != nil {
 			b.Fatal(err)
 		}
 
 		// Update
 		task.Status = models.InProgress
-		err = suite.storage.UpdateTask(task)
+		err = suite.storage.UpdateTask(ctx, task)
 		if err != nil {
 			b.Fatal(err)
 		}
 
 		// Delete
-		err = suite.storage.DeleteTask(task.ID)
+		err = suite.storage.DeleteTask(ctx, task.ID)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -2,8 +2,13 @@ package logger
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 type contextKey string
@@ -43,6 +48,82 @@ func NewJSONLogger(level slog.Level) *slog.Logger {
 	return slog.New(handler)
 }
 
+// Default rotation settings for file logging
+const (
+	DefaultLogMaxSizeMB  = 100
+	DefaultLogMaxAgeDays = 28
+	DefaultLogMaxBackups = 5
+)
+
+type fileOptions struct {
+	maxSizeMB  int
+	maxAgeDays int
+	maxBackups int
+	tee        []io.Writer
+}
+
+// FileOption customizes NewFileLogger
+type FileOption func(*fileOptions)
+
+// WithMaxSizeMB sets the size in megabytes at which the log file is rotated
+func WithMaxSizeMB(size int) FileOption {
+	return func(o *fileOptions) {
+		if size > 0 {
+			o.maxSizeMB = size
+		}
+	}
+}
+
+// WithMaxAgeDays sets how many days rotated log files are kept
+func WithMaxAgeDays(days int) FileOption {
+	return func(o *fileOptions) {
+		if days > 0 {
+			o.maxAgeDays = days
+		}
+	}
+}
+
+// WithTee also writes every log line to the given writers (e.g. os.Stdout)
+func WithTee(writers ...io.Writer) FileOption {
+	return func(o *fileOptions) {
+		o.tee = append(o.tee, writers...)
+	}
+}
+
+// NewFileLogger creates a text logger writing to a size/age rotated file.
+// The returned Closer must be closed on shutdown to flush the file.
+func NewFileLogger(path string, level slog.Level, opts ...FileOption) (*slog.Logger, io.Closer, error) {
+	options := fileOptions{
+		maxSizeMB:  DefaultLogMaxSizeMB,
+		maxAgeDays: DefaultLogMaxAgeDays,
+		maxBackups: DefaultLogMaxBackups,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	rotator := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    options.maxSizeMB,
+		MaxAge:     options.maxAgeDays,
+		MaxBackups: options.maxBackups,
+	}
+
+	var out io.Writer = rotator
+	if len(options.tee) > 0 {
+		out = io.MultiWriter(append([]io.Writer{rotator}, options.tee...)...)
+	}
+
+	handler := slog.NewTextHandler(out, &slog.HandlerOptions{
+		Level: level,
+	})
+	return slog.New(handler), rotator, nil
+}
+
 // WithFields adds structured fields to the logger in context
 func WithFields(ctx context.Context, fields ...any) context.Context {
 	logger := FromContext(ctx)
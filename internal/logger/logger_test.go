@@ -1,11 +1,15 @@
 package logger
 
 import (
+	"bytes"
 	"context"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWithLogger_FromContext(t *testing.T) {
@@ -107,4 +111,39 @@ func TestNestedContext(t *testing.T) {
 	
 	retrievedLogger := FromContext(ctx)
 	assert.Equal(t, logger2, retrievedLogger)
-}
\ No newline at end of file
+}
+
+func TestNewFileLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "michishirube.log")
+
+	logger, closer, err := NewFileLogger(path, slog.LevelInfo, WithMaxSizeMB(10))
+	require.NoError(t, err)
+
+	logger.Info("first line", "key", "value")
+	logger.Warn("second line")
+	logger.Debug("filtered out by level")
+	require.NoError(t, closer.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "first line")
+	assert.Contains(t, string(content), "key=value")
+	assert.Contains(t, string(content), "second line")
+	assert.NotContains(t, string(content), "filtered out by level")
+}
+
+func TestNewFileLogger_Tee(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "michishirube.log")
+	var buf bytes.Buffer
+
+	logger, closer, err := NewFileLogger(path, slog.LevelInfo, WithTee(&buf))
+	require.NoError(t, err)
+
+	logger.Info("teed line")
+	require.NoError(t, closer.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "teed line")
+	assert.Contains(t, buf.String(), "teed line")
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ActivityType string
+
+const (
+	ActivityTaskCreated   ActivityType = "task_created"
+	ActivityTaskUpdated   ActivityType = "task_updated"
+	ActivityStatusChanged ActivityType = "status_changed"
+	ActivityLinkAdded     ActivityType = "link_added"
+	ActivityCommentAdded  ActivityType = "comment_added"
+)
+
+// Activity represents one entry in a task's timeline
+type Activity struct {
+	ID        int64           `json:"id" db:"id" example:"42"`                                             // Sequential identifier
+	TaskID    string          `json:"task_id" db:"task_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Associated task ID
+	Type      ActivityType    `json:"type" db:"type" example:"status_changed"`                             // What happened
+	Timestamp time.Time       `json:"timestamp" db:"timestamp" example:"2024-01-15T11:00:00Z"`             // When it happened
+	Payload   json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`                           // Event details, e.g. {"from":"new","to":"in_progress"}
+}
@@ -1,9 +1,19 @@
 package models
 
 import (
+	"fmt"
 	"time"
+	"unicode/utf8"
 )
 
+// DefaultMaxCommentLen is how many characters a comment may hold unless configured otherwise
+const DefaultMaxCommentLen = 10000
+
+// MaxCommentLen returns the longest comment Comment.Validate accepts, in characters
+func MaxCommentLen() int {
+	return active.maxCommentLen
+}
+
 // Comment represents a comment associated with a task
 type Comment struct {
 	ID        string    `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440002"`                  // Unique identifier
@@ -14,10 +24,13 @@ type Comment struct {
 
 func (c *Comment) Validate() error {
 	if c.TaskID == "" {
-		return &ValidationError{Field: "task_id", Message: "task_id is required"}
+		return &ValidationError{Field: "task_id", Code: CodeRequired, Message: "task_id is required"}
 	}
 	if c.Content == "" {
-		return &ValidationError{Field: "content", Message: "content is required"}
+		return &ValidationError{Field: "content", Code: CodeRequired, Message: "content is required"}
+	}
+	if utf8.RuneCountInString(c.Content) > active.maxCommentLen {
+		return &ValidationError{Field: "content", Code: CodeTooLong, Message: fmt.Sprintf("content must be at most %d characters", active.maxCommentLen)}
 	}
 	return nil
 }
\ No newline at end of file
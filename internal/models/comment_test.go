@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -58,4 +59,20 @@ func TestComment_Validate(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestComment_ValidateMaxCommentLen(t *testing.T) {
+	configure(t, Limits{MaxCommentLen: 20})
+
+	comment := Comment{TaskID: "task-123", Content: strings.Repeat("ü", 20)}
+	require.NoError(t, comment.Validate(), "exactly at the limit, counted in characters")
+
+	comment.Content = strings.Repeat("a", 21)
+	err := comment.Validate()
+	require.Error(t, err)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "content", validationErr.Field)
+
+	configure(t, Limits{MaxCommentLen: -1})
+	assert.Equal(t, DefaultMaxCommentLen, MaxCommentLen())
+}
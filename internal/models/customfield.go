@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxCustomFields        = 50   // Custom fields a task may have
+	MaxCustomFieldValueLen = 1000 // Characters allowed in a custom field value
+)
+
+// customFieldKeyPattern keeps keys usable as ?custom.<key>= query parameters
+// and as JSON paths without quoting surprises
+var customFieldKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// IsValidCustomFieldKey reports whether key may name a custom field: 1 to 64
+// letters, digits, underscores or hyphens
+func IsValidCustomFieldKey(key string) bool {
+	return customFieldKeyPattern.MatchString(key)
+}
+
+// SetCustomField sets a custom field, replacing any previous value. An empty
+// value removes the field.
+func (t *Task) SetCustomField(key, value string) {
+	if value == "" {
+		delete(t.CustomFields, key)
+		return
+	}
+	if t.CustomFields == nil {
+		t.CustomFields = make(map[string]string)
+	}
+	t.CustomFields[key] = value
+}
+
+// GetCustomField returns a custom field's value and whether the task has it
+func (t *Task) GetCustomField(key string) (string, bool) {
+	value, ok := t.CustomFields[key]
+	return value, ok
+}
+
+// normalizeCustomFields trims values and drops empty ones, like
+// SetCustomField does, then checks keys, value lengths and the field count.
+// A task left without fields gets a nil map.
+func normalizeCustomFields(fields map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(fields))
+	for key, value := range fields {
+		if !IsValidCustomFieldKey(key) {
+			return nil, &ValidationError{Field: "custom_fields", Code: CodeInvalidFormat,
+				Message: fmt.Sprintf("custom field key %q must be 1 to 64 letters, digits, underscores or hyphens", key)}
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if utf8.RuneCountInString(value) > MaxCustomFieldValueLen {
+			return nil, &ValidationError{Field: "custom_fields", Code: CodeTooLong,
+				Message: fmt.Sprintf("custom field %q must be at most %d characters", key, MaxCustomFieldValueLen)}
+		}
+		normalized[key] = value
+	}
+
+	if len(normalized) > MaxCustomFields {
+		return nil, &ValidationError{Field: "custom_fields", Code: CodeTooLong,
+			Message: fmt.Sprintf("a task can have at most %d custom fields", MaxCustomFields)}
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
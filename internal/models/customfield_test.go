@@ -0,0 +1,55 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask_CustomFieldAccessors(t *testing.T) {
+	var task Task
+
+	_, ok := task.GetCustomField("sprint")
+	assert.False(t, ok)
+
+	task.SetCustomField("sprint", "12")
+	value, ok := task.GetCustomField("sprint")
+	assert.True(t, ok)
+	assert.Equal(t, "12", value)
+
+	task.SetCustomField("sprint", "")
+	_, ok = task.GetCustomField("sprint")
+	assert.False(t, ok, "an empty value removes the field")
+}
+
+func TestTask_ValidateCustomFields(t *testing.T) {
+	task := Task{Title: "Fields", CustomFields: map[string]string{"sprint": " 12 ", "epic": "  "}}
+	require.NoError(t, task.Validate())
+	assert.Equal(t, map[string]string{"sprint": "12"}, task.CustomFields, "values are trimmed and blanks dropped")
+
+	task = Task{Title: "Blank", CustomFields: map[string]string{"epic": ""}}
+	require.NoError(t, task.Validate())
+	assert.Nil(t, task.CustomFields)
+
+	tests := []struct {
+		name   string
+		fields map[string]string
+		code   string
+	}{
+		{"key with a space", map[string]string{"story points": "3"}, CodeInvalidFormat},
+		{"key with a dot", map[string]string{"a.b": "3"}, CodeInvalidFormat},
+		{"key too long", map[string]string{strings.Repeat("k", 65): "3"}, CodeInvalidFormat},
+		{"value too long", map[string]string{"notes": strings.Repeat("x", MaxCustomFieldValueLen+1)}, CodeTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := Task{Title: "Fields", CustomFields: tt.fields}
+			var validationErr *ValidationError
+			require.ErrorAs(t, task.Validate(), &validationErr)
+			assert.Equal(t, "custom_fields", validationErr.Field)
+			assert.Equal(t, tt.code, validationErr.Code)
+		})
+	}
+}
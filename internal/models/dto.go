@@ -1,20 +1,40 @@
 package models
 
+import "time"
+
 // API Request/Response DTOs for Swagger documentation
 
 // TaskListResponse represents the response for listing tasks
 type TaskListResponse struct {
-	Tasks  []*Task `json:"tasks"`  // List of tasks
-	Total  int     `json:"total" example:"42"`   // Total number of tasks
-	Limit  int     `json:"limit" example:"20"`   // Request limit
-	Offset int     `json:"offset" example:"0"`   // Request offset
+	Tasks      []*TaskListItem `json:"tasks"`                 // List of tasks
+	Total      int             `json:"total" example:"42"`    // Total number of tasks
+	Limit      int             `json:"limit" example:"20"`    // Request limit
+	Offset     int             `json:"offset" example:"0"`    // Request offset
+	NextCursor string          `json:"next_cursor,omitempty"` // Pass as after= to fetch the next page; omitted on the last page
+}
+
+// TaskListItem is a task in a list response together with how many links and
+// comments it has, so lists can show them without fetching each task
+type TaskListItem struct {
+	Task
+	LinkCount    int `json:"link_count" example:"3"`    // Links attached to the task
+	CommentCount int `json:"comment_count" example:"2"` // Comments on the task
+}
+
+// CommentListResponse represents one page of a task's comments
+type CommentListResponse struct {
+	Comments []*Comment `json:"comments"`           // Comments on this page, oldest first
+	Total    int        `json:"total" example:"42"` // Total comments on the task
+	Limit    int        `json:"limit" example:"50"` // Page size
+	Offset   int        `json:"offset" example:"0"` // Page offset
 }
 
 // TaskWithDetails represents a task with all related data
 type TaskWithDetails struct {
 	*Task
-	Links    []*Link    `json:"links"`    // Associated links
-	Comments []*Comment `json:"comments"` // Associated comments
+	Links    []*Link    `json:"links"`             // Associated links
+	Comments []*Comment `json:"comments"`          // Associated comments
+	Related  []*Task    `json:"related,omitempty"` // Other tasks sharing tags, most shared first
 }
 
 // CreateTaskRequest represents request to create a new task
@@ -24,6 +44,8 @@ type CreateTaskRequest struct {
 	Priority Priority `json:"priority" example:"high"`                                // Task priority
 	Tags     []string `json:"tags"`      // Task tags
 	Blockers []string `json:"blockers"` // Blocking issues
+	Links    []CreateLinkRequest `json:"links,omitempty"` // Links to create with the task (task_id is ignored)
+	CustomFields map[string]string `json:"custom_fields,omitempty"` // Free-form key/value data such as sprint or story points
 }
 
 // UpdateTaskRequest represents request to update a task
@@ -34,6 +56,7 @@ type UpdateTaskRequest struct {
 	Status   Status   `json:"status" example:"in_progress"`                           // Task status
 	Tags     []string `json:"tags"`      // Task tags
 	Blockers []string `json:"blockers"` // Blocking issues
+	CustomFields map[string]string `json:"custom_fields,omitempty"` // Replaces all custom fields
 }
 
 // PatchTaskRequest represents request to partially update a task
@@ -43,6 +66,32 @@ type PatchTaskRequest struct {
 	Title    *string   `json:"title,omitempty" example:"Updated title"`                // Task title
 	Tags     []string  `json:"tags,omitempty"`     // Task tags
 	Blockers []string  `json:"blockers,omitempty"` // Blocking issues
+	Starred  *bool     `json:"starred,omitempty" example:"true"` // Whether the task is starred
+	CustomFields map[string]*string `json:"custom_fields,omitempty"` // Custom fields to set; other fields are kept, and null or "" removes one
+}
+
+// MergeTasksRequest represents request to merge one task into another
+type MergeTasksRequest struct {
+	Source       string `json:"source" example:"550e8400-e29b-41d4-a716-446655440000"` // Task merged away
+	Target       string `json:"target" example:"550e8400-e29b-41d4-a716-446655440003"` // Task that survives the merge
+	Prefer       string `json:"prefer,omitempty" example:"target"`                     // Which task's scalar fields win: target (default) or source
+	DeleteSource bool   `json:"delete_source,omitempty" example:"false"`                // Delete the source instead of archiving it
+}
+
+// RenameTagRequest represents request to rename a tag across all tasks
+type RenameTagRequest struct {
+	From string `json:"from" example:"k8s"`      // Tag to replace
+	To   string `json:"to" example:"kubernetes"` // Replacement tag; merged if a task already has it
+}
+
+// RenameTagResponse reports how many tasks a tag rename changed
+type RenameTagResponse struct {
+	Affected int `json:"affected" example:"4"` // Tasks whose tags were rewritten
+}
+
+// MoveLinkRequest names the task a link should be moved or copied to
+type MoveLinkRequest struct {
+	TaskID string `json:"task_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Destination task ID
 }
 
 // CreateLinkRequest represents request to create a new link
@@ -70,6 +119,17 @@ type CreateCommentRequest struct {
 	Content string `json:"content" example:"Found the root cause in the controller"`             // Comment content
 }
 
+// BulkCommentItem is one comment of a bulk comment import
+type BulkCommentItem struct {
+	Content   string     `json:"content" example:"Reproduced on staging"`              // Comment content
+	CreatedAt *time.Time `json:"created_at,omitempty" example:"2024-01-10T09:00:00Z"` // Original time of the comment; defaults to now
+}
+
+// BulkCommentResponse lists the comments created by a bulk import
+type BulkCommentResponse struct {
+	Comments []*Comment `json:"comments"` // Created comments, in request order
+}
+
 // CreateCommentResponse represents response when creating a comment
 type CreateCommentResponse struct {
 	ID      string `json:"id" example:"550e8400-e29b-41d4-a716-446655440002"`                    // Comment ID
@@ -83,13 +143,53 @@ type DeleteCommentResponse struct {
 
 // ReportResponse represents the status report response
 type ReportResponse struct {
-	WorkingOn []*TaskWithDetails `json:"working_on"` // Tasks in progress or completed
-	NextUp    []*TaskWithDetails `json:"next_up"`    // Tasks to work on next
-	Blockers  []*TaskWithDetails `json:"blockers"`   // Blocked tasks
+	GeneratedAt time.Time          `json:"generated_at"` // When the report was generated
+	WorkingOn   []*TaskWithDetails `json:"working_on"`   // Tasks in progress or completed
+	NextUp      []*TaskWithDetails `json:"next_up"`      // Tasks to work on next
+	Blockers    []*TaskWithDetails `json:"blockers"`     // Blocked tasks
+	Stale       []*TaskWithDetails `json:"stale"`        // In-progress tasks not updated recently, oldest first
+	Summary     *ReportSummary     `json:"summary"`      // Headline counts over the reported tasks
+}
+
+// ReportSummary holds headline counts for a status report
+type ReportSummary struct {
+	Total      int              `json:"total" example:"8"`
+	ByStatus   map[Status]int   `json:"by_status"`
+	ByPriority map[Priority]int `json:"by_priority"`
+}
+
+// ExportData represents a full backup of all tasks, links and comments
+type ExportData struct {
+	Tasks    []*Task    `json:"tasks"`    // All tasks, including archived ones
+	Links    []*Link    `json:"links"`    // All links
+	Comments []*Comment `json:"comments"` // All comments
+}
+
+// ImportResult reports how many records an import inserted or skipped
+type ImportResult struct {
+	TasksImported    int `json:"tasks_imported" example:"12"`   // Tasks inserted
+	TasksSkipped     int `json:"tasks_skipped" example:"3"`     // Tasks whose ID already existed
+	LinksImported    int `json:"links_imported" example:"20"`   // Links inserted
+	LinksSkipped     int `json:"links_skipped" example:"0"`     // Links whose ID already existed
+	CommentsImported int `json:"comments_imported" example:"8"` // Comments inserted
+	CommentsSkipped  int `json:"comments_skipped" example:"1"`  // Comments whose ID already existed
 }
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error string `json:"error" example:"Task not found"`           // Error message
-	Code  string `json:"code,omitempty" example:"TASK_NOT_FOUND"`  // Error code
+	Code  string `json:"code,omitempty" example:"NOT_FOUND"`       // Error code
+}
+
+// ValidationErrorResponse is the body of a 400 for input that failed
+// validation, so clients can point at the offending field
+type ValidationErrorResponse struct {
+	Error FieldError `json:"error"` // Offending field and why it was rejected
+}
+
+// FieldError describes which request field failed validation and why
+type FieldError struct {
+	Field   string `json:"field,omitempty" example:"title"`      // Field that failed validation, when known
+	Message string `json:"message" example:"title is required"` // What is wrong with it
+	Code    string `json:"code" example:"REQUIRED"`              // Machine-readable reason, e.g. REQUIRED or TOO_LONG
 }
\ No newline at end of file
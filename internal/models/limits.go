@@ -0,0 +1,92 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Limits are the configurable rules and defaults the models validate with.
+// Zero fields fall back to their defaults, so Limits{} restores them all.
+type Limits struct {
+	NoJiraID           string            // Placeholder Jira ID of tasks without a ticket (defaults to DefaultNoJira)
+	JiraIDPattern      string            // Regex other Jira IDs must match in full (empty accepts any ID)
+	MaxTitleLen        int               // Longest task title, in characters (defaults to DefaultMaxTitleLen)
+	MaxCommentLen      int               // Longest comment, in characters (defaults to DefaultMaxCommentLen)
+	NewTaskPriority    Priority          // Priority of tasks created without one (defaults to DefaultPriority)
+	NewTaskStatus      Status            // Status of tasks created without one (defaults to DefaultStatus)
+	PriorityOrder      []Priority        // Priorities from most to least urgent (defaults to DefaultPriorityOrder)
+	EnforceTransitions bool              // Reject status changes outside the workflow in ValidateTransition
+	TagColors          map[string]string // #rrggbb colors replacing the generated color of specific tags
+	AllowedURLSchemes  []string          // URL schemes Link.Validate accepts (defaults to DefaultAllowedURLSchemes)
+}
+
+// limits is a checked Limits with the defaults filled in
+type limits struct {
+	noJiraID           string
+	jiraIDPattern      *regexp.Regexp
+	maxTitleLen        int
+	maxCommentLen      int
+	newTaskPriority    Priority
+	newTaskStatus      Status
+	priorityOrder      []Priority
+	enforceTransitions bool
+	tagColors          map[string]string
+	allowedURLSchemes  []string
+}
+
+// active holds the limits every model validates with
+var active = limits{
+	noJiraID:          DefaultNoJira,
+	maxTitleLen:       DefaultMaxTitleLen,
+	maxCommentLen:     DefaultMaxCommentLen,
+	newTaskPriority:   DefaultPriority,
+	newTaskStatus:     DefaultStatus,
+	priorityOrder:     DefaultPriorityOrder,
+	allowedURLSchemes: DefaultAllowedURLSchemes,
+}
+
+// Configure checks l and makes it the limits every model validates with. On
+// error the current limits are kept. It is meant to be called once at startup.
+func Configure(l Limits) error {
+	checked, err := l.check()
+	if err != nil {
+		return err
+	}
+	active = checked
+	return nil
+}
+
+// check validates every field of l and fills in the defaults
+func (l Limits) check() (limits, error) {
+	checked := limits{
+		noJiraID:           strings.TrimSpace(l.NoJiraID),
+		maxTitleLen:        l.MaxTitleLen,
+		maxCommentLen:      l.MaxCommentLen,
+		enforceTransitions: l.EnforceTransitions,
+		allowedURLSchemes:  normalizeURLSchemes(l.AllowedURLSchemes),
+	}
+	if checked.noJiraID == "" {
+		checked.noJiraID = DefaultNoJira
+	}
+	if checked.maxTitleLen <= 0 {
+		checked.maxTitleLen = DefaultMaxTitleLen
+	}
+	if checked.maxCommentLen <= 0 {
+		checked.maxCommentLen = DefaultMaxCommentLen
+	}
+
+	var err error
+	if checked.jiraIDPattern, err = compileJiraIDPattern(l.JiraIDPattern); err != nil {
+		return limits{}, err
+	}
+	if checked.newTaskPriority, checked.newTaskStatus, err = checkNewTaskDefaults(l.NewTaskPriority, l.NewTaskStatus); err != nil {
+		return limits{}, err
+	}
+	if checked.priorityOrder, err = checkPriorityOrder(l.PriorityOrder); err != nil {
+		return limits{}, err
+	}
+	if checked.tagColors, err = normalizeTagColors(l.TagColors); err != nil {
+		return limits{}, err
+	}
+	return checked, nil
+}
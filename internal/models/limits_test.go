@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// configure applies l for the rest of the test and restores the defaults after
+func configure(t *testing.T, l Limits) {
+	t.Helper()
+	require.NoError(t, Configure(l))
+	t.Cleanup(func() { _ = Configure(Limits{}) })
+}
+
+func TestConfigure(t *testing.T) {
+	configure(t, Limits{
+		NoJiraID:           "NONE",
+		MaxTitleLen:        10,
+		MaxCommentLen:      20,
+		NewTaskPriority:    High,
+		EnforceTransitions: true,
+	})
+	assert.Equal(t, "NONE", NoJiraID())
+	assert.Equal(t, 10, MaxTitleLen())
+	assert.Equal(t, 20, MaxCommentLen())
+	assert.Equal(t, High, NewTaskPriority())
+	assert.True(t, EnforceTransitions())
+
+	err := Configure(Limits{MaxTitleLen: 50, JiraIDPattern: "("})
+	require.Error(t, err)
+	assert.Equal(t, 10, MaxTitleLen(), "a rejected configuration changes nothing")
+
+	require.NoError(t, Configure(Limits{}))
+	assert.Equal(t, DefaultNoJira, NoJiraID())
+	assert.Equal(t, DefaultMaxTitleLen, MaxTitleLen())
+	assert.Equal(t, DefaultMaxCommentLen, MaxCommentLen())
+	assert.Equal(t, DefaultPriority, NewTaskPriority())
+	assert.Equal(t, DefaultStatus, NewTaskStatus())
+	assert.Equal(t, DefaultPriorityOrder, PriorityOrder())
+	assert.Equal(t, DefaultAllowedURLSchemes, AllowedURLSchemes())
+	assert.False(t, EnforceTransitions())
+}
@@ -1,5 +1,12 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
 type LinkType string
 
 const (
@@ -7,6 +14,9 @@ const (
 	SlackThread   LinkType = "slack_thread"
 	JiraTicket    LinkType = "jira_ticket"
 	Documentation LinkType = "documentation"
+	Commit        LinkType = "commit"
+	DesignDoc     LinkType = "design_doc"
+	Incident      LinkType = "incident"
 	Other         LinkType = "other"
 )
 
@@ -21,9 +31,42 @@ type Link struct {
 	Metadata string   `json:"metadata" db:"metadata" example:"{\"pr_number\": 456, \"author\": \"user\"}"`  // Additional metadata
 }
 
+// DefaultAllowedURLSchemes are the link URL schemes accepted unless
+// Limits.AllowedURLSchemes overrides them
+var DefaultAllowedURLSchemes = []string{"http", "https", "slack"}
+
+// normalizeURLSchemes lowercases and trims the schemes Link.Validate accepts.
+// An empty list stands for the defaults.
+func normalizeURLSchemes(schemes []string) []string {
+	if len(schemes) == 0 {
+		return DefaultAllowedURLSchemes
+	}
+	normalized := make([]string, 0, len(schemes))
+	for _, scheme := range schemes {
+		if scheme = strings.ToLower(strings.TrimSpace(scheme)); scheme != "" {
+			normalized = append(normalized, scheme)
+		}
+	}
+	return normalized
+}
+
+// AllowedURLSchemes returns the schemes Link.Validate currently accepts
+func AllowedURLSchemes() []string {
+	return active.allowedURLSchemes
+}
+
+func isAllowedURLScheme(scheme string) bool {
+	for _, allowed := range active.allowedURLSchemes {
+		if strings.EqualFold(scheme, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
 func (lt LinkType) IsValid() bool {
 	switch lt {
-	case PullRequest, SlackThread, JiraTicket, Documentation, Other:
+	case PullRequest, SlackThread, JiraTicket, Documentation, Commit, DesignDoc, Incident, Other:
 		return true
 	}
 	return false
@@ -31,16 +74,55 @@ func (lt LinkType) IsValid() bool {
 
 func (l *Link) Validate() error {
 	if l.TaskID == "" {
-		return &ValidationError{Field: "task_id", Message: "task_id is required"}
+		return &ValidationError{Field: "task_id", Code: CodeRequired, Message: "task_id is required"}
 	}
 	if l.URL == "" {
-		return &ValidationError{Field: "url", Message: "url is required"}
+		return &ValidationError{Field: "url", Code: CodeRequired, Message: "url is required"}
+	}
+	u, err := url.ParseRequestURI(l.URL)
+	if err != nil || u.Scheme == "" {
+		return &ValidationError{Field: "url", Code: CodeInvalidFormat, Message: "url must be an absolute URL"}
+	}
+	if !isAllowedURLScheme(u.Scheme) {
+		return &ValidationError{Field: "url", Code: CodeNotAllowed, Message: fmt.Sprintf("url scheme %q is not allowed", u.Scheme)}
+	}
+	if (u.Scheme == "http" || u.Scheme == "https") && u.Host == "" {
+		return &ValidationError{Field: "url", Code: CodeInvalidFormat, Message: "url must include a host"}
 	}
 	if !l.Type.IsValid() {
-		return &ValidationError{Field: "type", Message: "invalid link type"}
+		return &ValidationError{Field: "type", Code: CodeInvalid, Message: "invalid link type"}
+	}
+	if l.Metadata == "" {
+		l.Metadata = "{}"
+	} else if !json.Valid([]byte(l.Metadata)) {
+		return &ValidationError{Field: "metadata", Code: CodeInvalidFormat, Message: "metadata must be valid JSON"}
 	}
 	if l.Title == "" {
 		l.Title = l.URL
 	}
 	return nil
+}
+
+// MetadataMap decodes Metadata into a map. It returns an empty map when
+// Metadata is empty or isn't a JSON object.
+func (l *Link) MetadataMap() map[string]any {
+	m := map[string]any{}
+	if l.Metadata != "" {
+		_ = json.Unmarshal([]byte(l.Metadata), &m)
+	}
+	return m
+}
+
+// SetMetadata stores m as the link's JSON metadata
+func (l *Link) SetMetadata(m map[string]any) error {
+	if m == nil {
+		l.Metadata = "{}"
+		return nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	l.Metadata = string(data)
+	return nil
 }
\ No newline at end of file
@@ -64,6 +64,76 @@ func TestLink_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "type: invalid link type",
 		},
+		{
+			name: "valid link with slack scheme",
+			link: Link{
+				TaskID: "task-123",
+				Type:   SlackThread,
+				URL:    "slack://channel?team=T123&id=C456",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid link - URL without scheme",
+			link: Link{
+				TaskID: "task-123",
+				Type:   PullRequest,
+				URL:    "github.com/org/repo/pull/123",
+			},
+			wantErr: true,
+			errMsg:  "url: url must be an absolute URL",
+		},
+		{
+			name: "invalid link - misspelled scheme",
+			link: Link{
+				TaskID: "task-123",
+				Type:   PullRequest,
+				URL:    "htps:/github.com/org/repo",
+			},
+			wantErr: true,
+			errMsg:  `url: url scheme "htps" is not allowed`,
+		},
+		{
+			name: "invalid link - disallowed scheme",
+			link: Link{
+				TaskID: "task-123",
+				Type:   Other,
+				URL:    "javascript:alert(1)",
+			},
+			wantErr: true,
+			errMsg:  `url: url scheme "javascript" is not allowed`,
+		},
+		{
+			name: "invalid link - http URL without host",
+			link: Link{
+				TaskID: "task-123",
+				Type:   Other,
+				URL:    "https:/github.com",
+			},
+			wantErr: true,
+			errMsg:  "url: url must include a host",
+		},
+		{
+			name: "valid link with JSON metadata",
+			link: Link{
+				TaskID:   "task-123",
+				Type:     PullRequest,
+				URL:      "https://github.com/org/repo/pull/123",
+				Metadata: `{"pr_number": 123}`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid link - metadata is not JSON",
+			link: Link{
+				TaskID:   "task-123",
+				Type:     PullRequest,
+				URL:      "https://github.com/org/repo/pull/123",
+				Metadata: "pr_number=123",
+			},
+			wantErr: true,
+			errMsg:  "metadata: metadata must be valid JSON",
+		},
 	}
 
 	for _, tt := range tests {
@@ -79,12 +149,46 @@ func TestLink_Validate(t *testing.T) {
 				// Check title defaults to URL if empty
 				if tt.name == "valid link with minimal fields - title defaulted to URL" {
 					assert.Equal(t, tt.link.URL, tt.link.Title)
+					assert.Equal(t, "{}", tt.link.Metadata)
 				}
 			}
 		})
 	}
 }
 
+func TestLink_MetadataAccessors(t *testing.T) {
+	link := Link{}
+	assert.Empty(t, link.MetadataMap())
+
+	require.NoError(t, link.SetMetadata(map[string]any{"pr_number": 456, "author": "user"}))
+	assert.JSONEq(t, `{"pr_number": 456, "author": "user"}`, link.Metadata)
+
+	m := link.MetadataMap()
+	assert.Equal(t, "user", m["author"])
+	assert.Equal(t, float64(456), m["pr_number"])
+
+	require.NoError(t, link.SetMetadata(nil))
+	assert.Equal(t, "{}", link.Metadata)
+
+	link.Metadata = "[1, 2]"
+	assert.Empty(t, link.MetadataMap())
+}
+
+func TestLimits_AllowedURLSchemes(t *testing.T) {
+	link := Link{TaskID: "task-123", Type: Other, URL: "ftp://files.example.com/report.pdf"}
+	require.Error(t, link.Validate())
+
+	configure(t, Limits{AllowedURLSchemes: []string{" FTP ", "https"}})
+	assert.Equal(t, []string{"ftp", "https"}, AllowedURLSchemes())
+	require.NoError(t, link.Validate())
+
+	link.URL = "http://example.com"
+	require.Error(t, link.Validate())
+
+	configure(t, Limits{})
+	assert.Equal(t, DefaultAllowedURLSchemes, AllowedURLSchemes())
+}
+
 func TestLinkType_IsValid(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -95,6 +199,9 @@ func TestLinkType_IsValid(t *testing.T) {
 		{"valid slack_thread", SlackThread, true},
 		{"valid jira_ticket", JiraTicket, true},
 		{"valid documentation", Documentation, true},
+		{"valid commit", Commit, true},
+		{"valid design_doc", DesignDoc, true},
+		{"valid incident", Incident, true},
 		{"valid other", Other, true},
 		{"invalid empty", LinkType(""), false},
 		{"invalid random", LinkType("random"), false},
@@ -113,10 +220,13 @@ func TestLinkConstants(t *testing.T) {
 	assert.Equal(t, LinkType("slack_thread"), SlackThread)
 	assert.Equal(t, LinkType("jira_ticket"), JiraTicket)
 	assert.Equal(t, LinkType("documentation"), Documentation)
+	assert.Equal(t, LinkType("commit"), Commit)
+	assert.Equal(t, LinkType("design_doc"), DesignDoc)
+	assert.Equal(t, LinkType("incident"), Incident)
 	assert.Equal(t, LinkType("other"), Other)
 	
 	// Test that all constants are valid
-	validTypes := []LinkType{PullRequest, SlackThread, JiraTicket, Documentation, Other}
+	validTypes := []LinkType{PullRequest, SlackThread, JiraTicket, Documentation, Commit, DesignDoc, Incident, Other}
 	for _, linkType := range validTypes {
 		assert.True(t, linkType.IsValid(), "LinkType %s should be valid", linkType)
 	}
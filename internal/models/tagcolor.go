@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// normalizeTagColors checks colors that replace the generated color of
+// specific tags. Tags are normalized like Task.Validate does and colors must
+// be #rrggbb.
+func normalizeTagColors(colors map[string]string) (map[string]string, error) {
+	overrides := make(map[string]string, len(colors))
+	for tag, color := range colors {
+		normalized, err := normalizeTags([]string{tag})
+		if err != nil || len(normalized) == 0 {
+			return nil, fmt.Errorf("invalid tag_colors tag %q", tag)
+		}
+		color = strings.ToLower(strings.TrimSpace(color))
+		if !hexColorPattern.MatchString(color) {
+			return nil, fmt.Errorf("invalid tag_colors color %q for tag %q, expected #rrggbb", color, tag)
+		}
+		overrides[normalized[0]] = color
+	}
+	return overrides, nil
+}
+
+// TagColor returns the #rrggbb color a tag is shown with. Tags without a
+// configured color get one derived from a hash of the tag, so the same tag
+// always has the same color. Saturation and lightness are fixed so every
+// generated color is readable with white text.
+func TagColor(tag string) string {
+	if color, ok := active.tagColors[tag]; ok {
+		return color
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(tag))
+	hue := float64(h.Sum32() % 360)
+	return hslToHex(hue, 0.55, 0.42)
+}
+
+// hslToHex converts a hue in degrees with saturation and lightness in [0, 1]
+func hslToHex(hue, saturation, lightness float64) string {
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	m := lightness - chroma/2
+
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g, b = chroma, x, 0
+	case hue < 120:
+		r, g, b = x, chroma, 0
+	case hue < 180:
+		r, g, b = 0, chroma, x
+	case hue < 240:
+		r, g, b = 0, x, chroma
+	case hue < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	toByte := func(v float64) int { return int(math.Round((v + m) * 255)) }
+	return fmt.Sprintf("#%02x%02x%02x", toByte(r), toByte(g), toByte(b))
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagColor(t *testing.T) {
+	color := TagColor("kubernetes")
+	assert.Regexp(t, `^#[0-9a-f]{6}$`, color)
+	assert.Equal(t, color, TagColor("kubernetes"), "same tag, same color")
+	assert.NotEqual(t, TagColor("api"), TagColor("ui"))
+
+	configure(t, Limits{TagColors: map[string]string{"  Kubernetes ": "#326CE5"}})
+
+	assert.Equal(t, "#326ce5", TagColor("kubernetes"), "overrides take precedence")
+	assert.Equal(t, TagColor("api"), TagColor("api"), "other tags keep their generated color")
+
+	assert.Error(t, Configure(Limits{TagColors: map[string]string{"api": "blue"}}))
+	assert.Error(t, Configure(Limits{TagColors: map[string]string{"a,b": "#000000"}}))
+	assert.Equal(t, "#326ce5", TagColor("kubernetes"), "a rejected call leaves the overrides alone")
+
+	configure(t, Limits{})
+	assert.Equal(t, color, TagColor("kubernetes"))
+}
+
+func TestHSLToHex(t *testing.T) {
+	assert.Equal(t, "#ff0000", hslToHex(0, 1, 0.5))
+	assert.Equal(t, "#00ff00", hslToHex(120, 1, 0.5))
+	assert.Equal(t, "#0000ff", hslToHex(240, 1, 0.5))
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type Priority string
@@ -15,6 +19,9 @@ const (
 
 const DefaultPriority = Normal
 
+// DefaultPriorityOrder lists the priorities from most to least urgent
+var DefaultPriorityOrder = []Priority{Critical, High, Normal, Minor}
+
 type Status string
 
 const (
@@ -28,19 +35,133 @@ const (
 const (
 	DefaultStatus  = New
 	DefaultNoJira = "NO-JIRA"
+
+	DefaultMaxTitleLen = 500 // Characters allowed in a task title unless configured otherwise
 )
 
+// statusTransitions lists the statuses each status may move to when
+// transitions are enforced. Staying on the same status is always allowed.
+var statusTransitions = map[Status][]Status{
+	New:        {InProgress, Blocked, Done, Archived},
+	InProgress: {New, Blocked, Done, Archived},
+	Blocked:    {New, InProgress, Archived},
+	Done:       {InProgress, Archived},
+	Archived:   {New},
+}
+
+// EnforceTransitions reports whether status transitions are being checked
+func EnforceTransitions() bool {
+	return active.enforceTransitions
+}
+
+// checkNewTaskDefaults returns the priority and status Task.Validate fills in
+// when a task has none. Empty values stand for DefaultPriority and
+// DefaultStatus. New tasks can't default to archived, nor to blocked since
+// they have no blockers yet.
+func checkNewTaskDefaults(priority Priority, status Status) (Priority, Status, error) {
+	if priority == "" {
+		priority = DefaultPriority
+	}
+	if status == "" {
+		status = DefaultStatus
+	}
+	if !priority.IsValid() {
+		return "", "", fmt.Errorf("invalid default_priority %q", priority)
+	}
+	if !status.IsValid() || status == Archived || status == Blocked {
+		return "", "", fmt.Errorf("invalid default_status %q", status)
+	}
+	return priority, status, nil
+}
+
+// checkPriorityOrder returns the order Priority.Order sorts by, from most to
+// least urgent. It must list every priority exactly once; an empty order
+// stands for DefaultPriorityOrder.
+func checkPriorityOrder(order []Priority) ([]Priority, error) {
+	if len(order) == 0 {
+		return DefaultPriorityOrder, nil
+	}
+	if len(order) != len(DefaultPriorityOrder) {
+		return nil, fmt.Errorf("priority order must list all of %v", DefaultPriorityOrder)
+	}
+	seen := make(map[Priority]bool, len(order))
+	for _, priority := range order {
+		if !priority.IsValid() {
+			return nil, fmt.Errorf("invalid priority %q in priority order", priority)
+		}
+		if seen[priority] {
+			return nil, fmt.Errorf("priority %q is listed twice in priority order", priority)
+		}
+		seen[priority] = true
+	}
+	return append([]Priority(nil), order...), nil
+}
+
+// PriorityOrder returns the priorities from most to least urgent
+func PriorityOrder() []Priority {
+	return append([]Priority(nil), active.priorityOrder...)
+}
+
+// PriorityAt is the reverse of Priority.Order: it returns the priority sorted
+// at position order, and false when no priority is
+func PriorityAt(order int) (Priority, bool) {
+	if order < 0 || order >= len(active.priorityOrder) {
+		return "", false
+	}
+	return active.priorityOrder[order], true
+}
+
+// NewTaskPriority returns the priority given to tasks created without one
+func NewTaskPriority() Priority {
+	return active.newTaskPriority
+}
+
+// NewTaskStatus returns the status given to tasks created without one
+func NewTaskStatus() Status {
+	return active.newTaskStatus
+}
+
+// MaxTitleLen returns the longest title Task.Validate accepts, in characters
+func MaxTitleLen() int {
+	return active.maxTitleLen
+}
+
+// NoJiraID returns the placeholder Jira ID for tasks without a ticket
+func NoJiraID() string {
+	return active.noJiraID
+}
+
+// IsNoJira reports whether id is empty or the no-ticket placeholder
+func IsNoJira(id string) bool {
+	return id == "" || id == active.noJiraID
+}
+
+// compileJiraIDPattern returns the regexp Jira IDs other than the placeholder
+// must match in full. An empty pattern accepts any ID and compiles to nil.
+func compileJiraIDPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid jira_id_pattern: %w", err)
+	}
+	return re, nil
+}
+
 // Task represents a work item in the system
 type Task struct {
-	ID        string    `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440000"`                                   // Unique identifier
-	JiraID    string    `json:"jira_id" db:"jira_id" example:"OCPBUGS-1234"`                                                 // Jira ticket ID or NO-JIRA
-	Title     string    `json:"title" db:"title" example:"Fix memory leak in pod controller"`                               // Task title
-	Priority  Priority  `json:"priority" db:"priority" example:"high"`                                                      // Task priority
-	Status    Status    `json:"status" db:"status" example:"in_progress"`                                                   // Current status
-	Tags      []string  `json:"tags" db:"tags" example:"k8s,memory"`                                                        // Associated tags
-	Blockers  []string  `json:"blockers" db:"blockers" example:"Waiting for review from @team-lead"`                       // Blocking issues
-	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`                                // Creation timestamp
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2024-01-15T14:20:00Z"`                                // Last update timestamp
+	ID           string            `json:"id" db:"id" example:"550e8400-e29b-41d4-a716-446655440000"`           // Unique identifier
+	JiraID       string            `json:"jira_id" db:"jira_id" example:"OCPBUGS-1234"`                         // Jira ticket ID or NO-JIRA
+	Title        string            `json:"title" db:"title" example:"Fix memory leak in pod controller"`        // Task title
+	Priority     Priority          `json:"priority" db:"priority" example:"high"`                               // Task priority
+	Status       Status            `json:"status" db:"status" example:"in_progress"`                            // Current status
+	Tags         []string          `json:"tags" db:"tags" example:"k8s,memory"`                                 // Associated tags
+	Blockers     []string          `json:"blockers" db:"blockers" example:"Waiting for review from @team-lead"` // Blocking issues
+	Starred      bool              `json:"starred" db:"starred" example:"false"`                                // Pinned to the top of task lists
+	CustomFields map[string]string `json:"custom_fields,omitempty" db:"custom_fields"`                          // Free-form key/value data such as sprint or story points
+	CreatedAt    time.Time         `json:"created_at" db:"created_at" example:"2024-01-15T10:30:00Z"`           // Creation timestamp
+	UpdatedAt    time.Time         `json:"updated_at" db:"updated_at" example:"2024-01-15T14:20:00Z"`           // Last update timestamp
 }
 
 func (p Priority) IsValid() bool {
@@ -51,6 +172,17 @@ func (p Priority) IsValid() bool {
 	return false
 }
 
+// Order is the position of p when sorting from most to least urgent, 0 being
+// the most urgent. Unknown priorities sort after all the others.
+func (p Priority) Order() int {
+	for i, priority := range active.priorityOrder {
+		if priority == p {
+			return i
+		}
+	}
+	return len(active.priorityOrder)
+}
+
 func (s Status) IsValid() bool {
 	switch s {
 	case New, InProgress, Blocked, Done, Archived:
@@ -59,35 +191,150 @@ func (s Status) IsValid() bool {
 	return false
 }
 
+// CanTransitionTo reports whether a task may move from s to next
+func (s Status) CanTransitionTo(next Status) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns a ValidationError when transitions are enforced
+// and a task may not move from one status to the other
+func ValidateTransition(from, to Status) error {
+	if !active.enforceTransitions || from.CanTransitionTo(to) {
+		return nil
+	}
+	return &ValidationError{Field: "status", Code: CodeInvalidTransition, Message: fmt.Sprintf("cannot change status from %s to %s", from, to)}
+}
+
 func (t *Task) Validate() error {
 	if t.Title == "" {
-		return &ValidationError{Field: "title", Message: "title is required"}
+		return &ValidationError{Field: "title", Code: CodeRequired, Message: "title is required"}
+	}
+	if utf8.RuneCountInString(t.Title) > active.maxTitleLen {
+		return &ValidationError{Field: "title", Code: CodeTooLong, Message: fmt.Sprintf("title must be at most %d characters", active.maxTitleLen)}
 	}
 	
 	// Set defaults if empty
 	if t.JiraID == "" {
-		t.JiraID = DefaultNoJira
+		t.JiraID = active.noJiraID
 	}
 	if t.Priority == "" {
-		t.Priority = DefaultPriority
+		t.Priority = active.newTaskPriority
 	}
 	if t.Status == "" {
-		t.Status = DefaultStatus
+		t.Status = active.newTaskStatus
 	}
 	
 	// Validate after setting defaults
+	if active.jiraIDPattern != nil && !IsNoJira(t.JiraID) && !active.jiraIDPattern.MatchString(t.JiraID) {
+		return &ValidationError{Field: "jira_id", Code: CodeInvalidFormat, Message: fmt.Sprintf("jira_id %q does not match the expected format", t.JiraID)}
+	}
 	if !t.Priority.IsValid() {
-		return &ValidationError{Field: "priority", Message: "invalid priority"}
+		return &ValidationError{Field: "priority", Code: CodeInvalid, Message: "invalid priority"}
 	}
 	if !t.Status.IsValid() {
-		return &ValidationError{Field: "status", Message: "invalid status"}
+		return &ValidationError{Field: "status", Code: CodeInvalid, Message: "invalid status"}
+	}
+
+	tags, err := normalizeTags(t.Tags)
+	if err != nil {
+		return err
 	}
+	t.Tags = tags
+
+	fields, err := normalizeCustomFields(t.CustomFields)
+	if err != nil {
+		return err
+	}
+	t.CustomFields = fields
 	
 	return nil
 }
 
+// ValidateBlockedChange returns a ValidationError when the task moves to
+// blocked from another status without naming a blocker. A new task moves
+// from the empty status. Tasks that were already blocked are left alone, so
+// ones saved before the rule still update.
+func (t *Task) ValidateBlockedChange(from Status) error {
+	if t.Status != Blocked || from == Blocked || hasBlocker(t.Blockers) {
+		return nil
+	}
+	return &ValidationError{Field: "blockers", Code: CodeRequired, Message: "a blocked task needs at least one blocker"}
+}
+
+// hasBlocker reports whether any blocker has non-whitespace text
+func hasBlocker(blockers []string) bool {
+	for _, blocker := range blockers {
+		if strings.TrimSpace(blocker) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTags splits comma-separated tags, as typed into the web forms, and
+// normalizes them the same way Validate does
+func ParseTags(s string) []string {
+	// Splitting on commas leaves none behind, so normalizing can't fail
+	tags, _ := normalizeTags(strings.Split(s, ","))
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// NormalizeTag trims and lowercases a tag and collapses runs of inner
+// whitespace to one space, the form tags are stored and matched in
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// normalizeTags normalizes tags with NormalizeTag and drops empties and
+// duplicates while keeping the first occurrence's position. Commas are
+// rejected because tag filters are comma-separated.
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if strings.Contains(tag, ",") {
+			return nil, &ValidationError{Field: "tags", Code: CodeInvalidFormat, Message: "tags must not contain commas"}
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
+
+// Validation error codes tell clients why a field was rejected
+const (
+	CodeRequired          = "REQUIRED"
+	CodeTooLong           = "TOO_LONG"
+	CodeInvalid           = "INVALID"
+	CodeInvalidFormat     = "INVALID_FORMAT"
+	CodeNotAllowed        = "NOT_ALLOWED"
+	CodeInvalidTransition = "INVALID_TRANSITION"
+)
+
+// ValidationError reports an invalid field. Code is one of the Code*
+// constants and lets clients react without parsing Message
 type ValidationError struct {
 	Field   string
+	Code    string
 	Message string
 }
 
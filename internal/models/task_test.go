@@ -1,6 +1,8 @@
 package models
 
 import (
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -60,6 +62,15 @@ func TestTask_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "status: invalid status",
 		},
+		{
+			name: "tag containing a comma",
+			task: Task{
+				Title: "Test task",
+				Tags:  []string{"frontend", "k8s,memory"},
+			},
+			wantErr: true,
+			errMsg:  "tags: tags must not contain commas",
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,6 +107,192 @@ func TestTask_ValidateDefaults(t *testing.T) {
 	assert.Equal(t, DefaultStatus, task.Status)
 }
 
+func TestTask_ValidateNormalizesTags(t *testing.T) {
+	task := Task{
+		Title: "Test task",
+		Tags:  []string{" Frontend ", "frontend", ""},
+	}
+
+	require.NoError(t, task.Validate())
+	assert.Equal(t, []string{"frontend"}, task.Tags)
+
+	task.Tags = []string{"K8s", "api", " k8s", "API", "memory"}
+	require.NoError(t, task.Validate())
+	assert.Equal(t, []string{"k8s", "api", "memory"}, task.Tags)
+}
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"a, b ,,c", []string{"a", "b", "c"}},
+		{"a,b,", []string{"a", "b"}},
+		{" Needs   Review ,needs review", []string{"needs review"}},
+		{"", nil},
+		{" , ,", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseTags(tt.input))
+		})
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	assert.Equal(t, "needs review", NormalizeTag("  Needs \t  REVIEW "))
+	assert.Equal(t, "", NormalizeTag("   "))
+	assert.Equal(t, "a,b", NormalizeTag("A,B"), "commas are left for callers to reject")
+}
+
+func TestTask_ValidateBlockedChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     Status
+		status   Status
+		blockers []string
+		wantErr  bool
+	}{
+		{name: "blocked with a reason", from: InProgress, status: Blocked, blockers: []string{"Waiting for review"}},
+		{name: "blocked without blockers", from: InProgress, status: Blocked, wantErr: true},
+		{name: "blocked with only blank blockers", from: New, status: Blocked, blockers: []string{"", "  "}, wantErr: true},
+		{name: "already blocked without blockers", from: Blocked, status: Blocked},
+		{name: "in progress without blockers", from: New, status: InProgress},
+		{name: "done without blockers", from: Blocked, status: Done},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := Task{Title: "Test task", Status: tt.status, Blockers: tt.blockers}
+			require.NoError(t, task.Validate(), "Validate leaves blockers to the status change check")
+			err := task.ValidateBlockedChange(tt.from)
+
+			if tt.wantErr {
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, "blockers", validationErr.Field)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTask_ValidateJiraIDPattern(t *testing.T) {
+	configure(t, Limits{JiraIDPattern: `[A-Z]+-[0-9]+`})
+
+	task := Task{Title: "Test task", JiraID: "PROJ-1234"}
+	require.NoError(t, task.Validate(), "matching ID passes")
+
+	task.JiraID = "proj 1234"
+	err := task.Validate()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr, "non-matching ID fails")
+	assert.Equal(t, "jira_id", validationErr.Field)
+
+	task.JiraID = "PROJ-1234 and more"
+	require.Error(t, task.Validate(), "the pattern must match the whole ID")
+
+	task.JiraID = DefaultNoJira
+	require.NoError(t, task.Validate(), "the sentinel always passes")
+
+	task.JiraID = ""
+	require.NoError(t, task.Validate())
+	assert.Equal(t, DefaultNoJira, task.JiraID)
+
+	assert.Error(t, Configure(Limits{JiraIDPattern: "("}))
+}
+
+func TestLimits_NoJiraID(t *testing.T) {
+	configure(t, Limits{NoJiraID: "NONE", JiraIDPattern: `PROJ-[0-9]+`})
+
+	task := Task{Title: "Test task"}
+	require.NoError(t, task.Validate())
+	assert.Equal(t, "NONE", task.JiraID)
+	assert.True(t, IsNoJira("NONE"))
+	assert.False(t, IsNoJira(DefaultNoJira))
+
+	configure(t, Limits{NoJiraID: "  "})
+	assert.Equal(t, DefaultNoJira, NoJiraID())
+}
+
+func TestTask_ValidateMaxTitleLen(t *testing.T) {
+	configure(t, Limits{MaxTitleLen: 10})
+
+	task := Task{Title: strings.Repeat("é", 10)}
+	require.NoError(t, task.Validate(), "exactly at the limit, counted in characters")
+
+	task = Task{Title: strings.Repeat("a", 11)}
+	err := task.Validate()
+	require.Error(t, err)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "title", validationErr.Field)
+
+	configure(t, Limits{})
+	assert.Equal(t, DefaultMaxTitleLen, MaxTitleLen())
+}
+
+func TestLimits_NewTaskDefaults(t *testing.T) {
+	configure(t, Limits{NewTaskPriority: High, NewTaskStatus: InProgress})
+
+	task := Task{Title: "Defaults"}
+	require.NoError(t, task.Validate())
+	assert.Equal(t, High, task.Priority)
+	assert.Equal(t, InProgress, task.Status)
+
+	task = Task{Title: "Explicit", Priority: Minor, Status: Done}
+	require.NoError(t, task.Validate())
+	assert.Equal(t, Minor, task.Priority, "explicit values are kept")
+	assert.Equal(t, Done, task.Status)
+
+	assert.Error(t, Configure(Limits{NewTaskPriority: "urgent"}))
+	assert.Error(t, Configure(Limits{NewTaskStatus: Archived}))
+	assert.Error(t, Configure(Limits{NewTaskStatus: Blocked}))
+	assert.Equal(t, High, NewTaskPriority(), "a rejected call leaves the defaults alone")
+
+	configure(t, Limits{})
+	assert.Equal(t, DefaultPriority, NewTaskPriority())
+	assert.Equal(t, DefaultStatus, NewTaskStatus())
+}
+
+func TestValidateTransition(t *testing.T) {
+	assert.NoError(t, ValidateTransition(Archived, InProgress), "not enforced by default")
+
+	configure(t, Limits{EnforceTransitions: true})
+
+	tests := []struct {
+		from, to Status
+		wantErr  bool
+	}{
+		{New, InProgress, false},
+		{InProgress, Done, false},
+		{Blocked, InProgress, false},
+		{Done, InProgress, false},
+		{Done, Archived, false},
+		{Archived, New, false},
+		{InProgress, InProgress, false},
+		{Archived, InProgress, true},
+		{Archived, Done, true},
+		{Blocked, Done, true},
+		{Done, New, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			err := ValidateTransition(tt.from, tt.to)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "status", validationErr.Field)
+		})
+	}
+}
+
 func TestPriority_IsValid(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -117,6 +314,65 @@ func TestPriority_IsValid(t *testing.T) {
 	}
 }
 
+func TestPriority_Order(t *testing.T) {
+	tests := []struct {
+		priority Priority
+		want     int
+	}{
+		{Critical, 0},
+		{High, 1},
+		{Normal, 2},
+		{Minor, 3},
+		{Priority("urgent"), 4},
+		{Priority(""), 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.priority), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.priority.Order())
+		})
+	}
+}
+
+func TestPriorityAt(t *testing.T) {
+	for _, priority := range PriorityOrder() {
+		got, ok := PriorityAt(priority.Order())
+		assert.True(t, ok)
+		assert.Equal(t, priority, got, "PriorityAt reverses Order")
+	}
+
+	_, ok := PriorityAt(-1)
+	assert.False(t, ok)
+	_, ok = PriorityAt(len(DefaultPriorityOrder))
+	assert.False(t, ok)
+}
+
+func TestPriority_OrderSort(t *testing.T) {
+	priorities := []Priority{Minor, "urgent", Critical, Normal, High}
+	sort.SliceStable(priorities, func(i, j int) bool {
+		return priorities[i].Order() < priorities[j].Order()
+	})
+	assert.Equal(t, []Priority{Critical, High, Normal, Minor, "urgent"}, priorities)
+}
+
+func TestLimits_PriorityOrder(t *testing.T) {
+	configure(t, Limits{PriorityOrder: []Priority{Minor, Normal, High, Critical}})
+
+	assert.Equal(t, 0, Minor.Order())
+	assert.Equal(t, 3, Critical.Order())
+	got, ok := PriorityAt(1)
+	assert.True(t, ok)
+	assert.Equal(t, Normal, got)
+
+	assert.Error(t, Configure(Limits{PriorityOrder: []Priority{Critical, High, Normal}}), "every priority must be listed")
+	assert.Error(t, Configure(Limits{PriorityOrder: []Priority{Critical, High, Normal, "urgent"}}))
+	assert.Error(t, Configure(Limits{PriorityOrder: []Priority{Critical, High, Normal, Normal}}))
+	assert.Equal(t, []Priority{Minor, Normal, High, Critical}, PriorityOrder(), "a rejected call leaves the order alone")
+
+	configure(t, Limits{})
+	assert.Equal(t, DefaultPriorityOrder, PriorityOrder())
+}
+
 func TestStatus_IsValid(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"michishirube/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_RateLimit(t *testing.T) {
+	srv := setupTestServer(t, &config.Config{Port: "8080", RateLimitRPS: 20, RateLimitBurst: 3})
+	handler := testHandler(t, srv)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	var limited int
+	for i := 0; i < 10; i++ {
+		w := get("/api/tasks")
+		if i < 3 {
+			assert.Equal(t, http.StatusOK, w.Code, "request %d is within the burst", i)
+		}
+		if w.Code == http.StatusTooManyRequests {
+			limited++
+			assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		}
+	}
+	assert.Greater(t, limited, 0, "hammering past the burst is rejected")
+
+	assert.Equal(t, http.StatusOK, get("/health").Code, "health probes are not limited")
+
+	// Another client has its own bucket
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.RemoteAddr = "192.0.2.2:1234"
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// At 20 requests per second a token is back after 50ms
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, get("/api/tasks").Code, "the bucket refills")
+}
+
+func TestServer_RateLimitDisabledByDefault(t *testing.T) {
+	srv := setupTestServer(t, &config.Config{Port: "8080"})
+	handler := testHandler(t, srv)
+
+	for i := 0; i < 50; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(2, 2)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		ok, _ := limiter.allow("client")
+		assert.True(t, ok)
+	}
+	ok, wait := limiter.allow("client")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = limiter.allow("client")
+	assert.True(t, ok, "one token refilled")
+
+	// Idle clients are forgotten once their bucket is full again
+	now = now.Add(2 * rateLimiterSweepInterval)
+	limiter.allow("other")
+	assert.NotContains(t, limiter.buckets, "client")
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		trustProxy bool
+		want       string
+	}{
+		{"remote address", "192.0.2.1:1234", "", false, "192.0.2.1"},
+		{"IPv6 remote address", "[2001:db8::1]:1234", "", false, "2001:db8::1"},
+		{"forwarded header ignored", "192.0.2.1:1234", "198.51.100.7", false, "192.0.2.1"},
+		{"trusted forwarded header", "192.0.2.1:1234", "198.51.100.7, 10.0.0.1", true, "198.51.100.7"},
+		{"trusted proxy without header", "192.0.2.1:1234", "", true, "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			assert.Equal(t, tt.want, clientIP(req, tt.trustProxy))
+		})
+	}
+}
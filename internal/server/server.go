@@ -1,18 +1,27 @@
 package server
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
-	"os"
 	"os/signal"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"michishirube/internal/config"
+	"michishirube/internal/events"
 	"michishirube/internal/handlers"
 	"michishirube/internal/logger"
+	"michishirube/internal/models"
 	"michishirube/internal/storage"
 	
 	_ "michishirube/docs" // Import generated docs
@@ -23,30 +32,117 @@ type Server struct {
 	storage    storage.Storage
 	httpServer *http.Server
 	logger     *slog.Logger
+	build      handlers.BuildInfo
+	events     *events.Broker // Nil unless events are enabled
+	inFlight   atomic.Int64   // Requests currently being served
 }
 
-func New(config *config.Config, storage storage.Storage, logger *slog.Logger) *Server {
-	return &Server{
+// defaultShutdownTimeout applies when the configuration sets no shutdown_timeout
+const defaultShutdownTimeout = 30 * time.Second
+
+func New(config *config.Config, storage storage.Storage, logger *slog.Logger, build handlers.BuildInfo) *Server {
+	s := &Server{
 		config:  config,
 		storage: storage,
 		logger:  logger,
+		build:   build,
+	}
+	if config.EventsEnabled {
+		// Every handler writes through the wrapper, so all changes are published
+		s.events = events.NewBroker()
+		s.storage = events.NewStorage(storage, s.events)
 	}
+	return s
 }
 
+// Start listens on the configured address and port and serves until SIGINT/SIGTERM
 func (s *Server) Start() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// listen opens the configured bind address and port
+func (s *Server) listen() (net.Listener, error) {
+	return net.Listen("tcp", s.config.ListenAddr())
+}
+
+// Handler builds the application's routes wrapped in middleware. It fails if
+// the web templates are broken.
+func (s *Server) Handler() (http.Handler, error) {
 	// Initialize handlers
-	taskHandler := handlers.NewTaskHandler(s.storage)
-	webHandler := handlers.NewWebHandler(s.storage)
+	taskHandler := handlers.NewTaskHandler(s.storage,
+		handlers.WithPageSizes(s.config.DefaultPageSize, s.config.MaxPageSize),
+		handlers.WithMaxBodyBytes(s.config.MaxBodyBytes),
+		handlers.WithStrictJSON(s.config.StrictJSON),
+	)
+	healthHandler := handlers.NewHealthHandler(s.storage, s.build)
+	adminHandler := handlers.NewAdminHandler(s.storage)
 
 	// Setup routes with middleware
 	mux := http.NewServeMux()
 
+	mux.HandleFunc("/health", healthHandler.HealthCheck)
+	mux.HandleFunc("/ready", healthHandler.Ready)
+
+	// The web UI parses templates from disk, so API-only mode never builds it
+	if !s.config.APIOnly {
+		if err := s.registerWebRoutes(mux); err != nil {
+			return nil, err
+		}
+	}
+
+	// API routes (for AJAX calls from frontend)
+	mux.HandleFunc("/api/tasks", taskHandler.HandleTasks)
+	mux.HandleFunc("/api/tasks/", taskHandler.HandleTask)
+	mux.HandleFunc("/api/tasks/merge", taskHandler.HandleMerge)
+	mux.HandleFunc("/api/tasks/ensure", taskHandler.HandleEnsure)
+	mux.HandleFunc("/api/tasks/validate", taskHandler.HandleValidate)
+	mux.HandleFunc("/api/tasks/grouped", taskHandler.HandleGrouped)
+	mux.HandleFunc("/api/links", taskHandler.HandleLinks)
+	mux.HandleFunc("/api/links/", taskHandler.HandleLink)
+	mux.HandleFunc("/api/comments", taskHandler.HandleComments)
+	mux.HandleFunc("/api/comments/", taskHandler.HandleComment)
+	mux.HandleFunc("/api/tags", taskHandler.HandleTags)
+	mux.HandleFunc("/api/tags/rename", taskHandler.HandleRenameTag)
+	mux.HandleFunc("/api/suggest", taskHandler.HandleSuggest)
+	mux.HandleFunc("/api/report", taskHandler.HandleReport)
+	mux.HandleFunc("/api/report.md", taskHandler.HandleReportMarkdown)
+	mux.HandleFunc("/api/export", taskHandler.HandleExport)
+	mux.HandleFunc("/api/import", taskHandler.HandleImport)
+	mux.HandleFunc("/api/admin/checkpoint", adminHandler.HandleCheckpoint)
+	mux.HandleFunc("/api/admin/vacuum", adminHandler.HandleVacuum)
+	mux.HandleFunc("/api/admin/orphans", adminHandler.HandleOrphans)
+	mux.HandleFunc("/api/admin/orphans/purge", adminHandler.HandleOrphansPurge)
+	if s.events != nil {
+		mux.HandleFunc("/api/events", handlers.NewEventsHandler(s.events).HandleEvents)
+	}
+
+	// Apply middleware
+	return s.loggingMiddleware(s.rateLimitMiddleware(s.authMiddleware(gzipMiddleware(mux)))), nil
+}
+
+// registerWebRoutes adds the HTML pages, API documentation and static files
+func (s *Server) registerWebRoutes(mux *http.ServeMux) error {
+	webHandler, err := handlers.NewWebHandler(s.storage)
+	if err != nil {
+		return err
+	}
+
 	// Web routes (frontend)
 	mux.HandleFunc("/", webHandler.Dashboard)
+	mux.HandleFunc("/favicon.ico", webHandler.Favicon)
 	mux.HandleFunc("/task/", webHandler.TaskDetail)
 	mux.HandleFunc("/new", webHandler.NewTask)
-	mux.HandleFunc("/health", webHandler.HealthCheck)
-	
+	mux.HandleFunc("/board", webHandler.Board)
+	mux.HandleFunc("/toggle-theme", webHandler.ToggleTheme)
+
 	// API Documentation routes
 	mux.HandleFunc("/docs", webHandler.SwaggerUI)
 	mux.HandleFunc("/api-docs/", httpSwagger.Handler(
@@ -55,51 +151,78 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/swagger/doc.json", webHandler.SwaggerJSON)
 	mux.HandleFunc("/openapi.yaml", webHandler.OpenAPISpec)
 
-	// API routes (for AJAX calls from frontend)
-	mux.HandleFunc("/api/tasks", taskHandler.HandleTasks)
-	mux.HandleFunc("/api/tasks/", taskHandler.HandleTask)
-	mux.HandleFunc("/api/links", taskHandler.HandleLinks)
-	mux.HandleFunc("/api/links/", taskHandler.HandleLink)
-	mux.HandleFunc("/api/comments", taskHandler.HandleComments)
-	mux.HandleFunc("/api/comments/", taskHandler.HandleComment)
-	mux.HandleFunc("/api/report", taskHandler.HandleReport)
-
 	// Static files
 	mux.Handle("/static/", webHandler.StaticFileHandler())
+	return nil
+}
 
-	// Apply middleware
-	handler := s.loggingMiddleware(mux)
+// Serve accepts connections on listener until ctx is cancelled, then shuts down
+// gracefully. TLS is used when both a certificate and key are configured.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	handler, err := s.Handler()
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
 
 	// Configure HTTP server
 	s.httpServer = &http.Server{
-		Addr:         ":" + s.config.Port,
-		Handler:      handler,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  120 * time.Second,
+		Addr:         listener.Addr().String(),
+		Handler:      s.trackInFlight(handler),
+		ReadTimeout:  s.config.ReadTimeout,
+		WriteTimeout: s.config.WriteTimeout,
+		IdleTimeout:  s.config.IdleTimeout,
 	}
+	if s.events != nil {
+		// Shutdown doesn't interrupt active requests, so event streams have
+		// to be told to finish or they would hold it up until the timeout
+		s.httpServer.RegisterOnShutdown(s.events.Close)
+	}
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	s.startCheckpointer(jobsCtx)
+	s.startArchivePurger(jobsCtx)
+	s.startReportWriter(jobsCtx)
 
-	slog.Info("Starting HTTP server", "port", s.config.Port, "addr", s.httpServer.Addr)
+	useTLS := s.config.TLSEnabled()
+	slog.Info("Starting HTTP server", "port", s.config.Port, "addr", s.httpServer.Addr, "tls", useTLS)
 
 	// Start server in a goroutine
+	serveErr := make(chan error, 1)
 	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if useTLS {
+			err = s.httpServer.ServeTLS(listener, s.config.TLSCertFile, s.config.TLSKeyFile)
+		} else {
+			err = s.httpServer.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
 			slog.Error("HTTP server failed", "error", err)
+			serveErr <- err
 		}
 	}()
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	slog.Info("Shutting down server...")
+	// Wait for cancellation (e.g. interrupt signal) to gracefully shutdown the server
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	timeout := s.config.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	slog.Info("Shutting down server...", "in_flight", s.inFlight.Load(), "timeout", timeout)
 
-	// Graceful shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Stop accepting connections and let in-flight requests finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	if err := s.httpServer.Shutdown(ctx); err != nil {
-		slog.Error("Server forced to shutdown", "error", err)
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Server forced to shutdown", "error", err, "in_flight", s.inFlight.Load())
+		_ = s.httpServer.Close()
 		return err
 	}
 
@@ -107,6 +230,57 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// startCheckpointer periodically truncates the SQLite write-ahead log. It only
+// runs when the backend supports checkpoints and WAL mode is actually enabled.
+func (s *Server) startCheckpointer(ctx context.Context) {
+	interval := s.config.WALCheckpointInterval
+	checkpointer, ok := s.storage.(storage.Checkpointer)
+	if !ok || interval <= 0 {
+		return
+	}
+
+	enabled, err := checkpointer.WALEnabled(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to detect journal mode, periodic checkpoints disabled", "error", err)
+		return
+	}
+	if !enabled {
+		s.logger.Debug("WAL mode not enabled, periodic checkpoints disabled")
+		return
+	}
+
+	s.logger.Info("Starting periodic WAL checkpoints", "interval", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result, err := checkpointer.Checkpoint(ctx)
+				if err != nil {
+					s.logger.Error("Periodic WAL checkpoint failed", "error", err)
+					continue
+				}
+				s.logger.Info("Periodic WAL checkpoint completed",
+					"busy", result.Busy,
+					"log_pages", result.LogPages,
+					"checkpointed", result.Checkpointed)
+			}
+		}
+	}()
+}
+
+// trackInFlight counts the requests being served so shutdown can report them
+func (s *Server) trackInFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
 // loggingMiddleware logs HTTP requests
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -118,9 +292,27 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 		
 		// Create a custom ResponseWriter to capture status code
 		ww := &responseWriter{ResponseWriter: w}
+
+		// Bodies are only captured at debug level; they may hold user data
+		// and copying them costs memory on every request
+		var reqBody *bodyCapture
+		if s.logger.Enabled(ctx, slog.LevelDebug) {
+			reqBody = &bodyCapture{limit: debugBodyLogLimit}
+			r.Body = teeReadCloser{Reader: io.TeeReader(r.Body, reqBody), Closer: r.Body}
+			ww.body = &bodyCapture{limit: debugBodyLogLimit}
+		}
 		
 		// Call the next handler
 		next.ServeHTTP(ww, r)
+
+		if reqBody != nil {
+			s.logger.DebugContext(ctx, "HTTP bodies",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"request_body", reqBody.String(),
+				"response_body", ww.responseBody(),
+			)
+		}
 		
 		// Log the request using the configured logger
 		duration := time.Since(start)
@@ -135,10 +327,12 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// responseWriter wraps http.ResponseWriter to capture status code
+// responseWriter wraps http.ResponseWriter to capture status code, and the
+// start of the body when debug logging is enabled
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	body       *bodyCapture
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -150,5 +344,336 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	if rw.statusCode == 0 {
 		rw.statusCode = 200
 	}
+	if rw.body != nil {
+		_, _ = rw.body.Write(b)
+	}
 	return rw.ResponseWriter.Write(b)
 }
+
+// Flush passes through so streaming responses reach the client
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying connection
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// responseBody returns the captured body for logging. Compressed bodies are
+// not readable in a log line, so only their presence is noted.
+func (rw *responseWriter) responseBody() string {
+	if rw.Header().Get("Content-Encoding") != "" {
+		return "[" + rw.Header().Get("Content-Encoding") + " encoded]"
+	}
+	return rw.body.String()
+}
+
+// debugBodyLogLimit caps how much of each body is kept for debug logging
+const debugBodyLogLimit = 4096
+
+// bodyCapture keeps the first limit bytes written to it and discards the rest
+type bodyCapture struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (c *bodyCapture) Write(p []byte) (int, error) {
+	if room := c.limit - c.buf.Len(); room < len(p) {
+		c.truncated = true
+		c.buf.Write(p[:max(room, 0)])
+	} else {
+		c.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+func (c *bodyCapture) String() string {
+	if c.truncated {
+		return c.buf.String() + "...(truncated)"
+	}
+	return c.buf.String()
+}
+
+// teeReadCloser lets a request body be copied as the handler reads it while
+// Close still reaches the original body
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// authMiddleware requires a configured API key on mutating /api/ requests.
+// Reads stay open, and without configured keys every request passes.
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	if !s.config.AuthEnabled() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") || !isMutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !s.validAPIKey(requestAPIKey(r)) {
+			logger.FromContext(r.Context()).Warn("Rejected unauthenticated API request",
+				"method", r.Method, "path", r.URL.Path)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="michishirube"`)
+			writeUnauthorized(w)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeUnauthorized answers with the same JSON error body the API handlers use
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusUnauthorized)
+	_ = json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Unauthorized", Code: "UNAUTHORIZED"})
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// requestAPIKey reads the key from "Authorization: Bearer <key>" or X-API-Key
+func requestAPIKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, key, ok := strings.Cut(auth, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(key)
+		}
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+func (s *Server) validAPIKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	valid := false
+	for _, configured := range s.config.APIKeys {
+		// Compare every key in constant time so timing doesn't reveal a match
+		if subtle.ConstantTimeCompare([]byte(key), []byte(configured)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// gzipMinSize is the smallest response worth compressing; below it the gzip
+// framing costs more than it saves
+const gzipMinSize = 1024
+
+// gzipMiddleware compresses responses for clients that accept gzip. It sits
+// inside loggingMiddleware so the status capture still sees every response.
+func gzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer func() {
+			if err := gw.Close(); err != nil {
+				slog.Error("Failed to finish gzip response", "error", err)
+			}
+		}()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		encoding, _, _ = strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.EqualFold(encoding, "gzip") {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response until it knows whether
+// the body reaches gzipMinSize, then either compresses or passes it through
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	buf        bytes.Buffer
+	gz         *gzip.Writer
+	committed  bool // Headers have been sent, compressed or not
+}
+
+func (gw *gzipResponseWriter) WriteHeader(code int) {
+	if gw.committed || gw.statusCode != 0 {
+		return
+	}
+	gw.statusCode = code
+}
+
+func (gw *gzipResponseWriter) Write(b []byte) (int, error) {
+	if gw.gz != nil {
+		return gw.gz.Write(b)
+	}
+	if gw.committed {
+		return gw.ResponseWriter.Write(b)
+	}
+
+	gw.buf.Write(b)
+	if gw.buf.Len() < gzipMinSize {
+		return len(b), nil
+	}
+	if err := gw.startGzip(); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Flush sends whatever is buffered so streaming responses are not held back
+func (gw *gzipResponseWriter) Flush() {
+	if gw.gz != nil {
+		_ = gw.gz.Flush()
+	} else if !gw.committed {
+		_ = gw.passThrough()
+	}
+	if f, ok := gw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying connection
+func (gw *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return gw.ResponseWriter
+}
+
+// Close writes out a response that never reached the threshold, or finishes the gzip stream
+func (gw *gzipResponseWriter) Close() error {
+	if gw.gz != nil {
+		return gw.gz.Close()
+	}
+	if !gw.committed {
+		return gw.passThrough()
+	}
+	return nil
+}
+
+func (gw *gzipResponseWriter) startGzip() error {
+	header := gw.Header()
+	if header.Get("Content-Encoding") != "" {
+		// Already encoded by the handler, don't double-compress
+		return gw.passThrough()
+	}
+	if header.Get("Content-Type") == "" {
+		// Sniff from the plain bytes before they are compressed
+		header.Set("Content-Type", http.DetectContentType(gw.buf.Bytes()))
+	}
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+
+	gw.writeHeader()
+	gw.gz = gzip.NewWriter(gw.ResponseWriter)
+	_, err := gw.gz.Write(gw.buf.Bytes())
+	gw.buf.Reset()
+	return err
+}
+
+func (gw *gzipResponseWriter) passThrough() error {
+	gw.writeHeader()
+	if gw.buf.Len() == 0 {
+		return nil
+	}
+	_, err := gw.ResponseWriter.Write(gw.buf.Bytes())
+	gw.buf.Reset()
+	return err
+}
+
+func (gw *gzipResponseWriter) writeHeader() {
+	gw.committed = true
+	if gw.statusCode == 0 {
+		gw.statusCode = http.StatusOK
+	}
+	gw.ResponseWriter.WriteHeader(gw.statusCode)
+}
+
+// archivePurgeInterval is how often archived tasks past their retention are purged
+const archivePurgeInterval = 24 * time.Hour
+
+// startArchivePurger deletes archived tasks older than the configured
+// retention once at startup and then daily. Disabled when retention is 0.
+func (s *Server) startArchivePurger(ctx context.Context) {
+	days := s.config.ArchiveRetentionDays
+	if days <= 0 {
+		return
+	}
+	retention := time.Duration(days) * 24 * time.Hour
+
+	purge := func() {
+		cutoff := time.Now().Add(-retention)
+		purged, err := s.storage.PurgeArchived(ctx, cutoff)
+		if err != nil {
+			s.logger.Error("Archived task purge failed", "error", err)
+			return
+		}
+		s.logger.Info("Purged archived tasks", "purged", purged, "cutoff", cutoff)
+	}
+
+	s.logger.Info("Starting archived task purge", "retention_days", days, "interval", archivePurgeInterval)
+	go func() {
+		purge()
+		ticker := time.NewTicker(archivePurgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purge()
+			}
+		}
+	}()
+}
+
+// startReportWriter writes the Markdown status report to report_output_path
+// once at startup and then on report_schedule. Disabled without a path.
+func (s *Server) startReportWriter(ctx context.Context) {
+	if !s.config.ReportEnabled() {
+		return
+	}
+	path := s.config.ReportOutputPath
+	reports := handlers.NewReportService(s.storage)
+
+	write := func() {
+		if err := reports.WriteFile(ctx, path); err != nil {
+			s.logger.Error("Scheduled report failed", "error", err, "path", path)
+			return
+		}
+		s.logger.Info("Wrote status report", "path", path)
+	}
+
+	s.logger.Info("Starting scheduled status report", "path", path, "schedule", s.config.ReportSchedule)
+	go func() {
+		write()
+		for {
+			timer := time.NewTimer(time.Until(s.config.NextReportAt(time.Now())))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				write()
+			}
+		}
+	}()
+}
@@ -0,0 +1,574 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"michishirube/internal/config"
+	"michishirube/internal/handlers"
+	"michishirube/internal/logger"
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+	"michishirube/internal/storage/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestServer creates a server backed by a temporary SQLite database. The
+// working directory is switched to the repository root so templates resolve.
+func setupTestServer(t *testing.T, cfg *config.Config) *Server {
+	t.Helper()
+	t.Chdir(filepath.Join("..", ".."))
+
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "server_test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Logf("failed to close storage: %v", err)
+		}
+	})
+
+	return New(cfg, store, logger.NewLogger(slog.LevelError), handlers.BuildInfo{Version: "test", Commit: "deadbeef"})
+}
+
+// testHandler builds the server's handler, failing the test if it can't
+func testHandler(t *testing.T, srv *Server) http.Handler {
+	t.Helper()
+	handler, err := srv.Handler()
+	require.NoError(t, err)
+	return handler
+}
+
+// runServer serves on a random local port until the test ends and returns the address
+func runServer(t *testing.T, srv *Server) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx, listener)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Error("server did not shut down")
+		}
+	})
+
+	return listener.Addr().String()
+}
+
+// writeSelfSignedCert generates a certificate for 127.0.0.1 and returns the
+// cert/key paths plus the parsed certificate for client trust
+func writeSelfSignedCert(t *testing.T) (string, string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{Organization: []string{"Michishirube Test"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certFile, keyFile, cert
+}
+
+func TestServer_ServeTLS(t *testing.T) {
+	certFile, keyFile, cert := writeSelfSignedCert(t)
+
+	srv := setupTestServer(t, &config.Config{
+		Port:        "8443",
+		TLSCertFile: certFile,
+		TLSKeyFile:  keyFile,
+	})
+	addr := runServer(t, srv)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	client := &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+	}
+
+	resp, err := client.Get("https://" + addr + "/health")
+	require.NoError(t, err)
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			t.Logf("failed to close body: %v", err)
+		}
+	}()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotNil(t, resp.TLS)
+	assert.True(t, resp.TLS.HandshakeComplete)
+}
+
+func TestServer_ListenBindAddress(t *testing.T) {
+	// Port 0 picks a free port; the host comes from bind_address
+	srv := New(&config.Config{BindAddress: "127.0.0.1", Port: "0"}, nil, logger.NewLogger(slog.LevelError), handlers.BuildInfo{})
+
+	listener, err := srv.listen()
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+	assert.NotEqual(t, "0", port)
+}
+
+func TestServer_ServePlainHTTP(t *testing.T) {
+	srv := setupTestServer(t, &config.Config{Port: "8080"})
+	addr := runServer(t, srv)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get("http://" + addr + "/health")
+	require.NoError(t, err)
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			t.Logf("failed to close body: %v", err)
+		}
+	}()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, resp.TLS)
+
+	var health map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	assert.Equal(t, "test", health["version"])
+	assert.Equal(t, "deadbeef", health["commit"])
+	assert.NotNil(t, health["schema_version"])
+	assert.Equal(t, health["expected_schema_version"], health["schema_version"])
+}
+
+func TestServer_GzipCompression(t *testing.T) {
+	srv := setupTestServer(t, &config.Config{Port: "8080", DefaultPageSize: 50, MaxPageSize: 200})
+	for i := 0; i < 20; i++ {
+		require.NoError(t, srv.storage.CreateTask(context.Background(), &models.Task{
+			JiraID:   "NO-JIRA",
+			Title:    fmt.Sprintf("Compressible task number %d", i),
+			Priority: models.Normal,
+			Status:   models.New,
+			Tags:     []string{"gzip", "test"},
+		}))
+	}
+	handler := testHandler(t, srv)
+
+	plain := httptest.NewRecorder()
+	handler.ServeHTTP(plain, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+	require.Equal(t, http.StatusOK, plain.Code)
+	assert.Empty(t, plain.Header().Get("Content-Encoding"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	compressed := httptest.NewRecorder()
+	handler.ServeHTTP(compressed, req)
+
+	require.Equal(t, http.StatusOK, compressed.Code)
+	assert.Equal(t, "gzip", compressed.Header().Get("Content-Encoding"))
+	assert.Equal(t, "application/json", compressed.Header().Get("Content-Type"))
+	assert.Contains(t, compressed.Header().Values("Vary"), "Accept-Encoding")
+	assert.Less(t, compressed.Body.Len(), plain.Body.Len())
+
+	reader, err := gzip.NewReader(compressed.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	var response models.TaskListResponse
+	require.NoError(t, json.Unmarshal(body, &response))
+	assert.Len(t, response.Tasks, 20)
+	assert.JSONEq(t, plain.Body.String(), string(body))
+}
+
+func TestServer_GzipSkipsSmallResponses(t *testing.T) {
+	srv := setupTestServer(t, &config.Config{Port: "8080"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tags", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	testHandler(t, srv).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, `{}`, w.Body.String())
+}
+
+func TestServer_AuthMiddleware(t *testing.T) {
+	srv := setupTestServer(t, &config.Config{Port: "8080", APIKeys: []string{"secret-key"}})
+	handler := testHandler(t, srv)
+
+	newTaskRequest := func() *http.Request {
+		body := `{"jira_id":"NO-JIRA","title":"Authenticated task","priority":"normal"}`
+		return httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body))
+	}
+
+	tests := []struct {
+		name           string
+		request        func() *http.Request
+		expectedStatus int
+	}{
+		{
+			name:           "missing key",
+			request:        newTaskRequest,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong key",
+			request: func() *http.Request {
+				req := newTaskRequest()
+				req.Header.Set("Authorization", "Bearer wrong-key")
+				return req
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "bearer token",
+			request: func() *http.Request {
+				req := newTaskRequest()
+				req.Header.Set("Authorization", "Bearer secret-key")
+				return req
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "X-API-Key header",
+			request: func() *http.Request {
+				req := newTaskRequest()
+				req.Header.Set("X-API-Key", "secret-key")
+				return req
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "reads stay open",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tt.request())
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				assert.JSONEq(t, `{"error":"Unauthorized","code":"UNAUTHORIZED"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_AuthDisabledWithoutKeys(t *testing.T) {
+	srv := setupTestServer(t, &config.Config{Port: "8080"})
+
+	body := `{"jira_id":"NO-JIRA","title":"Open task","priority":"normal"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	testHandler(t, srv).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestServer_APIOnlyWithoutTemplates(t *testing.T) {
+	// An empty working directory has no web/templates to parse
+	t.Chdir(t.TempDir())
+
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "server_test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Logf("failed to close storage: %v", err)
+		}
+	})
+
+	srv := New(&config.Config{Port: "8080", APIOnly: true}, store, logger.NewLogger(slog.LevelError), handlers.BuildInfo{})
+	handler := testHandler(t, srv)
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{"/api/tasks", http.StatusOK},
+		{"/health", http.StatusOK},
+		{"/ready", http.StatusOK},
+		{"/", http.StatusNotFound},
+		{"/board", http.StatusNotFound},
+		{"/static/css/style.css", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestServer_DebugBodyLogging(t *testing.T) {
+	tests := []struct {
+		name       string
+		level      slog.Level
+		wantBodies bool
+	}{
+		{name: "debug level logs bodies", level: slog.LevelDebug, wantBodies: true},
+		{name: "info level omits bodies", level: slog.LevelInfo, wantBodies: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := setupTestServer(t, &config.Config{Port: "8080"})
+
+			var logs strings.Builder
+			srv.logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: tt.level}))
+
+			body := `{"title":"Debug body logging","jira_id":"LOG-1","priority":"normal","status":"new"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			testHandler(t, srv).ServeHTTP(w, req)
+
+			// The handler still saw the full body despite the tee
+			require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+			output := logs.String()
+			assert.Contains(t, output, "HTTP request")
+			if tt.wantBodies {
+				assert.Contains(t, output, "Debug body logging")
+				assert.Contains(t, output, "response_body=")
+				assert.Contains(t, output, "LOG-1")
+			} else {
+				assert.NotContains(t, output, "Debug body logging")
+				assert.NotContains(t, output, "request_body")
+			}
+		})
+	}
+}
+
+func TestBodyCapture_Truncates(t *testing.T) {
+	c := &bodyCapture{limit: 5}
+	n, err := c.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	n, err = c.Write([]byte("defgh"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	assert.Equal(t, "abcde...(truncated)", c.String())
+}
+
+func TestServer_EventStream(t *testing.T) {
+	srv := setupTestServer(t, &config.Config{Port: "8080", EventsEnabled: true, MaxBodyBytes: 1 << 20})
+	addr := runServer(t, srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/api/events", nil)
+	require.NoError(t, err)
+
+	// Headers only arrive once the subscription is registered
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		if err := stream.Body.Close(); err != nil {
+			t.Logf("failed to close body: %v", err)
+		}
+	}()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+	assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+
+	created, err := http.Post("http://"+addr+"/api/tasks", "application/json",
+		strings.NewReader(`{"title":"Streamed task","priority":"normal"}`))
+	require.NoError(t, err)
+	var task models.Task
+	require.NoError(t, json.NewDecoder(created.Body).Decode(&task))
+	require.NoError(t, created.Body.Close())
+	require.Equal(t, http.StatusCreated, created.StatusCode)
+
+	scanner := bufio.NewScanner(stream.Body)
+	var lines []string
+	for scanner.Scan() && scanner.Text() != "" {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, "id: 1", lines[0])
+	assert.Equal(t, "event: task.created", lines[1])
+	var event map[string]any
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &event))
+	assert.Equal(t, "task.created", event["type"])
+	assert.Equal(t, task.ID, event["task_id"])
+}
+
+func TestServer_EventStreamDisabledByDefault(t *testing.T) {
+	// API-only so the web UI's catch-all route doesn't answer instead
+	srv := setupTestServer(t, &config.Config{Port: "8080", APIOnly: true})
+
+	w := httptest.NewRecorder()
+	testHandler(t, srv).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/events", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// slowListStorage holds ListTasks until released, to keep a request in flight
+type slowListStorage struct {
+	storage.Storage
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowListStorage) ListTasks(ctx context.Context, filters storage.TaskFilters) ([]*models.Task, error) {
+	close(s.started)
+	<-s.release
+	return s.Storage.ListTasks(ctx, filters)
+}
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	base := setupTestServer(t, &config.Config{Port: "8080", APIOnly: true, EventsEnabled: true, ShutdownTimeout: 5 * time.Second})
+	slow := &slowListStorage{Storage: base.storage, started: make(chan struct{}), release: make(chan struct{})}
+	srv := New(base.config, slow, base.logger, base.build)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx, listener)
+	}()
+
+	// An open event stream must not hold up the shutdown
+	stream, err := http.Get("http://" + addr + "/api/events")
+	require.NoError(t, err)
+	defer func() {
+		if err := stream.Body.Close(); err != nil {
+			t.Logf("failed to close body: %v", err)
+		}
+	}()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	type result struct {
+		status int
+		err    error
+	}
+	slowResult := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/api/tasks")
+		if err != nil {
+			slowResult <- result{err: err}
+			return
+		}
+		_ = resp.Body.Close()
+		slowResult <- result{status: resp.StatusCode}
+	}()
+	<-slow.started
+
+	cancel()
+
+	// New connections are refused once the listener is closed
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return true
+		}
+		_ = conn.Close()
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_, err = io.ReadAll(stream.Body)
+	assert.NoError(t, err, "the event stream ends cleanly")
+
+	close(slow.release)
+	select {
+	case res := <-slowResult:
+		require.NoError(t, res.err)
+		assert.Equal(t, http.StatusOK, res.status, "in-flight request completes")
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow request did not complete")
+	}
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down within the grace period")
+	}
+}
+
+func TestServer_ReportWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.md")
+	srv := setupTestServer(t, &config.Config{Port: "8080", ReportOutputPath: path, ReportSchedule: "08:00"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv.startReportWriter(ctx)
+
+	// The report is written once at startup, before the first scheduled run
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "## Working on")
+}
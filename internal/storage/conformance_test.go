@@ -0,0 +1,893 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+	"michishirube/internal/storage/memory"
+	"michishirube/internal/storage/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backends returns a fresh, empty store for every implementation under test
+func backends() map[string]func(t *testing.T) storage.Storage {
+	return map[string]func(t *testing.T) storage.Storage{
+		"memory": func(t *testing.T) storage.Storage {
+			return memory.New()
+		},
+		"sqlite": func(t *testing.T) storage.Storage {
+			store, err := sqlite.New(filepath.Join(t.TempDir(), "conformance.db"))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = store.Close() })
+			return store
+		},
+	}
+}
+
+// TestConformance runs the same assertions against every backend so they stay
+// interchangeable behind the storage.Storage interface
+func TestConformance(t *testing.T) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, s storage.Storage)
+	}{
+		{"TaskCRUD", testTaskCRUD},
+		{"NotFound", testNotFound},
+		{"GetTasks", testGetTasks},
+		{"ListTasksFilters", testListTasksFilters},
+		{"ListTasksPaging", testListTasksPaging},
+		{"StarredTasks", testStarredTasks},
+		{"CustomFields", testCustomFields},
+		{"StreamTasks", testStreamTasks},
+		{"SearchTasks", testSearchTasks},
+		{"RelatedTasks", testRelatedTasks},
+		{"LinksAndComments", testLinksAndComments},
+		{"CreateComments", testCreateComments},
+		{"StreamLinksAndComments", testStreamLinksAndComments},
+		{"CountTaskRelations", testCountTaskRelations},
+		{"Tags", testTags},
+		{"DistinctValues", testDistinctValues},
+		{"MergeTasks", testMergeTasks},
+		{"PurgeArchived", testPurgeArchived},
+		{"Activity", testActivity},
+		{"ImportData", testImportData},
+		{"WithTransaction", testWithTransaction},
+		{"StatusTransitions", testStatusTransitions},
+		{"BlockedNeedsBlocker", testBlockedNeedsBlocker},
+	}
+
+	for backend, open := range backends() {
+		t.Run(backend, func(t *testing.T) {
+			for _, tc := range cases {
+				t.Run(tc.name, func(t *testing.T) {
+					tc.run(t, open(t))
+				})
+			}
+		})
+	}
+}
+
+// createTask stores a task and pauses briefly so creation times are distinct
+func createTask(t *testing.T, s storage.Storage, id, title string, status models.Status, tags ...string) *models.Task {
+	t.Helper()
+	task := &models.Task{
+		ID:       id,
+		JiraID:   "NO-JIRA",
+		Title:    title,
+		Priority: models.Normal,
+		Status:   status,
+		Tags:     tags,
+	}
+	require.NoError(t, s.CreateTask(context.Background(), task))
+	time.Sleep(2 * time.Millisecond)
+	return task
+}
+
+// importBlockedTask stores a blocked task without blockers the way an old
+// backup would bring it in; creating one directly is rejected
+func importBlockedTask(t *testing.T, s storage.Storage, id, title string) {
+	t.Helper()
+	result, err := s.ImportData(context.Background(), &models.ExportData{Tasks: []*models.Task{
+		{ID: id, JiraID: "NO-JIRA", Title: title, Priority: models.Normal, Status: models.Blocked},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.TasksImported)
+}
+
+func taskIDs(tasks []*models.Task) []string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func testTaskCRUD(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	task := &models.Task{JiraID: "OCPBUGS-1", Title: "Fix the thing", Tags: []string{"api"}}
+	require.NoError(t, s.CreateTask(ctx, task))
+	assert.NotEmpty(t, task.ID)
+	assert.Equal(t, models.DefaultStatus, task.Status)
+	assert.Equal(t, models.DefaultPriority, task.Priority)
+	assert.False(t, task.CreatedAt.IsZero())
+
+	got, err := s.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Fix the thing", got.Title)
+	assert.Equal(t, []string{"api"}, got.Tags)
+
+	byJira, err := s.GetTaskByJiraID(ctx, "OCPBUGS-1")
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, byJira.ID)
+
+	got.Title = "Fix the other thing"
+	got.Status = models.InProgress
+	require.NoError(t, s.UpdateTask(ctx, got))
+
+	updated, err := s.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Fix the other thing", updated.Title)
+	assert.Equal(t, models.InProgress, updated.Status)
+	assert.False(t, updated.UpdatedAt.Before(updated.CreatedAt))
+
+	invalid := &models.Task{JiraID: "NO-JIRA"}
+	var validationErr *models.ValidationError
+	assert.True(t, errors.As(s.CreateTask(ctx, invalid), &validationErr))
+
+	require.NoError(t, s.DeleteTask(ctx, task.ID))
+	_, err = s.GetTask(ctx, task.ID)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testNotFound(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	_, err := s.GetTask(ctx, "missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.GetTaskByJiraID(ctx, "MISSING-1")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.GetLink(ctx, "missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.GetComment(ctx, "missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testListTasksFilters(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "t1", "New api task", models.New, "api")
+	createTask(t, s, "t2", "Done ui task", models.Done, "ui")
+	createTask(t, s, "t3", "Archived task", models.Archived, "api")
+	createTask(t, s, "t4", "Progress task", models.InProgress, "api", "ui")
+
+	tasks, err := s.ListTasks(ctx, storage.TaskFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t4", "t2", "t1"}, taskIDs(tasks), "newest first, archived hidden")
+
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{IncludeArchived: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t4", "t3", "t2", "t1"}, taskIDs(tasks))
+
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{Status: []models.Status{models.New, models.Done}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t2", "t1"}, taskIDs(tasks))
+
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{Tags: []string{"ui"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t4", "t2"}, taskIDs(tasks))
+	count, err := s.CountTasks(ctx, storage.TaskFilters{Tags: []string{"ui"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{Priority: []models.Priority{models.Critical}})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	t2, err := s.GetTask(ctx, "t2")
+	require.NoError(t, err)
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{CreatedAfter: t2.CreatedAt, CreatedBefore: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t4", "t2"}, taskIDs(tasks), "lower bound is inclusive")
+}
+
+func testListTasksPaging(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	for _, id := range []string{"t1", "t2", "t3", "t4", "t5"} {
+		createTask(t, s, id, "Task "+id, models.New)
+	}
+
+	tasks, err := s.ListTasks(ctx, storage.TaskFilters{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t4", "t3"}, taskIDs(tasks))
+
+	last := tasks[len(tasks)-1]
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{
+		Limit: 10,
+		After: &storage.TaskCursor{CreatedAt: last.CreatedAt, ID: last.ID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t2", "t1"}, taskIDs(tasks))
+
+	// A cursor carrying the same instant in another zone pages the same way
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{
+		Limit: 10,
+		After: &storage.TaskCursor{CreatedAt: last.CreatedAt.In(time.FixedZone("UTC+5", 5*60*60)), ID: last.ID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t2", "t1"}, taskIDs(tasks))
+
+	count, err := s.CountTasks(ctx, storage.TaskFilters{
+		Limit:  2,
+		Offset: 1,
+		After:  &storage.TaskCursor{CreatedAt: last.CreatedAt, ID: last.ID},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, count, "paging is ignored")
+}
+
+func testStarredTasks(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	for _, id := range []string{"t1", "t2", "t3", "t4"} {
+		createTask(t, s, id, "Task "+id, models.New)
+	}
+	for _, id := range []string{"t1", "t3"} {
+		task, err := s.GetTask(ctx, id)
+		require.NoError(t, err)
+		task.Starred = true
+		require.NoError(t, s.UpdateTask(ctx, task))
+	}
+
+	got, err := s.GetTask(ctx, "t3")
+	require.NoError(t, err)
+	assert.True(t, got.Starred)
+
+	tasks, err := s.ListTasks(ctx, storage.TaskFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t3", "t1", "t4", "t2"}, taskIDs(tasks), "starred first, then newest first")
+
+	starred, unstarred := true, false
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{Starred: &starred})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t3", "t1"}, taskIDs(tasks))
+	count, err := s.CountTasks(ctx, storage.TaskFilters{Starred: &starred})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{Starred: &unstarred})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t4", "t2"}, taskIDs(tasks))
+
+	// Paging with a cursor crosses from the starred tasks into the rest
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{Limit: 2, After: storage.CursorFor(tasks[0])})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t2"}, taskIDs(tasks))
+
+	first, err := s.ListTasks(ctx, storage.TaskFilters{Limit: 1})
+	require.NoError(t, err)
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{Limit: 2, After: storage.CursorFor(first[0])})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t4"}, taskIDs(tasks))
+}
+
+func testCustomFields(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	t1 := &models.Task{ID: "t1", Title: "Sprint 12 task", Priority: models.Normal, Status: models.New}
+	t1.SetCustomField("sprint", "12")
+	t1.SetCustomField("epic", "etcd")
+	require.NoError(t, s.CreateTask(ctx, t1))
+	time.Sleep(2 * time.Millisecond)
+
+	t2 := &models.Task{ID: "t2", Title: "Sprint 13 task", Priority: models.Normal, Status: models.New,
+		CustomFields: map[string]string{"sprint": "13", "story_points": "3"}}
+	require.NoError(t, s.CreateTask(ctx, t2))
+	time.Sleep(2 * time.Millisecond)
+
+	createTask(t, s, "t3", "No fields", models.New)
+
+	got, err := s.GetTask(ctx, "t1")
+	require.NoError(t, err)
+	sprint, ok := got.GetCustomField("sprint")
+	assert.True(t, ok)
+	assert.Equal(t, "12", sprint)
+	assert.Equal(t, map[string]string{"sprint": "12", "epic": "etcd"}, got.CustomFields)
+
+	got, err = s.GetTask(ctx, "t3")
+	require.NoError(t, err)
+	_, ok = got.GetCustomField("sprint")
+	assert.False(t, ok)
+	assert.Empty(t, got.CustomFields)
+
+	tasks, err := s.ListTasks(ctx, storage.TaskFilters{CustomFields: map[string]string{"sprint": "12"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, taskIDs(tasks))
+	count, err := s.CountTasks(ctx, storage.TaskFilters{CustomFields: map[string]string{"sprint": "13"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// Every filtered field has to match
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{CustomFields: map[string]string{"sprint": "12", "epic": "etcd"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, taskIDs(tasks))
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{CustomFields: map[string]string{"sprint": "12", "story_points": "3"}})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	// Updating replaces the fields; an empty value removes one
+	got, err = s.GetTask(ctx, "t2")
+	require.NoError(t, err)
+	got.SetCustomField("sprint", "12")
+	got.SetCustomField("story_points", "")
+	require.NoError(t, s.UpdateTask(ctx, got))
+
+	got, err = s.GetTask(ctx, "t2")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"sprint": "12"}, got.CustomFields)
+	tasks, err = s.ListTasks(ctx, storage.TaskFilters{CustomFields: map[string]string{"sprint": "12"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t2", "t1"}, taskIDs(tasks))
+
+	// Keys are validated
+	bad := &models.Task{Title: "Bad key", CustomFields: map[string]string{"sprint number": "1"}}
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, s.CreateTask(ctx, bad), &validationErr)
+	assert.Equal(t, "custom_fields", validationErr.Field)
+
+	// Merging keeps the target's value on conflicts and adds the source's other fields
+	merged, err := s.MergeTasks(ctx, "t1", "t2", storage.MergeOptions{Prefer: storage.PreferTarget})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"sprint": "12", "epic": "etcd"}, merged.CustomFields)
+}
+
+func testStreamTasks(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	for _, id := range []string{"t1", "t2", "t3", "t4"} {
+		createTask(t, s, id, "Task "+id, models.New, "k8s")
+	}
+	createTask(t, s, "t5", "Archived", models.Archived, "k8s")
+
+	filters := storage.TaskFilters{Tags: []string{"k8s"}, Limit: 3}
+	listed, err := s.ListTasks(ctx, filters)
+	require.NoError(t, err)
+
+	var streamed []*models.Task
+	require.NoError(t, s.StreamTasks(ctx, filters, func(task *models.Task) error {
+		streamed = append(streamed, task)
+		return nil
+	}))
+	assert.Equal(t, taskIDs(listed), taskIDs(streamed), "same tasks in the same order as ListTasks")
+	assert.Equal(t, []string{"k8s"}, streamed[0].Tags)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = s.StreamTasks(ctx, storage.TaskFilters{}, func(*models.Task) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls, "an error from fn ends the iteration")
+}
+
+func testDistinctValues(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	for i, jiraID := range []string{"OCPBUGS-1", "OCPBUGS-2", "OCPBUGS-2", "HOSTEDCP-7", "OCPBUGS-2", "HOSTEDCP-7", "OCP_X-1"} {
+		status := models.New
+		if i == 0 {
+			status = models.Archived
+		}
+		require.NoError(t, s.CreateTask(ctx, &models.Task{JiraID: jiraID, Title: "Task", Priority: models.Normal, Status: status}))
+	}
+	createTask(t, s, "t1", "No ticket", models.New)
+
+	values, err := s.DistinctValues(ctx, storage.DistinctJiraID, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OCPBUGS-2", "HOSTEDCP-7", "OCPBUGS-1", "OCP_X-1"}, values, "most used first, archived included, placeholder left out")
+
+	values, err = s.DistinctValues(ctx, storage.DistinctJiraID, "ocpbugs", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OCPBUGS-2", "OCPBUGS-1"}, values, "prefix ignores case")
+
+	values, err = s.DistinctValues(ctx, storage.DistinctJiraID, "OCP_", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OCP_X-1"}, values, "wildcards in the prefix match literally")
+
+	values, err = s.DistinctValues(ctx, storage.DistinctJiraID, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OCPBUGS-2"}, values)
+
+	values, err = s.DistinctValues(ctx, storage.DistinctJiraID, "nothing", 0)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	_, err = s.DistinctValues(ctx, "title", "", 0)
+	var validationErr *models.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func testSearchTasks(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "t1", "Mentioned in a comment", models.New)
+	createTask(t, s, "t2", "Fix ETCD backup", models.New)
+	createTask(t, s, "t3", "Old etcd work", models.Archived)
+	require.NoError(t, s.CreateComment(ctx, &models.Comment{TaskID: "t1", Content: "related to etcd"}))
+
+	tasks, err := s.SearchTasks(ctx, "etcd", false, false, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t2"}, taskIDs(tasks), "case-insensitive, archived hidden")
+
+	tasks, err = s.SearchTasks(ctx, "etcd", true, true, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t3", "t2", "t1"}, taskIDs(tasks), "field matches rank above comment matches")
+
+	tasks, err = s.SearchTasks(ctx, "etcd", true, true, 1)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+}
+
+func testRelatedTasks(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "src", "Source", models.New, "api", "k8s", "ui")
+	createTask(t, s, "one", "Shares one", models.New, "api")
+	createTask(t, s, "two", "Shares two", models.New, "api", "k8s")
+	createTask(t, s, "none", "Shares none", models.New, "docs")
+	createTask(t, s, "arch", "Archived", models.Archived, "api", "k8s", "ui")
+
+	tasks, err := s.GetRelatedTasks(ctx, "src", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"two", "one"}, taskIDs(tasks))
+
+	tasks, err = s.GetRelatedTasks(ctx, "src", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"two"}, taskIDs(tasks))
+}
+
+func testGetTasks(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "a", "First", models.New, "x")
+	createTask(t, s, "b", "Second", models.Archived)
+	createTask(t, s, "c", "Third", models.Done)
+
+	tasks, err := s.GetTasks(ctx, []string{"a", "missing", "b", "a"})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "First", tasks["a"].Title)
+	assert.Equal(t, []string{"x"}, tasks["a"].Tags)
+	assert.Equal(t, models.Archived, tasks["b"].Status, "archived tasks are included")
+
+	tasks, err = s.GetTasks(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func testCountTaskRelations(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "busy", "Busy", models.New)
+	createTask(t, s, "quiet", "Quiet", models.New)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, s.CreateLink(ctx, &models.Link{TaskID: "busy", Type: models.Other, URL: fmt.Sprintf("https://example.com/%d", i), Status: "active"}))
+	}
+	require.NoError(t, s.CreateComment(ctx, &models.Comment{TaskID: "busy", Content: "note"}))
+
+	counts, err := s.CountTaskRelations(ctx, []string{"busy", "quiet", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]storage.TaskRelationCounts{
+		"busy":  {Links: 2, Comments: 1},
+		"quiet": {},
+	}, counts)
+}
+
+func testLinksAndComments(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "t1", "Task", models.New)
+
+	pr := &models.Link{TaskID: "t1", Type: models.PullRequest, URL: "https://github.com/org/repo/pull/1", Status: "open"}
+	doc := &models.Link{TaskID: "t1", Type: models.Documentation, URL: "https://docs.example.com", Status: "active"}
+	require.NoError(t, s.CreateLink(ctx, pr))
+	require.NoError(t, s.CreateLink(ctx, doc))
+
+	links, err := s.GetTaskLinks(ctx, "t1")
+	require.NoError(t, err)
+	assert.Len(t, links, 2)
+
+	links, err = s.GetTaskLinksByType(ctx, "t1", models.Documentation)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, doc.ID, links[0].ID)
+
+	pr.Status = "merged"
+	require.NoError(t, s.UpdateLink(ctx, pr))
+	got, err := s.GetLink(ctx, pr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "merged", got.Status)
+
+	pr.Metadata = `{"a":1}`
+	require.NoError(t, s.UpdateLink(ctx, pr))
+	pr.Metadata = `{"b":2,"a":null}`
+	require.NoError(t, s.UpdateLinkWithOptions(ctx, pr, storage.UpdateLinkOptions{MergeMetadata: true}))
+	got, err = s.GetLink(ctx, pr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"b": float64(2)}, got.MetadataMap(), "null removes a key")
+
+	require.NoError(t, s.DeleteLink(ctx, doc.ID))
+	links, err = s.GetTaskLinks(ctx, "t1")
+	require.NoError(t, err)
+	assert.Len(t, links, 1)
+
+	for _, content := range []string{"first", "second", "third"} {
+		require.NoError(t, s.CreateComment(ctx, &models.Comment{TaskID: "t1", Content: content}))
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	comments, total, err := s.GetTaskCommentsPaged(ctx, "t1", 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "second", comments[0].Content)
+	assert.Equal(t, "third", comments[1].Content)
+
+	require.NoError(t, s.DeleteComment(ctx, comments[0].ID))
+	all, err := s.GetTaskComments(ctx, "t1")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	require.NoError(t, s.DeleteTask(ctx, "t1"))
+	_, err = s.GetLink(ctx, pr.ID)
+	assert.ErrorIs(t, err, storage.ErrNotFound, "links are removed with their task")
+	_, err = s.GetComment(ctx, all[0].ID)
+	assert.ErrorIs(t, err, storage.ErrNotFound, "comments are removed with their task")
+}
+
+func testCreateComments(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "t1", "Task", models.New)
+
+	old := time.Date(2023, 3, 1, 9, 30, 0, 0, time.UTC)
+	before := time.Now()
+	comments := []*models.Comment{
+		{TaskID: "t1", Content: "migrated", CreatedAt: old},
+		{TaskID: "t1", Content: "fresh one"},
+		{TaskID: "t1", Content: "fresh two"},
+	}
+	require.NoError(t, s.CreateComments(ctx, comments))
+
+	got, err := s.GetTaskComments(ctx, "t1")
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, "migrated", got[0].Content)
+	assert.True(t, got[0].CreatedAt.Equal(old), "supplied timestamps are kept")
+	assert.Equal(t, "fresh one", got[1].Content)
+	assert.Equal(t, "fresh two", got[2].Content)
+	assert.False(t, got[1].CreatedAt.Before(before.Truncate(time.Second)))
+
+	// One invalid comment rejects the whole batch
+	err = s.CreateComments(ctx, []*models.Comment{
+		{TaskID: "t1", Content: "valid"},
+		{TaskID: "t1"},
+	})
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	got, err = s.GetTaskComments(ctx, "t1")
+	require.NoError(t, err)
+	assert.Len(t, got, 3)
+}
+
+func testStreamLinksAndComments(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "t1", "First", models.New)
+	createTask(t, s, "t2", "Second", models.Archived)
+	for _, link := range []*models.Link{
+		{ID: "l3", TaskID: "t2", Type: models.Other, URL: "https://example.com/3"},
+		{ID: "l1", TaskID: "t1", Type: models.Other, URL: "https://example.com/1"},
+		{ID: "l2", TaskID: "t1", Type: models.Other, URL: "https://example.com/2"},
+	} {
+		require.NoError(t, s.CreateLink(ctx, link))
+	}
+	require.NoError(t, s.CreateComments(ctx, []*models.Comment{
+		{ID: "c3", TaskID: "t2", Content: "on the archived task"},
+		{ID: "c2", TaskID: "t1", Content: "newer", CreatedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "c1", TaskID: "t1", Content: "older", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}))
+
+	var links []string
+	require.NoError(t, s.StreamLinks(ctx, func(link *models.Link) error {
+		links = append(links, link.ID)
+		return nil
+	}))
+	assert.Equal(t, []string{"l1", "l2", "l3"}, links, "grouped by task, archived tasks included")
+
+	var comments []string
+	require.NoError(t, s.StreamComments(ctx, func(comment *models.Comment) error {
+		comments = append(comments, comment.ID)
+		return nil
+	}))
+	assert.Equal(t, []string{"c1", "c2", "c3"}, comments, "grouped by task, oldest first")
+
+	stop := errors.New("stop")
+	assert.ErrorIs(t, s.StreamLinks(ctx, func(*models.Link) error { return stop }), stop)
+	assert.ErrorIs(t, s.StreamComments(ctx, func(*models.Comment) error { return stop }), stop)
+}
+
+func testTags(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "t1", "One", models.New, "api", "k8s")
+	createTask(t, s, "t2", "Two", models.New, "api")
+	createTask(t, s, "t3", "Archived", models.Archived, "api")
+
+	tags, err := s.ListTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"api": 2, "k8s": 1}, tags)
+
+	renamed, err := s.RenameTag(ctx, "api", "k8s")
+	require.NoError(t, err)
+	assert.Equal(t, 3, renamed, "archived tasks are renamed too")
+
+	t1, err := s.GetTask(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k8s"}, t1.Tags, "duplicates collapse into the target tag")
+
+	var validationErr *models.ValidationError
+	_, err = s.RenameTag(ctx, "k8s", "k8s")
+	assert.True(t, errors.As(err, &validationErr))
+
+	// Both sides are normalized like stored tags
+	renamed, err = s.RenameTag(ctx, " K8S", "Kubernetes   Cluster ")
+	require.NoError(t, err)
+	assert.Equal(t, 3, renamed)
+	t1, err = s.GetTask(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kubernetes cluster"}, t1.Tags)
+
+	for _, to := range []string{"a,b", "   ", "KUBERNETES CLUSTER"} {
+		_, err = s.RenameTag(ctx, "kubernetes cluster", to)
+		assert.True(t, errors.As(err, &validationErr), "renaming to %q is rejected", to)
+	}
+}
+
+func testMergeTasks(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "src", "Source", models.InProgress, "api")
+	createTask(t, s, "dst", "Target", models.New, "ui")
+	require.NoError(t, s.CreateLink(ctx, &models.Link{TaskID: "src", Type: models.JiraTicket, URL: "https://issues.example.com/1", Status: "open"}))
+	require.NoError(t, s.CreateComment(ctx, &models.Comment{TaskID: "src", Content: "moved"}))
+
+	merged, err := s.MergeTasks(ctx, "src", "dst", storage.MergeOptions{Prefer: storage.PreferTarget})
+	require.NoError(t, err)
+	assert.Equal(t, "Target", merged.Title)
+	assert.Equal(t, []string{"ui", "api"}, merged.Tags)
+
+	links, err := s.GetTaskLinks(ctx, "dst")
+	require.NoError(t, err)
+	assert.Len(t, links, 1)
+	comments, err := s.GetTaskComments(ctx, "dst")
+	require.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	source, err := s.GetTask(ctx, "src")
+	require.NoError(t, err)
+	assert.Equal(t, models.Archived, source.Status)
+
+	var validationErr *models.ValidationError
+	_, err = s.MergeTasks(ctx, "dst", "dst", storage.MergeOptions{})
+	assert.True(t, errors.As(err, &validationErr))
+
+	_, err = s.MergeTasks(ctx, "missing", "dst", storage.MergeOptions{})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Taking the source's status runs the same checks as an update
+	importBlockedTask(t, s, "blocked-src", "Blocked source")
+	createTask(t, s, "open-dst", "Open target", models.New)
+	_, err = s.MergeTasks(ctx, "blocked-src", "open-dst", storage.MergeOptions{Prefer: storage.PreferSource})
+	require.ErrorAs(t, err, &validationErr, "the target can't become blocked without a blocker")
+	assert.Equal(t, "blockers", validationErr.Field)
+	_, err = s.GetTask(ctx, "blocked-src")
+	require.NoError(t, err, "a rejected merge leaves the source alone")
+
+	require.NoError(t, models.Configure(models.Limits{EnforceTransitions: true}))
+	defer func() { _ = models.Configure(models.Limits{}) }()
+	createTask(t, s, "working-src", "Working source", models.InProgress)
+	createTask(t, s, "archived-dst", "Archived target", models.Archived)
+	_, err = s.MergeTasks(ctx, "working-src", "archived-dst", storage.MergeOptions{Prefer: storage.PreferSource})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, models.CodeInvalidTransition, validationErr.Code)
+}
+
+func testPurgeArchived(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "old", "Old", models.Archived)
+	createTask(t, s, "open", "Open", models.New)
+
+	purged, err := s.PurgeArchived(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = s.PurgeArchived(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	_, err = s.GetTask(ctx, "old")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = s.GetTask(ctx, "open")
+	assert.NoError(t, err)
+}
+
+func testActivity(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	task := createTask(t, s, "t1", "Task", models.New)
+	task.Status = models.Done
+	require.NoError(t, s.UpdateTask(ctx, task))
+	require.NoError(t, s.CreateComment(ctx, &models.Comment{TaskID: "t1", Content: "done"}))
+
+	activity, err := s.GetTaskActivity(ctx, "t1")
+	require.NoError(t, err)
+	var types []models.ActivityType
+	for _, a := range activity {
+		types = append(types, a.Type)
+	}
+	assert.Equal(t, []models.ActivityType{
+		models.ActivityTaskCreated,
+		models.ActivityStatusChanged,
+		models.ActivityCommentAdded,
+	}, types)
+}
+
+func testImportData(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	createTask(t, s, "existing", "Existing", models.New)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	result, err := s.ImportData(ctx, &models.ExportData{
+		Tasks: []*models.Task{
+			{ID: "existing", JiraID: "NO-JIRA", Title: "Clash", Priority: models.Normal, Status: models.New},
+			{ID: "imported", JiraID: "NO-JIRA", Title: "Imported", Priority: models.High, Status: models.New, CreatedAt: created},
+		},
+		Links: []*models.Link{
+			{ID: "l1", TaskID: "imported", Type: models.JiraTicket, URL: "https://issues.example.com/1", Status: "open"},
+		},
+		Comments: []*models.Comment{
+			{ID: "c1", TaskID: "imported", Content: "hello"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &models.ImportResult{
+		TasksImported:    1,
+		TasksSkipped:     1,
+		LinksImported:    1,
+		CommentsImported: 1,
+	}, result)
+
+	existing, err := s.GetTask(ctx, "existing")
+	require.NoError(t, err)
+	assert.Equal(t, "Existing", existing.Title)
+
+	imported, err := s.GetTask(ctx, "imported")
+	require.NoError(t, err)
+	assert.True(t, created.Equal(imported.CreatedAt), "timestamps are preserved")
+
+	var validationErr *models.ValidationError
+	_, err = s.ImportData(ctx, &models.ExportData{Tasks: []*models.Task{{ID: "bad"}}})
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func testWithTransaction(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	err := s.WithTransaction(ctx, func(tx storage.Storage) error {
+		createTask(t, tx, "kept", "Kept", models.New)
+		return tx.CreateComment(ctx, &models.Comment{TaskID: "kept", Content: "note"})
+	})
+	require.NoError(t, err)
+	comments, err := s.GetTaskComments(ctx, "kept")
+	require.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	// A failure part way through undoes the writes that came before it
+	err = s.WithTransaction(ctx, func(tx storage.Storage) error {
+		createTask(t, tx, "dropped", "Dropped", models.New)
+		require.NoError(t, tx.CreateComment(ctx, &models.Comment{TaskID: "dropped", Content: "note"}))
+		return tx.CreateLink(ctx, &models.Link{TaskID: "dropped", Type: models.Other, URL: "not a url", Status: "active"})
+	})
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	_, err = s.GetTask(ctx, "dropped")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	activity, err := s.GetTaskActivity(ctx, "dropped")
+	require.NoError(t, err)
+	assert.Empty(t, activity)
+
+	// A failed nested transaction only undoes its own writes
+	err = s.WithTransaction(ctx, func(tx storage.Storage) error {
+		createTask(t, tx, "outer", "Outer", models.New)
+		nestedErr := tx.WithTransaction(ctx, func(inner storage.Storage) error {
+			createTask(t, inner, "inner", "Inner", models.New)
+			return errors.New("inner failure")
+		})
+		assert.EqualError(t, nestedErr, "inner failure")
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = s.GetTask(ctx, "outer")
+	assert.NoError(t, err)
+	_, err = s.GetTask(ctx, "inner")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testStatusTransitions(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	require.NoError(t, models.Configure(models.Limits{EnforceTransitions: true}))
+	defer func() { _ = models.Configure(models.Limits{}) }()
+
+	task := createTask(t, s, "t1", "Task", models.New)
+
+	task.Status = models.InProgress
+	require.NoError(t, s.UpdateTask(ctx, task), "legal transition")
+
+	task.Status = models.Archived
+	require.NoError(t, s.UpdateTask(ctx, task))
+
+	task.Status = models.InProgress
+	err := s.UpdateTask(ctx, task)
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr, "archived tasks can't jump back into progress")
+	got, err := s.GetTask(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, models.Archived, got.Status)
+
+	require.NoError(t, s.UpdateTask(storage.WithForcedTransition(ctx), task))
+	got, err = s.GetTask(ctx, "t1")
+	require.NoError(t, err)
+	assert.Equal(t, models.InProgress, got.Status, "forced transitions skip the check")
+}
+
+func testBlockedNeedsBlocker(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	task := createTask(t, s, "t1", "Task", models.InProgress)
+	task.Status = models.Blocked
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, s.UpdateTask(ctx, task), &validationErr, "moving to blocked needs a blocker")
+	assert.Equal(t, "blockers", validationErr.Field)
+
+	task.Blockers = []string{"Waiting for review"}
+	require.NoError(t, s.UpdateTask(ctx, task))
+
+	// A new task moves to blocked from nothing
+	created := &models.Task{JiraID: "NO-JIRA", Title: "Created blocked", Priority: models.Normal, Status: models.Blocked}
+	require.ErrorAs(t, s.CreateTask(ctx, created), &validationErr)
+	assert.Equal(t, "blockers", validationErr.Field)
+	require.ErrorAs(t, s.CreateTaskWithLinks(ctx, created, nil), &validationErr)
+	assert.Equal(t, "blockers", validationErr.Field)
+	created.Blockers = []string{"Waiting for review"}
+	require.NoError(t, s.CreateTask(ctx, created))
+
+	// Tasks blocked before the rule existed still import and save
+	importBlockedTask(t, s, "legacy", "Legacy")
+	legacy, err := s.GetTask(ctx, "legacy")
+	require.NoError(t, err)
+	legacy.Starred = true
+	require.NoError(t, s.UpdateTask(ctx, legacy), "already blocked tasks are left alone")
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"michishirube/internal/models"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TaskCursor is the sort key of the last task on a page. Tasks are listed
+// starred first and then newest first, so the next page holds tasks that sort
+// strictly after it.
+type TaskCursor struct {
+	Starred   bool
+	CreatedAt time.Time
+	ID        string
+}
+
+// CursorFor returns the cursor pointing just past task
+func CursorFor(task *models.Task) *TaskCursor {
+	return &TaskCursor{Starred: task.Starred, CreatedAt: task.CreatedAt, ID: task.ID}
+}
+
+// Encode returns the opaque string handed to API clients
+func (c *TaskCursor) Encode() string {
+	raw := c.CreatedAt.Format(time.RFC3339Nano) + "|" + c.ID
+	if c.Starred {
+		raw = "*" + raw
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTaskCursor parses a cursor produced by Encode
+func DecodeTaskCursor(s string) (*TaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	// Cursors of starred tasks carry a leading "*"
+	rest, starred := strings.CutPrefix(string(raw), "*")
+	createdAt, id, ok := strings.Cut(rest, "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &TaskCursor{Starred: starred, CreatedAt: t, ID: id}, nil
+}
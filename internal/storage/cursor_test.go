@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCursor_RoundTrip(t *testing.T) {
+	cursor := &TaskCursor{
+		CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 123456789, time.FixedZone("", -7*3600)),
+		ID:        "550e8400-e29b-41d4-a716-446655440000",
+	}
+
+	decoded, err := DecodeTaskCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.CreatedAt.Format(time.RFC3339Nano), decoded.CreatedAt.Format(time.RFC3339Nano))
+}
+
+func TestTaskCursor_RoundTripStarred(t *testing.T) {
+	cursor := &TaskCursor{Starred: true, CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), ID: "t1"}
+
+	decoded, err := DecodeTaskCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, decoded.Starred)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+
+	cursor.Starred = false
+	decoded, err = DecodeTaskCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.False(t, decoded.Starred)
+}
+
+func TestDecodeTaskCursor_Invalid(t *testing.T) {
+	for _, value := range []string{"", "not base64!", "bm8tc2VwYXJhdG9y", "bm90LWEtdGltZXxpZA"} {
+		_, err := DecodeTaskCursor(value)
+		assert.ErrorIs(t, err, ErrInvalidCursor, value)
+	}
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"fmt"
+
+	"michishirube/internal/models"
+)
+
+// DistinctJiraID is the task field DistinctValues suggests values for
+const DistinctJiraID = "jira_id"
+
+// CheckDistinctField returns a ValidationError for fields DistinctValues
+// does not support
+func CheckDistinctField(field string) error {
+	switch field {
+	case DistinctJiraID:
+		return nil
+	}
+	return &models.ValidationError{Field: "field", Code: models.CodeNotAllowed, Message: fmt.Sprintf("field %q does not support suggestions", field)}
+}
@@ -3,57 +3,236 @@
 package storage
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"michishirube/internal/models"
 )
 
+// ErrNotFound is returned, possibly wrapped, when a task, link or comment
+// does not exist. Check for it with errors.Is.
+var ErrNotFound = errors.New("not found")
+
+// Storage persists tasks, links and comments. Methods take the caller's context
+// so request cancellation and deadlines reach the database.
 type Storage interface {
 	// Tasks
 	// CreateTask creates a new task
-	CreateTask(task *models.Task) error
+	CreateTask(ctx context.Context, task *models.Task) error
+	// CreateTaskWithLinks creates a task and its initial links atomically
+	CreateTaskWithLinks(ctx context.Context, task *models.Task, links []*models.Link) error
+	// GetTaskByJiraID retrieves the oldest non-archived task with the given Jira ID
+	GetTaskByJiraID(ctx context.Context, jiraID string) (*models.Task, error)
 	// GetTask retrieves a task by its ID
-	GetTask(id string) (*models.Task, error)
+	GetTask(ctx context.Context, id string) (*models.Task, error)
+	// GetTasks retrieves several tasks at once, keyed by ID; unknown IDs are left out
+	GetTasks(ctx context.Context, ids []string) (map[string]*models.Task, error)
 	// UpdateTask updates an existing task
-	UpdateTask(task *models.Task) error
+	UpdateTask(ctx context.Context, task *models.Task) error
 	// DeleteTask deletes a task by its ID
-	DeleteTask(id string) error
+	DeleteTask(ctx context.Context, id string) error
 	// ListTasks retrieves a list of tasks based on the provided filters
-	ListTasks(filters TaskFilters) ([]*models.Task, error)
-	SearchTasks(query string, includeArchived bool, limit int) ([]*models.Task, error)
+	ListTasks(ctx context.Context, filters TaskFilters) ([]*models.Task, error)
+	// StreamTasks calls fn for each task ListTasks would return, in the same
+	// order, without building the whole list. fn must not use the storage:
+	// the SQLite backend keeps its result set open while fn runs. An error
+	// from fn stops the iteration and is returned
+	StreamTasks(ctx context.Context, filters TaskFilters, fn func(*models.Task) error) error
+	// CountTasks counts the tasks matching filters, ignoring Limit, Offset and After
+	CountTasks(ctx context.Context, filters TaskFilters) (int, error)
+	// SearchTasks matches title, Jira ID and tags, and optionally comment
+	// content; tasks matching on their own fields rank before comment-only matches
+	SearchTasks(ctx context.Context, query string, includeArchived, includeComments bool, limit int) ([]*models.Task, error)
+	// GetRelatedTasks returns non-archived tasks sharing at least one tag with
+	// the task, most shared tags first
+	GetRelatedTasks(ctx context.Context, taskID string, limit int) ([]*models.Task, error)
+	// PurgeArchived deletes archived tasks last updated before olderThan,
+	// together with their links and comments, and returns how many were removed
+	PurgeArchived(ctx context.Context, olderThan time.Time) (int, error)
+	// MergeTasks folds the source task into the target and returns the updated target
+	MergeTasks(ctx context.Context, sourceID, targetID string, opts MergeOptions) (*models.Task, error)
+	// DistinctValues returns the values of a task field, most used first,
+	// for autocomplete. Only values starting with prefix (ignoring case) are
+	// included, and the placeholder Jira ID is left out. A non-positive limit
+	// returns all
+	DistinctValues(ctx context.Context, field, prefix string, limit int) ([]string, error)
+	// CountTaskRelations returns how many links and comments each of the given
+	// tasks has. Existing tasks without any are included with zero counts;
+	// unknown IDs are left out
+	CountTaskRelations(ctx context.Context, taskIDs []string) (map[string]TaskRelationCounts, error)
 
 	// Links
 	// CreateLink creates a new link
-	CreateLink(link *models.Link) error
+	CreateLink(ctx context.Context, link *models.Link) error
 	// GetLink retrieves a link by its ID
-	GetLink(id string) (*models.Link, error)
+	GetLink(ctx context.Context, id string) (*models.Link, error)
 	// UpdateLink updates an existing link
-	UpdateLink(link *models.Link) error
+	UpdateLink(ctx context.Context, link *models.Link) error
+	// UpdateLinkWithOptions updates an existing link; with MergeMetadata the
+	// link's metadata is merged into the stored object instead of replacing it
+	UpdateLinkWithOptions(ctx context.Context, link *models.Link, opts UpdateLinkOptions) error
 	// DeleteLink deletes a link by its ID
-	DeleteLink(id string) error
+	DeleteLink(ctx context.Context, id string) error
 	// GetTaskLinks retrieves all links for a specific task
-	GetTaskLinks(taskID string) ([]*models.Link, error)
+	GetTaskLinks(ctx context.Context, taskID string) ([]*models.Link, error)
+
+	// GetTaskLinksByType retrieves a task's links of the given types; no types returns all links
+	GetTaskLinksByType(ctx context.Context, taskID string, types ...models.LinkType) ([]*models.Link, error)
 
 	// Comments
 	// CreateComment creates a new comment
-	CreateComment(comment *models.Comment) error
+	CreateComment(ctx context.Context, comment *models.Comment) error
+	// CreateComments creates several comments atomically. Comments that carry
+	// a CreatedAt keep it; the rest are stamped with the current time in slice order
+	CreateComments(ctx context.Context, comments []*models.Comment) error
 	// GetComment retrieves a comment by its ID
-	GetComment(id string) (*models.Comment, error)
+	GetComment(ctx context.Context, id string) (*models.Comment, error)
 	// DeleteComment deletes a comment by its ID
-	DeleteComment(id string) error
-	GetTaskComments(taskID string) ([]*models.Comment, error)
+	DeleteComment(ctx context.Context, id string) error
+	GetTaskComments(ctx context.Context, taskID string) ([]*models.Comment, error)
+	// GetTaskCommentsPaged returns one page of a task's comments, oldest first,
+	// and the task's total comment count. A non-positive limit returns all
+	GetTaskCommentsPaged(ctx context.Context, taskID string, limit, offset int) ([]*models.Comment, int, error)
+
+	// Tags
+	// ListTags counts how many non-archived tasks use each tag
+	ListTags(ctx context.Context) (map[string]int, error)
+	// RenameTag replaces a tag on every task, merging it into the target tag
+	// where both are present, and returns how many tasks changed
+	RenameTag(ctx context.Context, from, to string) (int, error)
+
+	// Activity
+	// GetTaskActivity returns the task's timeline, oldest first
+	GetTaskActivity(ctx context.Context, taskID string) ([]models.Activity, error)
+
+	// Backup
+	// ImportData inserts exported records in a single transaction, skipping IDs that already exist
+	ImportData(ctx context.Context, data *models.ExportData) (*models.ImportResult, error)
+	// StreamLinks calls fn for every link whose task exists, ordered by task,
+	// reading them all in one query. As with StreamTasks, fn must not use the storage
+	StreamLinks(ctx context.Context, fn func(*models.Link) error) error
+	// StreamComments calls fn for every comment whose task exists, ordered by
+	// task and then oldest first, reading them all in one query. As with
+	// StreamTasks, fn must not use the storage
+	StreamComments(ctx context.Context, fn func(*models.Comment) error) error
+
+	// Transactions
+	// WithTransaction runs fn with a storage bound to a single transaction,
+	// committing if fn returns nil and rolling back otherwise. fn must only use
+	// tx, not the outer storage. A nested call that fails only undoes its own
+	// writes, leaving the enclosing transaction to carry on
+	WithTransaction(ctx context.Context, fn func(tx Storage) error) error
 
 	// Migrations
 	// RunMigrations runs the database migrations
 	RunMigrations() error
+	// Ping verifies the database is reachable
+	Ping(ctx context.Context) error
 	// Close closes the database connection
 	Close() error
 }
 
+// Checkpointer is implemented by backends that keep a write-ahead log which
+// needs to be folded back into the main database periodically
+type Checkpointer interface {
+	// WALEnabled reports whether the write-ahead log is in use
+	WALEnabled(ctx context.Context) (bool, error)
+	// Checkpoint copies the write-ahead log into the database and truncates it
+	Checkpoint(ctx context.Context) (*CheckpointResult, error)
+}
+
+// CheckpointResult reports the outcome of a WAL checkpoint
+type CheckpointResult struct {
+	Busy         bool `json:"busy"`         // Checkpoint could not complete because of concurrent readers/writers
+	LogPages     int  `json:"log_pages"`    // Pages in the write-ahead log
+	Checkpointed int  `json:"checkpointed"` // Pages copied back into the database
+}
+
+// Optimizer is implemented by backends whose database file can be compacted
+type Optimizer interface {
+	// Optimize rebuilds the database to reclaim the space left by deleted rows
+	// and refreshes the query planner's statistics
+	Optimize(ctx context.Context) error
+	// DatabaseSize returns the size of the database in bytes
+	DatabaseSize(ctx context.Context) (int64, error)
+}
+
+// OptimizeResult reports the database size around an Optimize call
+type OptimizeResult struct {
+	SizeBefore int64 `json:"size_before"` // Bytes before optimizing
+	SizeAfter  int64 `json:"size_after"`  // Bytes after optimizing
+}
+
+// OrphanCleaner is implemented by backends where links and comments can
+// outlive their task, e.g. when rows were imported with foreign keys off
+type OrphanCleaner interface {
+	// FindOrphans returns the IDs of links and comments whose task is missing
+	FindOrphans(ctx context.Context) (links []string, comments []string, err error)
+	// PurgeOrphans deletes the links and comments FindOrphans reports and
+	// returns their IDs
+	PurgeOrphans(ctx context.Context) (links []string, comments []string, err error)
+}
+
+// SchemaVersioner is implemented by backends with a migrated schema, so a
+// deployment can tell whether the database matches the binary
+type SchemaVersioner interface {
+	// SchemaVersion returns the latest migration applied to the database
+	SchemaVersion() (int, error)
+	// ExpectedSchemaVersion returns the latest migration this build knows
+	ExpectedSchemaVersion() int
+}
+
+// OrphanReport lists links and comments that reference a missing task
+type OrphanReport struct {
+	Links    []string `json:"links"`    // IDs of orphaned links
+	Comments []string `json:"comments"` // IDs of orphaned comments
+}
+
 // TaskFilters is a struct that contains the filters for the tasks
 type TaskFilters struct {
 	Status          []models.Status
 	Priority        []models.Priority
-	Tags            []string
+	Tags            []string          // Tasks carrying any of these tags
+	Starred         *bool             // Only starred (true) or unstarred (false) tasks; nil for both
+	CustomFields    map[string]string // Tasks whose custom fields hold exactly these values, all of them
 	IncludeArchived bool
 	Limit           int
 	Offset          int
+	After           *TaskCursor // Keyset pagination: only tasks sorting after this cursor
+
+	// Date ranges are half-open: *After bounds are inclusive, *Before bounds
+	// are exclusive. Zero values leave the range unbounded.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+}
+
+// TaskRelationCounts is the number of links and comments attached to a task
+type TaskRelationCounts struct {
+	Links    int
+	Comments int
+}
+
+// UpdateLinkOptions controls how UpdateLinkWithOptions writes a link
+type UpdateLinkOptions struct {
+	// MergeMetadata applies the link's metadata as a JSON merge patch (RFC 7396):
+	// nested objects are merged key by key and null removes a key. The merged
+	// metadata is written back to the link.
+	MergeMetadata bool
+}
+
+// MergePreference selects which task wins when scalar fields conflict during a merge
+type MergePreference string
+
+const (
+	PreferTarget MergePreference = "target"
+	PreferSource MergePreference = "source"
+)
+
+// MergeOptions controls how MergeTasks combines two tasks
+type MergeOptions struct {
+	Prefer       MergePreference // Task whose jira_id, title, priority, status and conflicting custom fields are kept
+	DeleteSource bool            // Delete the source task instead of archiving it
 }
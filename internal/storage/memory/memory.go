@@ -0,0 +1,1202 @@
+// Package memory provides a storage.Storage that keeps everything in process
+// memory. It is meant for tests and throwaway demo instances: nothing survives
+// a restart.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"michishirube/internal/models"
+	"michishirube/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// Storage is an in-memory storage.Storage. It mirrors the SQLite backend's
+// validation, defaults, ordering and cascades so either can back the server.
+// Values are copied in and out, so callers never share state with the store.
+type Storage struct {
+	mu sync.RWMutex
+
+	tasks    map[string]*models.Task
+	links    []*models.Link // Kept in insertion order, like SQLite's rowid scan
+	comments map[string]*models.Comment
+
+	activity       []models.Activity
+	nextActivityID int64
+}
+
+var _ storage.Storage = (*Storage)(nil)
+
+func New() *Storage {
+	return &Storage{
+		tasks:    make(map[string]*models.Task),
+		comments: make(map[string]*models.Comment),
+	}
+}
+
+// RunMigrations is a no-op; there is no schema to migrate
+func (s *Storage) RunMigrations() error {
+	return nil
+}
+
+// Ping always succeeds
+func (s *Storage) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close is a no-op; the data is released with the Storage
+func (s *Storage) Close() error {
+	return nil
+}
+
+// Task operations
+func (s *Storage) CreateTask(ctx context.Context, task *models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.insertTask(task); err != nil {
+		return err
+	}
+	s.recordTaskCreated(task)
+	return nil
+}
+
+// CreateTaskWithLinks validates every link before storing anything, so an
+// invalid link leaves the store untouched
+func (s *Storage) CreateTaskWithLinks(ctx context.Context, task *models.Task, links []*models.Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := task.Validate(); err != nil {
+		return err
+	}
+	if err := task.ValidateBlockedChange(""); err != nil {
+		return err
+	}
+	if task.ID == "" {
+		task.ID = uuid.New().String()
+	}
+	if _, exists := s.tasks[task.ID]; exists {
+		return fmt.Errorf("task %s already exists", task.ID)
+	}
+
+	for i, link := range links {
+		link.TaskID = task.ID
+		if err := link.Validate(); err != nil {
+			if validationErr, ok := err.(*models.ValidationError); ok {
+				return &models.ValidationError{
+					Field:   fmt.Sprintf("links[%d].%s", i, validationErr.Field),
+					Code:    validationErr.Code,
+					Message: validationErr.Message,
+				}
+			}
+			return err
+		}
+		if link.ID == "" {
+			link.ID = uuid.New().String()
+		}
+		if s.findLink(link.ID) >= 0 {
+			return fmt.Errorf("link %s already exists", link.ID)
+		}
+	}
+
+	if err := s.insertTask(task); err != nil {
+		return err
+	}
+	s.recordTaskCreated(task)
+	for _, link := range links {
+		s.links = append(s.links, copyLink(link))
+		s.recordLinkAdded(link)
+	}
+	return nil
+}
+
+// insertTask validates and defaults a new task, then stores a copy. Callers hold the lock.
+func (s *Storage) insertTask(task *models.Task) error {
+	if err := task.Validate(); err != nil {
+		return err
+	}
+	if err := task.ValidateBlockedChange(""); err != nil {
+		return err
+	}
+
+	if task.ID == "" {
+		task.ID = uuid.New().String()
+	}
+	if _, exists := s.tasks[task.ID]; exists {
+		return fmt.Errorf("task %s already exists", task.ID)
+	}
+
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	if task.Status == "" {
+		task.Status = models.NewTaskStatus()
+	}
+	if task.Priority == "" {
+		task.Priority = models.NewTaskPriority()
+	}
+
+	s.tasks[task.ID] = copyTask(task)
+	return nil
+}
+
+func (s *Storage) recordTaskCreated(task *models.Task) {
+	s.recordActivity(task.ID, models.ActivityTaskCreated, map[string]interface{}{
+		"jira_id":  task.JiraID,
+		"title":    task.Title,
+		"status":   task.Status,
+		"priority": task.Priority,
+	})
+}
+
+func (s *Storage) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("task %w", storage.ErrNotFound)
+	}
+	return copyTask(task), nil
+}
+
+func (s *Storage) GetTasks(ctx context.Context, ids []string) (map[string]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := make(map[string]*models.Task, len(ids))
+	for _, id := range ids {
+		if task, ok := s.tasks[id]; ok {
+			tasks[id] = copyTask(task)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *Storage) GetTaskByJiraID(ctx context.Context, jiraID string) (*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var oldest *models.Task
+	for _, task := range s.tasks {
+		if task.JiraID != jiraID || task.Status == models.Archived {
+			continue
+		}
+		if oldest == nil || task.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = task
+		}
+	}
+	if oldest == nil {
+		return nil, fmt.Errorf("task %w", storage.ErrNotFound)
+	}
+	return copyTask(oldest), nil
+}
+
+func (s *Storage) UpdateTask(ctx context.Context, task *models.Task) error {
+	if err := task.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.tasks[task.ID]
+	if !ok {
+		return fmt.Errorf("task %w", storage.ErrNotFound)
+	}
+	previousStatus := existing.Status
+	if err := storage.CheckTransition(ctx, previousStatus, task.Status); err != nil {
+		return err
+	}
+	if err := task.ValidateBlockedChange(previousStatus); err != nil {
+		return err
+	}
+
+	task.CreatedAt = existing.CreatedAt
+	task.UpdatedAt = time.Now()
+	s.tasks[task.ID] = copyTask(task)
+
+	if previousStatus != task.Status {
+		s.recordActivity(task.ID, models.ActivityStatusChanged, map[string]interface{}{
+			"from": previousStatus,
+			"to":   task.Status,
+		})
+	} else {
+		s.recordActivity(task.ID, models.ActivityTaskUpdated, map[string]interface{}{
+			"title":    task.Title,
+			"priority": task.Priority,
+		})
+	}
+	return nil
+}
+
+func (s *Storage) DeleteTask(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteTask(id)
+	return nil
+}
+
+// deleteTask removes a task with its links, comments and activity, like the
+// SQLite schema's ON DELETE CASCADE. Callers hold the lock.
+func (s *Storage) deleteTask(id string) {
+	delete(s.tasks, id)
+	s.links = slices.DeleteFunc(s.links, func(link *models.Link) bool {
+		return link.TaskID == id
+	})
+	for commentID, comment := range s.comments {
+		if comment.TaskID == id {
+			delete(s.comments, commentID)
+		}
+	}
+	s.activity = slices.DeleteFunc(s.activity, func(a models.Activity) bool {
+		return a.TaskID == id
+	})
+}
+
+func (s *Storage) PurgeArchived(ctx context.Context, olderThan time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for id, task := range s.tasks {
+		if task.Status == models.Archived && task.UpdatedAt.Before(olderThan) {
+			s.deleteTask(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *Storage) ListTasks(ctx context.Context, filters storage.TaskFilters) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tasks []*models.Task
+	for _, task := range s.tasks {
+		if matchesFilters(task, filters) {
+			tasks = append(tasks, task)
+		}
+	}
+	sortListOrder(tasks)
+
+	if filters.Offset > 0 {
+		if filters.Offset >= len(tasks) {
+			tasks = nil
+		} else {
+			tasks = tasks[filters.Offset:]
+		}
+	}
+	if filters.Limit > 0 && len(tasks) > filters.Limit {
+		tasks = tasks[:filters.Limit]
+	}
+	return copyTasks(tasks), nil
+}
+
+// StreamTasks lists the tasks up front and calls fn without holding the
+// lock, so fn may use the store
+func (s *Storage) StreamTasks(ctx context.Context, filters storage.TaskFilters, fn func(*models.Task) error) error {
+	tasks, err := s.ListTasks(ctx, filters)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) CountTasks(ctx context.Context, filters storage.TaskFilters) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	filters.After = nil
+	count := 0
+	for _, task := range s.tasks {
+		if matchesFilters(task, filters) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// matchesFilters applies every TaskFilters condition except paging
+func matchesFilters(task *models.Task, filters storage.TaskFilters) bool {
+	if !filters.IncludeArchived && task.Status == models.Archived {
+		return false
+	}
+	if len(filters.Status) > 0 && !slices.Contains(filters.Status, task.Status) {
+		return false
+	}
+	if len(filters.Priority) > 0 && !slices.Contains(filters.Priority, task.Priority) {
+		return false
+	}
+	if filters.Starred != nil && task.Starred != *filters.Starred {
+		return false
+	}
+	if len(filters.Tags) > 0 && !slices.ContainsFunc(filters.Tags, func(tag string) bool {
+		return slices.Contains(task.Tags, tag)
+	}) {
+		return false
+	}
+	for key, value := range filters.CustomFields {
+		if got, ok := task.GetCustomField(key); !ok || got != value {
+			return false
+		}
+	}
+	if !inTimeRange(task.CreatedAt, filters.CreatedAfter, filters.CreatedBefore) ||
+		!inTimeRange(task.UpdatedAt, filters.UpdatedAfter, filters.UpdatedBefore) {
+		return false
+	}
+	if filters.After != nil && !sortsAfter(task, filters.After) {
+		return false
+	}
+	return true
+}
+
+// inTimeRange checks the half-open range [after, before); zero bounds are open
+func inTimeRange(t, after, before time.Time) bool {
+	if !after.IsZero() && t.Before(after) {
+		return false
+	}
+	if !before.IsZero() && !t.Before(before) {
+		return false
+	}
+	return true
+}
+
+// sortsAfter reports whether task comes after the cursor in list order
+func sortsAfter(task *models.Task, cursor *storage.TaskCursor) bool {
+	if task.Starred != cursor.Starred {
+		return cursor.Starred
+	}
+	if !task.CreatedAt.Equal(cursor.CreatedAt) {
+		return task.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return task.ID < cursor.ID
+}
+
+// sortNewestFirst orders tasks by created_at, then id, both descending
+func sortNewestFirst(tasks []*models.Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.After(tasks[j].CreatedAt)
+		}
+		return tasks[i].ID > tasks[j].ID
+	})
+}
+
+// sortListOrder orders tasks like ListTasks: starred first, then newest first
+func sortListOrder(tasks []*models.Task) {
+	sortNewestFirst(tasks)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Starred && !tasks[j].Starred
+	})
+}
+
+// SearchTasks matches case-insensitively, like SQLite's LIKE for ASCII text
+func (s *Storage) SearchTasks(ctx context.Context, query string, includeArchived, includeComments bool, limit int) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	needle := strings.ToLower(query)
+	fieldMatches := func(task *models.Task) bool {
+		tagsJSON, _ := json.Marshal(task.Tags)
+		return strings.Contains(strings.ToLower(task.Title), needle) ||
+			strings.Contains(strings.ToLower(task.JiraID), needle) ||
+			strings.Contains(strings.ToLower(string(tagsJSON)), needle)
+	}
+
+	var commentMatches map[string]bool
+	if includeComments {
+		commentMatches = make(map[string]bool)
+		for _, comment := range s.comments {
+			if strings.Contains(strings.ToLower(comment.Content), needle) {
+				commentMatches[comment.TaskID] = true
+			}
+		}
+	}
+
+	var fieldHits, commentHits []*models.Task
+	for _, task := range s.tasks {
+		if !includeArchived && task.Status == models.Archived {
+			continue
+		}
+		switch {
+		case fieldMatches(task):
+			fieldHits = append(fieldHits, task)
+		case commentMatches[task.ID]:
+			commentHits = append(commentHits, task)
+		}
+	}
+	sortNewestFirst(fieldHits)
+	sortNewestFirst(commentHits)
+
+	tasks := append(fieldHits, commentHits...)
+	if limit > 0 && len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return copyTasks(tasks), nil
+}
+
+func (s *Storage) GetRelatedTasks(ctx context.Context, taskID string, limit int) ([]*models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	source, ok := s.tasks[taskID]
+	if !ok {
+		return nil, nil
+	}
+
+	shared := make(map[string]int)
+	var tasks []*models.Task
+	for _, task := range s.tasks {
+		if task.ID == taskID || task.Status == models.Archived {
+			continue
+		}
+		count := 0
+		for _, tag := range uniqueStrings(task.Tags) {
+			if slices.Contains(source.Tags, tag) {
+				count++
+			}
+		}
+		if count > 0 {
+			shared[task.ID] = count
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if shared[a.ID] != shared[b.ID] {
+			return shared[a.ID] > shared[b.ID]
+		}
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.After(b.UpdatedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	if limit > 0 && len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return copyTasks(tasks), nil
+}
+
+func (s *Storage) CountTaskRelations(ctx context.Context, taskIDs []string) (map[string]storage.TaskRelationCounts, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]storage.TaskRelationCounts, len(taskIDs))
+	for _, id := range taskIDs {
+		if _, ok := s.tasks[id]; ok {
+			counts[id] = storage.TaskRelationCounts{}
+		}
+	}
+	for _, link := range s.links {
+		if c, ok := counts[link.TaskID]; ok {
+			c.Links++
+			counts[link.TaskID] = c
+		}
+	}
+	for _, comment := range s.comments {
+		if c, ok := counts[comment.TaskID]; ok {
+			c.Comments++
+			counts[comment.TaskID] = c
+		}
+	}
+	return counts, nil
+}
+
+func (s *Storage) MergeTasks(ctx context.Context, sourceID, targetID string, opts storage.MergeOptions) (*models.Task, error) {
+	if sourceID == targetID {
+		return nil, &models.ValidationError{Field: "source", Code: models.CodeNotAllowed, Message: "cannot merge a task into itself"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, ok := s.tasks[sourceID]
+	if !ok {
+		return nil, fmt.Errorf("task %w", storage.ErrNotFound)
+	}
+	stored, ok := s.tasks[targetID]
+	if !ok {
+		return nil, fmt.Errorf("task %w", storage.ErrNotFound)
+	}
+	target := copyTask(stored)
+
+	previousStatus := target.Status
+	if opts.Prefer == storage.PreferSource {
+		target.JiraID = source.JiraID
+		target.Title = source.Title
+		target.Priority = source.Priority
+		target.Status = source.Status
+	}
+	target.Tags = unionStrings(target.Tags, source.Tags)
+	target.Blockers = unionStrings(target.Blockers, source.Blockers)
+	target.Starred = target.Starred || source.Starred
+	target.CustomFields = storage.MergeCustomFields(target.CustomFields, source.CustomFields, opts.Prefer)
+
+	// The target takes on the source's status like an update would, so the
+	// same status checks apply
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	if err := storage.CheckTransition(ctx, previousStatus, target.Status); err != nil {
+		return nil, err
+	}
+	if err := target.ValidateBlockedChange(previousStatus); err != nil {
+		return nil, err
+	}
+
+	for _, link := range s.links {
+		if link.TaskID == sourceID {
+			link.TaskID = targetID
+		}
+	}
+	for _, comment := range s.comments {
+		if comment.TaskID == sourceID {
+			comment.TaskID = targetID
+		}
+	}
+
+	now := time.Now()
+	target.UpdatedAt = now
+	s.tasks[targetID] = copyTask(target)
+
+	if opts.DeleteSource {
+		s.deleteTask(sourceID)
+	} else {
+		source.Status = models.Archived
+		source.UpdatedAt = now
+	}
+
+	return target, nil
+}
+
+// Link operations
+func (s *Storage) CreateLink(ctx context.Context, link *models.Link) error {
+	if err := link.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[link.TaskID]; !ok {
+		return fmt.Errorf("task %w", storage.ErrNotFound)
+	}
+	if link.ID == "" {
+		link.ID = uuid.New().String()
+	}
+	if s.findLink(link.ID) >= 0 {
+		return fmt.Errorf("link %s already exists", link.ID)
+	}
+
+	s.links = append(s.links, copyLink(link))
+	s.recordLinkAdded(link)
+	return nil
+}
+
+func (s *Storage) recordLinkAdded(link *models.Link) {
+	s.recordActivity(link.TaskID, models.ActivityLinkAdded, map[string]interface{}{
+		"link_id": link.ID,
+		"type":    link.Type,
+		"url":     link.URL,
+	})
+}
+
+func (s *Storage) GetLink(ctx context.Context, id string) (*models.Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := s.findLink(id)
+	if i < 0 {
+		return nil, fmt.Errorf("link %w", storage.ErrNotFound)
+	}
+	return copyLink(s.links[i]), nil
+}
+
+// findLink returns the index of the link with the given ID, or -1. Callers hold the lock.
+func (s *Storage) findLink(id string) int {
+	return slices.IndexFunc(s.links, func(link *models.Link) bool {
+		return link.ID == id
+	})
+}
+
+func (s *Storage) UpdateLink(ctx context.Context, link *models.Link) error {
+	return s.UpdateLinkWithOptions(ctx, link, storage.UpdateLinkOptions{})
+}
+
+func (s *Storage) UpdateLinkWithOptions(ctx context.Context, link *models.Link, opts storage.UpdateLinkOptions) error {
+	if err := link.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.findLink(link.ID)
+	if i < 0 {
+		return fmt.Errorf("link %w", storage.ErrNotFound)
+	}
+	if _, ok := s.tasks[link.TaskID]; !ok {
+		return fmt.Errorf("task %w", storage.ErrNotFound)
+	}
+	if opts.MergeMetadata {
+		merged, err := mergeMetadata(s.links[i].Metadata, link.Metadata)
+		if err != nil {
+			return err
+		}
+		link.Metadata = merged
+	}
+	s.links[i] = copyLink(link)
+	return nil
+}
+
+func (s *Storage) DeleteLink(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.links = slices.DeleteFunc(s.links, func(link *models.Link) bool {
+		return link.ID == id
+	})
+	return nil
+}
+
+func (s *Storage) GetTaskLinks(ctx context.Context, taskID string) ([]*models.Link, error) {
+	return s.GetTaskLinksByType(ctx, taskID)
+}
+
+func (s *Storage) GetTaskLinksByType(ctx context.Context, taskID string, types ...models.LinkType) ([]*models.Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var links []*models.Link
+	for _, link := range s.links {
+		if link.TaskID != taskID {
+			continue
+		}
+		if len(types) > 0 && !slices.Contains(types, link.Type) {
+			continue
+		}
+		links = append(links, copyLink(link))
+	}
+	return links, nil
+}
+
+// Comment operations
+func (s *Storage) CreateComment(ctx context.Context, comment *models.Comment) error {
+	if err := comment.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[comment.TaskID]; !ok {
+		return fmt.Errorf("task %w", storage.ErrNotFound)
+	}
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+	if _, exists := s.comments[comment.ID]; exists {
+		return fmt.Errorf("comment %s already exists", comment.ID)
+	}
+	comment.CreatedAt = time.Now()
+
+	stored := *comment
+	s.comments[comment.ID] = &stored
+	s.recordActivity(comment.TaskID, models.ActivityCommentAdded, map[string]interface{}{
+		"comment_id": comment.ID,
+	})
+	return nil
+}
+
+// CreateComments validates every comment before storing any of them
+func (s *Storage) CreateComments(ctx context.Context, comments []*models.Comment) error {
+	for i, comment := range comments {
+		if err := comment.Validate(); err != nil {
+			var validationErr *models.ValidationError
+			if errors.As(err, &validationErr) {
+				return &models.ValidationError{
+					Field:   fmt.Sprintf("comments[%d].%s", i, validationErr.Field),
+					Code:    validationErr.Code,
+					Message: validationErr.Message,
+				}
+			}
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, comment := range comments {
+		if _, ok := s.tasks[comment.TaskID]; !ok {
+			return fmt.Errorf("task %w", storage.ErrNotFound)
+		}
+		if _, exists := s.comments[comment.ID]; exists && comment.ID != "" {
+			return fmt.Errorf("comment %s already exists", comment.ID)
+		}
+	}
+
+	now := time.Now()
+	for i, comment := range comments {
+		if comment.ID == "" {
+			comment.ID = uuid.New().String()
+		}
+		if comment.CreatedAt.IsZero() {
+			comment.CreatedAt = now.Add(time.Duration(i))
+		}
+		stored := *comment
+		s.comments[comment.ID] = &stored
+		s.recordActivity(comment.TaskID, models.ActivityCommentAdded, map[string]interface{}{
+			"comment_id": comment.ID,
+		})
+	}
+	return nil
+}
+
+func (s *Storage) GetComment(ctx context.Context, id string) (*models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comment, ok := s.comments[id]
+	if !ok {
+		return nil, fmt.Errorf("comment %w", storage.ErrNotFound)
+	}
+	found := *comment
+	return &found, nil
+}
+
+func (s *Storage) DeleteComment(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.comments, id)
+	return nil
+}
+
+func (s *Storage) GetTaskComments(ctx context.Context, taskID string) ([]*models.Comment, error) {
+	comments, _, err := s.GetTaskCommentsPaged(ctx, taskID, 0, 0)
+	return comments, err
+}
+
+func (s *Storage) GetTaskCommentsPaged(ctx context.Context, taskID string, limit, offset int) ([]*models.Comment, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var comments []*models.Comment
+	for _, comment := range s.comments {
+		if comment.TaskID == taskID {
+			found := *comment
+			comments = append(comments, &found)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+
+	total := len(comments)
+	if offset > 0 {
+		if offset >= len(comments) {
+			comments = nil
+		} else {
+			comments = comments[offset:]
+		}
+	}
+	if limit > 0 && len(comments) > limit {
+		comments = comments[:limit]
+	}
+	return comments, total, nil
+}
+
+func (s *Storage) DistinctValues(ctx context.Context, field, prefix string, limit int) ([]string, error) {
+	if err := storage.CheckDistinctField(field); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	counts := make(map[string]int)
+	prefix = strings.ToLower(prefix)
+	for _, task := range s.tasks {
+		value := task.JiraID
+		if models.IsNoJira(value) || !strings.HasPrefix(strings.ToLower(value), prefix) {
+			continue
+		}
+		counts[value]++
+	}
+	s.mu.RUnlock()
+
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+	return values, nil
+}
+
+// Tag operations
+func (s *Storage) ListTags(ctx context.Context) (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tags := make(map[string]int)
+	for _, task := range s.tasks {
+		if task.Status == models.Archived {
+			continue
+		}
+		for _, tag := range task.Tags {
+			tags[tag]++
+		}
+	}
+	return tags, nil
+}
+
+func (s *Storage) RenameTag(ctx context.Context, from, to string) (int, error) {
+	from, to, err := storage.NormalizeTagRename(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	renamed := 0
+	for _, task := range s.tasks {
+		if !slices.Contains(task.Tags, from) {
+			continue
+		}
+		for i, tag := range task.Tags {
+			if tag == from {
+				task.Tags[i] = to
+			}
+		}
+		task.Tags = uniqueStrings(task.Tags)
+		task.UpdatedAt = now
+		renamed++
+	}
+	return renamed, nil
+}
+
+// Activity operations
+
+// recordActivity appends an entry to a task's timeline. Callers hold the lock.
+func (s *Storage) recordActivity(taskID string, activityType models.ActivityType, payload interface{}) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("failed to marshal %s activity for task %s: %v", activityType, taskID, err)
+		return
+	}
+
+	s.nextActivityID++
+	s.activity = append(s.activity, models.Activity{
+		ID:        s.nextActivityID,
+		TaskID:    taskID,
+		Type:      activityType,
+		Timestamp: time.Now(),
+		Payload:   payloadJSON,
+	})
+}
+
+func (s *Storage) GetTaskActivity(ctx context.Context, taskID string) ([]models.Activity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Entries are appended in order, so the slice is already oldest first
+	var activities []models.Activity
+	for _, activity := range s.activity {
+		if activity.TaskID == taskID {
+			activities = append(activities, activity)
+		}
+	}
+	return activities, nil
+}
+
+// StreamLinks copies the links of existing tasks under the lock and calls fn
+// once it is released, so fn may take its time
+func (s *Storage) StreamLinks(ctx context.Context, fn func(*models.Link) error) error {
+	s.mu.RLock()
+	var links []*models.Link
+	for _, link := range s.links {
+		if _, ok := s.tasks[link.TaskID]; ok {
+			links = append(links, copyLink(link))
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].TaskID != links[j].TaskID {
+			return links[i].TaskID < links[j].TaskID
+		}
+		return links[i].ID < links[j].ID
+	})
+	for _, link := range links {
+		if err := fn(link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamComments copies the comments of existing tasks like StreamLinks
+func (s *Storage) StreamComments(ctx context.Context, fn func(*models.Comment) error) error {
+	s.mu.RLock()
+	var comments []*models.Comment
+	for _, comment := range s.comments {
+		if _, ok := s.tasks[comment.TaskID]; ok {
+			stored := *comment
+			comments = append(comments, &stored)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(comments, func(i, j int) bool {
+		if comments[i].TaskID != comments[j].TaskID {
+			return comments[i].TaskID < comments[j].TaskID
+		}
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+	for _, comment := range comments {
+		if err := fn(comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportData stores exported records, keeping their IDs and timestamps and
+// skipping IDs that already exist. Everything is validated before anything is
+// written, so a failed import changes nothing.
+func (s *Storage) ImportData(ctx context.Context, data *models.ExportData) (*models.ImportResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, task := range data.Tasks {
+		if err := task.Validate(); err != nil {
+			return nil, err
+		}
+		if task.ID == "" {
+			task.ID = uuid.New().String()
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		if task.UpdatedAt.IsZero() {
+			task.UpdatedAt = task.CreatedAt
+		}
+	}
+	for _, link := range data.Links {
+		if err := link.Validate(); err != nil {
+			return nil, err
+		}
+		if link.ID == "" {
+			link.ID = uuid.New().String()
+		}
+	}
+	for _, comment := range data.Comments {
+		if err := comment.Validate(); err != nil {
+			return nil, err
+		}
+		if comment.ID == "" {
+			comment.ID = uuid.New().String()
+		}
+		if comment.CreatedAt.IsZero() {
+			comment.CreatedAt = now
+		}
+	}
+
+	// Links and comments must point at a task that exists or is being imported
+	taskExists := func(id string) bool {
+		if _, ok := s.tasks[id]; ok {
+			return true
+		}
+		return slices.ContainsFunc(data.Tasks, func(task *models.Task) bool { return task.ID == id })
+	}
+	for _, link := range data.Links {
+		if s.findLink(link.ID) < 0 && !taskExists(link.TaskID) {
+			return nil, fmt.Errorf("failed to import link %s: task %w", link.ID, storage.ErrNotFound)
+		}
+	}
+	for _, comment := range data.Comments {
+		if _, exists := s.comments[comment.ID]; !exists && !taskExists(comment.TaskID) {
+			return nil, fmt.Errorf("failed to import comment %s: task %w", comment.ID, storage.ErrNotFound)
+		}
+	}
+
+	result := &models.ImportResult{}
+	for _, task := range data.Tasks {
+		if _, exists := s.tasks[task.ID]; exists {
+			result.TasksSkipped++
+			continue
+		}
+		s.tasks[task.ID] = copyTask(task)
+		result.TasksImported++
+	}
+	for _, link := range data.Links {
+		if s.findLink(link.ID) >= 0 {
+			result.LinksSkipped++
+			continue
+		}
+		s.links = append(s.links, copyLink(link))
+		result.LinksImported++
+	}
+	for _, comment := range data.Comments {
+		if _, exists := s.comments[comment.ID]; exists {
+			result.CommentsSkipped++
+			continue
+		}
+		stored := *comment
+		s.comments[comment.ID] = &stored
+		result.CommentsImported++
+	}
+	return result, nil
+}
+
+// WithTransaction runs fn against a copy of the store and keeps the copy
+// only if fn succeeds. Other callers wait until fn returns, so fn must use tx
+// rather than s.
+func (s *Storage) WithTransaction(ctx context.Context, fn func(tx storage.Storage) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := s.clone()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.tasks, s.links, s.comments = tx.tasks, tx.links, tx.comments
+	s.activity, s.nextActivityID = tx.activity, tx.nextActivityID
+	return nil
+}
+
+// clone deep-copies the stored data; the caller holds the lock
+func (s *Storage) clone() *Storage {
+	c := New()
+	for id, task := range s.tasks {
+		c.tasks[id] = copyTask(task)
+	}
+	c.links = make([]*models.Link, len(s.links))
+	for i, link := range s.links {
+		c.links[i] = copyLink(link)
+	}
+	for id, comment := range s.comments {
+		stored := *comment
+		c.comments[id] = &stored
+	}
+	c.activity = slices.Clone(s.activity)
+	c.nextActivityID = s.nextActivityID
+	return c
+}
+
+func copyTask(task *models.Task) *models.Task {
+	c := *task
+	c.Tags = slices.Clone(task.Tags)
+	c.Blockers = slices.Clone(task.Blockers)
+	c.CustomFields = maps.Clone(task.CustomFields)
+	return &c
+}
+
+func copyTasks(tasks []*models.Task) []*models.Task {
+	if tasks == nil {
+		return nil
+	}
+	copies := make([]*models.Task, len(tasks))
+	for i, task := range tasks {
+		copies[i] = copyTask(task)
+	}
+	return copies
+}
+
+func copyLink(link *models.Link) *models.Link {
+	c := *link
+	return &c
+}
+
+// mergeMetadata applies patch to base as a JSON merge patch (RFC 7396), the
+// same semantics as SQLite's json_patch
+func mergeMetadata(base, patch string) (string, error) {
+	var baseValue, patchValue any
+	if err := json.Unmarshal([]byte(patch), &patchValue); err != nil {
+		return "", fmt.Errorf("failed to unmarshal metadata patch: %w", err)
+	}
+	// Stored metadata that isn't valid JSON is simply replaced
+	_ = json.Unmarshal([]byte(base), &baseValue)
+
+	merged, err := json.Marshal(mergePatch(baseValue, patchValue))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	return string(merged), nil
+}
+
+func mergePatch(base, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	baseObject, ok := base.(map[string]any)
+	if !ok {
+		baseObject = make(map[string]any)
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(baseObject, key)
+			continue
+		}
+		baseObject[key] = mergePatch(baseObject[key], value)
+	}
+	return baseObject
+}
+
+// unionStrings appends the values of b missing from a, preserving order
+func unionStrings(a, b []string) []string {
+	return uniqueStrings(append(slices.Clone(a), b...))
+}
+
+// uniqueStrings drops repeated values, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
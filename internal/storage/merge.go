@@ -0,0 +1,22 @@
+package storage
+
+import "maps"
+
+// MergeCustomFields combines the custom fields of a merge. Keys only one task
+// has are kept; when both have a key, prefer decides whose value wins.
+func MergeCustomFields(target, source map[string]string, prefer MergePreference) map[string]string {
+	if len(target) == 0 && len(source) == 0 {
+		return nil
+	}
+	merged := maps.Clone(source)
+	if merged == nil {
+		merged = make(map[string]string, len(target))
+	}
+	for key, value := range target {
+		if _, conflict := source[key]; conflict && prefer == PreferSource {
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
@@ -4,10 +4,35 @@ package sqlite
 
 import (
 	"database/sql"
-	_ "github.com/mattn/go-sqlite3" // CGO SQLite driver
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/mattn/go-sqlite3" // CGO SQLite driver
 )
 
 // openDB opens a SQLite database using the CGO driver
 func openDB(dataSourceName string) (*sql.DB, error) {
 	return sql.Open("sqlite3", dataSourceName)
-}
\ No newline at end of file
+}
+
+// dataSourceName builds a go-sqlite3 DSN, which applies the pragmas on every new connection.
+// _pragma_key is read by SQLCipher builds of the driver (such as go-sqlcipher)
+// and ignored by plain ones, which New detects.
+func dataSourceName(dbPath string, o options) string {
+	var key string
+	if o.encryptionKey != "" {
+		key = "_pragma_key=" + url.QueryEscape(quoteKey(o.encryptionKey)) + "&"
+	}
+	return fmt.Sprintf("%s?%s_foreign_keys=on&_journal_mode=%s&_busy_timeout=%d&_synchronous=%s",
+		dbPath, key, o.journalMode, o.busyTimeout.Milliseconds(), o.synchronous)
+}
+
+// isBusyError reports whether err means another connection holds a conflicting lock
+func isBusyError(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
@@ -4,10 +4,39 @@ package sqlite
 
 import (
 	"database/sql"
-	_ "modernc.org/sqlite" // Pure Go SQLite driver
+	"errors"
+	"fmt"
+	"net/url"
+
+	"modernc.org/sqlite" // Pure Go SQLite driver
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 // openDB opens a SQLite database using the pure Go driver
 func openDB(dataSourceName string) (*sql.DB, error) {
 	return sql.Open("sqlite", dataSourceName)
-}
\ No newline at end of file
+}
+
+// dataSourceName builds a modernc.org/sqlite DSN, which applies the pragmas on every new connection.
+// _time_format=sqlite stores timestamps in the same layout as go-sqlite3 so date functions can parse them.
+// The encryption key pragma runs first; plain SQLite ignores it, which New detects.
+func dataSourceName(dbPath string, o options) string {
+	var key string
+	if o.encryptionKey != "" {
+		key = "&_pragma=" + url.QueryEscape("key("+quoteKey(o.encryptionKey)+")")
+	}
+	return fmt.Sprintf("%s?_time_format=sqlite%s&_pragma=foreign_keys(1)&_pragma=journal_mode(%s)&_pragma=busy_timeout(%d)&_pragma=synchronous(%s)",
+		dbPath, key, o.journalMode, o.busyTimeout.Milliseconds(), o.synchronous)
+}
+
+// isBusyError reports whether err means another connection holds a conflicting lock
+func isBusyError(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	// The low byte is the primary result code; extended codes such as
+	// SQLITE_BUSY_SNAPSHOT share it
+	code := sqliteErr.Code() & 0xff
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
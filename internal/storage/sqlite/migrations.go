@@ -67,6 +67,94 @@ var migrations = []Migration{
 			CREATE INDEX IF NOT EXISTS idx_comments_created_at ON comments(created_at);
 		`,
 	},
+	{
+		Version: 4,
+		SQL: `
+			CREATE TABLE IF NOT EXISTS activity (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				task_id TEXT NOT NULL,
+				type TEXT NOT NULL,
+				timestamp DATETIME NOT NULL,
+				payload TEXT NOT NULL DEFAULT '{}',
+				FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_activity_task_id ON activity(task_id, timestamp);
+		`,
+	},
+	{
+		// SQLite can't alter a CHECK constraint, so the links table is rebuilt
+		// to accept the commit, design_doc and incident types. Nothing
+		// references links, so dropping the old table is safe with foreign
+		// keys on.
+		Version: 5,
+		SQL: `
+			CREATE TABLE links_new (
+				id TEXT PRIMARY KEY,
+				task_id TEXT NOT NULL,
+				type TEXT NOT NULL CHECK (type IN ('pull_request', 'slack_thread', 'jira_ticket', 'documentation', 'commit', 'design_doc', 'incident', 'other')),
+				url TEXT NOT NULL,
+				title TEXT NOT NULL,
+				status TEXT NOT NULL DEFAULT '',
+				metadata TEXT NOT NULL DEFAULT '{}',
+				FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+			);
+
+			INSERT INTO links_new (id, task_id, type, url, title, status, metadata)
+			SELECT id, task_id, type, url, title, status, metadata FROM links;
+
+			DROP TABLE links;
+			ALTER TABLE links_new RENAME TO links;
+
+			CREATE INDEX IF NOT EXISTS idx_links_task_id ON links(task_id);
+			CREATE INDEX IF NOT EXISTS idx_links_type ON links(type);
+		`,
+	},
+	{
+		// Starred tasks are listed first, so the list index leads with the flag
+		Version: 6,
+		SQL: `
+			ALTER TABLE tasks ADD COLUMN starred INTEGER NOT NULL DEFAULT 0;
+
+			CREATE INDEX IF NOT EXISTS idx_tasks_starred_created_at ON tasks(starred, created_at);
+		`,
+	},
+	{
+		// Free-form key/value pairs, kept as a JSON object like link metadata
+		Version: 7,
+		SQL: `
+			ALTER TABLE tasks ADD COLUMN custom_fields TEXT NOT NULL DEFAULT '{}';
+		`,
+	},
+	{
+		// The pure Go driver used to store times as time.Time.String(), e.g.
+		// "2024-01-02 03:04:05.5 +0200 CEST m=+0.01". Rewrite those values to
+		// the "2024-01-02 03:04:05.5+02:00" layout both drivers now share so
+		// julianday() and cursor comparisons see every row. Values already
+		// in the new layout don't match the GLOB and are left untouched.
+		Version: 8,
+		SQL: `
+			UPDATE tasks SET created_at = ` + rewriteStringTimestamp("created_at") + `
+			WHERE created_at GLOB '* [+-][0-9][0-9][0-9][0-9] *';
+			UPDATE tasks SET updated_at = ` + rewriteStringTimestamp("updated_at") + `
+			WHERE updated_at GLOB '* [+-][0-9][0-9][0-9][0-9] *';
+			UPDATE comments SET created_at = ` + rewriteStringTimestamp("created_at") + `
+			WHERE created_at GLOB '* [+-][0-9][0-9][0-9][0-9] *';
+			UPDATE activity SET timestamp = ` + rewriteStringTimestamp("timestamp") + `
+			WHERE timestamp GLOB '* [+-][0-9][0-9][0-9][0-9] *';
+		`,
+	},
+}
+
+// rewriteStringTimestamp returns an SQL expression turning a column holding
+// "YYYY-MM-DD hh:mm:ss[.f] +hhmm ZONE[ m=...]" into
+// "YYYY-MM-DD hh:mm:ss[.f]+hh:mm". The space after the clock starts the
+// offset, so everything past it but the offset digits is dropped.
+func rewriteStringTimestamp(column string) string {
+	space := "instr(substr(" + column + ", 12), ' ')"
+	return "substr(" + column + ", 1, " + space + " + 10)" +
+		" || substr(" + column + ", " + space + " + 12, 3)" +
+		" || ':' || substr(" + column + ", " + space + " + 15, 2)"
 }
 
 func runMigrations(db *sql.DB) error {
@@ -105,6 +193,15 @@ func createMigrationsTable(db *sql.DB) error {
 	return err
 }
 
+// latestMigrationVersion is the schema version once every migration is applied
+func latestMigrationVersion() int {
+	latest := 0
+	for _, migration := range migrations {
+		latest = max(latest, migration.Version)
+	}
+	return latest
+}
+
 func getCurrentVersion(db *sql.DB) (int, error) {
 	var version int
 	err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
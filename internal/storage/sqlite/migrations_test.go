@@ -46,7 +46,7 @@ func TestRunMigrations_EmptyDatabase(t *testing.T) {
 	require.NoError(t, err)
 	
 	// Check that all tables exist
-	tables := []string{"tasks", "links", "comments", "schema_migrations"}
+	tables := []string{"tasks", "links", "comments", "activity", "schema_migrations"}
 	for _, table := range tables {
 		var exists bool
 		err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type='table' AND name=?)", table).Scan(&exists)
@@ -81,7 +81,7 @@ func TestRunMigrations_SchemaVersioning(t *testing.T) {
 	}
 	
 	// Should have all migration versions
-	expectedVersions := []int{1, 2, 3}
+	expectedVersions := []int{1, 2, 3, 4, 5, 6, 7, 8}
 	assert.Equal(t, expectedVersions, versions)
 }
 
@@ -100,7 +100,7 @@ func TestRunMigrations_Idempotent(t *testing.T) {
 	var count int
 	err = db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count)
 	require.NoError(t, err)
-	assert.Equal(t, 3, count) // Should still only have 3 versions
+	assert.Equal(t, 8, count) // Should still only have 8 versions
 }
 
 func TestRunMigrations_ForeignKeys(t *testing.T) {
@@ -170,6 +170,46 @@ func TestRunMigrations_CascadeDelete(t *testing.T) {
 	assert.Equal(t, 0, commentCount)
 }
 
+func TestRunMigrations_LinkTypeCheck(t *testing.T) {
+	db, cleanup := setupTestMigrationDB(t)
+	defer cleanup()
+
+	// Apply the schema up to version 4 and store a link under the old constraint
+	require.NoError(t, createMigrationsTable(db))
+	for _, migration := range migrations[:4] {
+		require.NoError(t, applyMigration(db, migration))
+	}
+	_, err := db.Exec("INSERT INTO tasks (id, jira_id, title, priority, status, tags, blockers, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))", "task-1", "TEST-1", "Test Task", "normal", "new", "[]", "[]")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO links (id, task_id, type, url, title, status, metadata) VALUES (?, ?, ?, ?, ?, ?, ?)", "link-old", "task-1", "pull_request", "http://test.com/1", "Existing", "open", "{}")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO links (id, task_id, type, url, title, status, metadata) VALUES (?, ?, ?, ?, ?, ?, ?)", "link-commit", "task-1", "commit", "http://test.com/c", "Commit", "", "{}")
+	require.Error(t, err, "commit is rejected before migration 5")
+
+	require.NoError(t, runMigrations(db))
+
+	// Existing links survive the table rebuild
+	var title string
+	require.NoError(t, db.QueryRow("SELECT title FROM links WHERE id = ?", "link-old").Scan(&title))
+	assert.Equal(t, "Existing", title)
+
+	for _, linkType := range []string{"commit", "design_doc", "incident"} {
+		_, err := db.Exec("INSERT INTO links (id, task_id, type, url, title, status, metadata) VALUES (?, ?, ?, ?, ?, ?, ?)", "link-"+linkType, "task-1", linkType, "http://test.com/"+linkType, linkType, "", "{}")
+		assert.NoError(t, err, "type %s should be accepted", linkType)
+	}
+
+	_, err = db.Exec("INSERT INTO links (id, task_id, type, url, title, status, metadata) VALUES (?, ?, ?, ?, ?, ?, ?)", "link-bogus", "task-1", "bogus", "http://test.com/b", "Bogus", "", "{}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CHECK constraint failed")
+
+	// The rebuilt table keeps its cascade
+	_, err = db.Exec("DELETE FROM tasks WHERE id = ?", "task-1")
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM links").Scan(&count))
+	assert.Zero(t, count)
+}
+
 func TestRunMigrations_Indexes(t *testing.T) {
 	db, cleanup := setupTestMigrationDB(t)
 	defer cleanup()
@@ -198,6 +238,56 @@ func TestRunMigrations_Indexes(t *testing.T) {
 	}
 }
 
+func TestRunMigrations_RewritesStringTimestamps(t *testing.T) {
+	db, cleanup := setupTestMigrationDB(t)
+	defer cleanup()
+
+	// Bring the schema to version 7, before timestamps were rewritten
+	require.NoError(t, createMigrationsTable(db))
+	for _, migration := range migrations {
+		if migration.Version > 7 {
+			break
+		}
+		require.NoError(t, applyMigration(db, migration))
+	}
+
+	// Rows written by the pure Go driver before it used the sqlite layout,
+	// next to one already in the new layout
+	_, err := db.Exec(`INSERT INTO tasks (id, jira_id, title, status, priority, created_at, updated_at) VALUES
+		('old', '', 'Old', 'new', 'normal', '2024-01-02 03:04:05.123 +0200 CEST m=+0.004', '2024-01-02 03:04:06 +0000 UTC'),
+		('current', '', 'Current', 'new', 'normal', '2024-01-02 03:04:05.5+00:00', '2024-01-02 03:04:05.5+00:00')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO comments (id, task_id, content, created_at) VALUES
+		('c1', 'old', 'Hi', '2024-01-02 03:04:07.25 -0500 EST')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO activity (task_id, type, timestamp) VALUES
+		('old', 'created', '2024-01-02 03:04:05.123 +0200 CEST m=+0.004')`)
+	require.NoError(t, err)
+
+	require.NoError(t, runMigrations(db))
+
+	// CAST reads the stored text instead of a driver-parsed time
+	var createdAt, updatedAt string
+	require.NoError(t, db.QueryRow("SELECT CAST(created_at AS TEXT), CAST(updated_at AS TEXT) FROM tasks WHERE id = 'old'").Scan(&createdAt, &updatedAt))
+	assert.Equal(t, "2024-01-02 03:04:05.123+02:00", createdAt)
+	assert.Equal(t, "2024-01-02 03:04:06+00:00", updatedAt)
+
+	require.NoError(t, db.QueryRow("SELECT CAST(created_at AS TEXT) FROM tasks WHERE id = 'current'").Scan(&createdAt))
+	assert.Equal(t, "2024-01-02 03:04:05.5+00:00", createdAt)
+
+	require.NoError(t, db.QueryRow("SELECT CAST(created_at AS TEXT) FROM comments WHERE id = 'c1'").Scan(&createdAt))
+	assert.Equal(t, "2024-01-02 03:04:07.25-05:00", createdAt)
+
+	var timestamp string
+	require.NoError(t, db.QueryRow("SELECT CAST(timestamp AS TEXT) FROM activity WHERE task_id = 'old'").Scan(&timestamp))
+	assert.Equal(t, "2024-01-02 03:04:05.123+02:00", timestamp)
+
+	// The rewritten values are ones SQLite's date functions understand
+	var unparsed int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM tasks WHERE julianday(created_at) IS NULL OR julianday(updated_at) IS NULL").Scan(&unparsed))
+	assert.Zero(t, unparsed)
+}
+
 func TestGetCurrentVersion(t *testing.T) {
 	db, cleanup := setupTestMigrationDB(t)
 	defer cleanup()
@@ -1,10 +1,15 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"michishirube/internal/models"
@@ -13,17 +18,170 @@ import (
 	"github.com/google/uuid"
 )
 
+// pingTimeout bounds how long a readiness ping may block
+const pingTimeout = 2 * time.Second
+
 type SQLiteStorage struct {
 	db *sql.DB
+	tx *sql.Tx // Set on the storage handed to a WithTransaction callback
+
+	retryAttempts  int
+	retryBaseDelay time.Duration
+}
+
+// options holds the connection settings encoded into the DSN and the pool limits
+type options struct {
+	// journalMode WAL lets readers proceed while a writer commits, at the cost
+	// of -wal/-shm files next to the database that must travel with it.
+	journalMode string
+	// busyTimeout is how long a connection waits on a lock before failing
+	// with "database is locked"; longer values trade latency for fewer errors.
+	busyTimeout time.Duration
+	// synchronous NORMAL skips the fsync on every WAL commit. It is safe from
+	// corruption, but the last transactions may be lost on power failure.
+	synchronous string
+
+	// SQLite allows a single writer at a time, so a small pool is enough: WAL
+	// readers run in parallel and extra writers would only queue on the lock.
+	maxOpenConns    int
+	maxIdleConns    int
+	connMaxLifetime time.Duration
+
+	// Writes that still hit a lock after busyTimeout are retried this many
+	// times in total, doubling the delay from retryBaseDelay between tries.
+	retryAttempts  int
+	retryBaseDelay time.Duration
+
+	// encryptionKey is the SQLCipher passphrase applied to every connection.
+	// It only takes effect with a SQLCipher-enabled driver; see New.
+	encryptionKey string
+}
+
+// Option tunes how New opens the database
+type Option func(*options)
+
+// WithJournalMode sets PRAGMA journal_mode (e.g. WAL, DELETE). Empty keeps the default.
+func WithJournalMode(mode string) Option {
+	return func(o *options) {
+		if mode != "" {
+			o.journalMode = strings.ToUpper(mode)
+		}
+	}
+}
+
+// WithBusyTimeout sets how long to wait on a locked database. Negative keeps the default.
+func WithBusyTimeout(d time.Duration) Option {
+	return func(o *options) {
+		if d >= 0 {
+			o.busyTimeout = d
+		}
+	}
+}
+
+// WithSynchronous sets PRAGMA synchronous (e.g. NORMAL, FULL). Empty keeps the default.
+func WithSynchronous(level string) Option {
+	return func(o *options) {
+		if level != "" {
+			o.synchronous = strings.ToUpper(level)
+		}
+	}
+}
+
+// WithMaxOpenConns caps the number of open connections. Non-positive keeps the default.
+func WithMaxOpenConns(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxOpenConns = n
+		}
+	}
+}
+
+// WithMaxIdleConns sets how many connections are kept open while idle. Negative keeps the default.
+func WithMaxIdleConns(n int) Option {
+	return func(o *options) {
+		if n >= 0 {
+			o.maxIdleConns = n
+		}
+	}
+}
+
+// WithConnMaxLifetime sets how long a connection may be reused (0 means forever). Negative keeps the default.
+func WithConnMaxLifetime(d time.Duration) Option {
+	return func(o *options) {
+		if d >= 0 {
+			o.connMaxLifetime = d
+		}
+	}
 }
 
-func New(dbPath string) (*SQLiteStorage, error) {
-	db, err := openDB(dbPath + "?_foreign_keys=on")
+// WithRetryAttempts sets how many times a write is tried when the database is
+// busy or locked (1 disables retries). Non-positive keeps the default.
+func WithRetryAttempts(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.retryAttempts = n
+		}
+	}
+}
+
+// WithRetryBaseDelay sets the wait before the first retry; it doubles on each
+// further attempt. Non-positive keeps the default.
+func WithRetryBaseDelay(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.retryBaseDelay = d
+		}
+	}
+}
+
+// WithEncryptionKey encrypts the database with SQLCipher using key. Empty
+// leaves the database unencrypted.
+func WithEncryptionKey(key string) Option {
+	return func(o *options) {
+		o.encryptionKey = key
+	}
+}
+
+func defaultOptions() options {
+	return options{
+		journalMode: "WAL",
+		busyTimeout: 5 * time.Second,
+		synchronous: "NORMAL",
+
+		maxOpenConns: 4,
+		maxIdleConns: 4,
+
+		retryAttempts:  5,
+		retryBaseDelay: 10 * time.Millisecond,
+	}
+}
+
+func New(dbPath string, opts ...Option) (*SQLiteStorage, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	db, err := openDB(dataSourceName(dbPath, o))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	db.SetMaxOpenConns(o.maxOpenConns)
+	db.SetMaxIdleConns(o.maxIdleConns)
+	db.SetConnMaxLifetime(o.connMaxLifetime)
 
-	storage := &SQLiteStorage{db: db}
+	if o.encryptionKey != "" {
+		if err := checkEncryption(db); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+
+	storage := &SQLiteStorage{
+		db:             db,
+		retryAttempts:  o.retryAttempts,
+		retryBaseDelay: o.retryBaseDelay,
+	}
 
 	if err := storage.RunMigrations(); err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
@@ -32,19 +190,275 @@ func New(dbPath string) (*SQLiteStorage, error) {
 	return storage, nil
 }
 
+// ErrEncryptionUnsupported is returned by New when an encryption key is set
+// but the SQLite driver was built without SQLCipher, which would otherwise
+// silently ignore the key and write the database in plain text
+var ErrEncryptionUnsupported = errors.New("database encryption needs a SQLite driver built with SQLCipher")
+
+// checkEncryption makes sure the driver applied the key from the DSN. Plain
+// SQLite has no cipher_version pragma and returns no rows for it; with
+// SQLCipher a wrong key only shows up once the schema is read.
+func checkEncryption(db *sql.DB) error {
+	var version string
+	err := db.QueryRow("PRAGMA cipher_version").Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && version == "") {
+		return ErrEncryptionUnsupported
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check database encryption: %w", err)
+	}
+
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master").Scan(&tables); err != nil {
+		return fmt.Errorf("failed to open encrypted database, is the key correct? %w", err)
+	}
+	return nil
+}
+
+// quoteKey quotes an encryption key as an SQL string literal for PRAGMA key
+func quoteKey(key string) string {
+	return "'" + strings.ReplaceAll(key, "'", "''") + "'"
+}
+
 func (s *SQLiteStorage) RunMigrations() error {
 	return runMigrations(s.db)
 }
 
+// Ping verifies the database connection is alive
+func (s *SQLiteStorage) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	return s.db.PingContext(ctx)
+}
+
+// SchemaVersion returns the latest migration recorded in schema_migrations
+func (s *SQLiteStorage) SchemaVersion() (int, error) {
+	version, err := getCurrentVersion(s.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// ExpectedSchemaVersion returns the latest migration this build applies
+func (s *SQLiteStorage) ExpectedSchemaVersion() int {
+	return latestMigrationVersion()
+}
+
+// WALEnabled reports whether the database runs in write-ahead log mode
+func (s *SQLiteStorage) WALEnabled(ctx context.Context) (bool, error) {
+	var mode string
+	if err := s.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode); err != nil {
+		return false, fmt.Errorf("failed to read journal mode: %w", err)
+	}
+	return strings.EqualFold(mode, "wal"), nil
+}
+
+// Checkpoint folds the write-ahead log back into the database and truncates
+// the -wal file so it does not grow without bound.
+//
+// A TRUNCATE checkpoint resets the log before reporting, so its page counts are
+// always zero; a PASSIVE pass runs first to capture how much work was done.
+func (s *SQLiteStorage) Checkpoint(ctx context.Context) (*storage.CheckpointResult, error) {
+	var busy int
+	var result storage.CheckpointResult
+	err := s.db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(PASSIVE)").Scan(&busy, &result.LogPages, &result.Checkpointed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkpoint: %w", err)
+	}
+
+	var logPages, checkpointed int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logPages, &checkpointed); err != nil {
+		return nil, fmt.Errorf("failed to truncate write-ahead log: %w", err)
+	}
+	result.Busy = busy != 0
+	return &result, nil
+}
+
+// Optimize rewrites the database file with VACUUM, dropping free pages left by
+// deletes, then runs PRAGMA optimize to refresh the query planner's statistics
+func (s *SQLiteStorage) Optimize(ctx context.Context) error {
+	if err := s.withRetry(func() error {
+		_, err := s.db.ExecContext(ctx, "VACUUM")
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to vacuum: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, "PRAGMA optimize"); err != nil {
+		return fmt.Errorf("failed to optimize: %w", err)
+	}
+	return nil
+}
+
+// DatabaseSize returns the size of the main database file, not counting the
+// write-ahead log
+func (s *SQLiteStorage) DatabaseSize(ctx context.Context) (int64, error) {
+	var pageCount, pageSize int64
+	if err := s.db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, fmt.Errorf("failed to read page count: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, "PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to read page size: %w", err)
+	}
+	return pageCount * pageSize, nil
+}
+
+// FindOrphans lists links and comments whose task_id matches no task. The
+// foreign keys prevent these, unless they were off when the rows were written
+func (s *SQLiteStorage) FindOrphans(ctx context.Context) ([]string, []string, error) {
+	links, err := s.orphanIDs(ctx, "SELECT id FROM links"+orphanCondition+" ORDER BY id")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find orphaned links: %w", err)
+	}
+	comments, err := s.orphanIDs(ctx, "SELECT id FROM comments"+orphanCondition+" ORDER BY id")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find orphaned comments: %w", err)
+	}
+	return links, comments, nil
+}
+
+// PurgeOrphans deletes the rows FindOrphans reports
+func (s *SQLiteStorage) PurgeOrphans(ctx context.Context) ([]string, []string, error) {
+	links, err := s.purgeOrphans(ctx, "links")
+	if err != nil {
+		return nil, nil, err
+	}
+	comments, err := s.purgeOrphans(ctx, "comments")
+	if err != nil {
+		return nil, nil, err
+	}
+	return links, comments, nil
+}
+
+func (s *SQLiteStorage) purgeOrphans(ctx context.Context, table string) ([]string, error) {
+	var ids []string
+	err := s.withRetry(func() error {
+		var err error
+		ids, err = s.orphanIDs(ctx, "DELETE FROM "+table+orphanCondition+" RETURNING id")
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge orphaned %s: %w", table, err)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+const orphanCondition = " WHERE NOT EXISTS (SELECT 1 FROM tasks WHERE tasks.id = task_id)"
+
+// orphanIDs runs a query returning a single id column
+func (s *SQLiteStorage) orphanIDs(ctx context.Context, query string) ([]string, error) {
+	rows, err := s.conn().QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 func (s *SQLiteStorage) Close() error {
+	if s.tx != nil {
+		return errors.New("cannot close the database from inside a transaction")
+	}
 	return s.db.Close()
 }
 
+// withRetry runs fn until it succeeds, fails with an error other than a busy
+// or locked database, or runs out of attempts, backing off exponentially.
+// Inside a transaction fn runs once: a lock error there means the whole
+// transaction has to be retried by its caller.
+func (s *SQLiteStorage) withRetry(fn func() error) error {
+	if s.tx != nil {
+		return fn()
+	}
+	delay := s.retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !isBusyError(err) || attempt >= s.retryAttempts {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
 // Task operations
-func (s *SQLiteStorage) CreateTask(task *models.Task) error {
+func (s *SQLiteStorage) CreateTask(ctx context.Context, task *models.Task) error {
+	err := s.withRetry(func() error {
+		return insertTask(ctx, s.conn(), task)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recordTaskCreated(ctx, task)
+	return nil
+}
+
+// CreateTaskWithLinks inserts a task and its initial links in one transaction;
+// if any link is invalid nothing is stored
+func (s *SQLiteStorage) CreateTaskWithLinks(ctx context.Context, task *models.Task, links []*models.Link) error {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	if err := insertTask(ctx, tx, task); err != nil {
+		return err
+	}
+
+	for i, link := range links {
+		link.TaskID = task.ID
+		if err := insertLink(ctx, tx, link); err != nil {
+			var validationErr *models.ValidationError
+			if errors.As(err, &validationErr) {
+				return &models.ValidationError{
+					Field:   fmt.Sprintf("links[%d].%s", i, validationErr.Field),
+					Code:    validationErr.Code,
+					Message: validationErr.Message,
+				}
+			}
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.recordTaskCreated(ctx, task)
+	for _, link := range links {
+		s.recordLinkAdded(ctx, link)
+	}
+	return nil
+}
+
+// insertTask validates and defaults a new task, then inserts it
+func insertTask(ctx context.Context, q querier, task *models.Task) error {
 	if err := task.Validate(); err != nil {
 		return err
 	}
+	if err := task.ValidateBlockedChange(""); err != nil {
+		return err
+	}
 
 	if task.ID == "" {
 		task.ID = uuid.New().String()
@@ -55,10 +469,10 @@ func (s *SQLiteStorage) CreateTask(task *models.Task) error {
 	task.UpdatedAt = now
 
 	if task.Status == "" {
-		task.Status = models.DefaultStatus
+		task.Status = models.NewTaskStatus()
 	}
 	if task.Priority == "" {
-		task.Priority = models.DefaultPriority
+		task.Priority = models.NewTaskPriority()
 	}
 
 	tagsJSON, err := json.Marshal(task.Tags)
@@ -71,51 +485,290 @@ func (s *SQLiteStorage) CreateTask(task *models.Task) error {
 		return fmt.Errorf("failed to marshal blockers: %w", err)
 	}
 
-	_, err = s.db.Exec(`
-		INSERT INTO tasks (id, jira_id, title, priority, status, tags, blockers, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, task.ID, task.JiraID, task.Title, task.Priority, task.Status, string(tagsJSON), string(blockersJSON), task.CreatedAt, task.UpdatedAt)
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO tasks (id, jira_id, title, priority, status, tags, blockers, created_at, updated_at, starred, custom_fields)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, task.ID, task.JiraID, task.Title, task.Priority, task.Status, string(tagsJSON), string(blockersJSON), task.CreatedAt, task.UpdatedAt, task.Starred, marshalFields(task.CustomFields))
+	return err
+}
+
+func (s *SQLiteStorage) recordTaskCreated(ctx context.Context, task *models.Task) {
+	s.recordActivity(ctx, task.ID, models.ActivityTaskCreated, map[string]interface{}{
+		"jira_id":  task.JiraID,
+		"title":    task.Title,
+		"status":   task.Status,
+		"priority": task.Priority,
+	})
+}
+
+func (s *SQLiteStorage) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	return getTask(ctx, s.conn(), id)
+}
+
+// GetTasks loads the given tasks with a single IN query
+func (s *SQLiteStorage) GetTasks(ctx context.Context, ids []string) (map[string]*models.Task, error) {
+	tasks := make(map[string]*models.Task, len(ids))
+	if len(ids) == 0 {
+		return tasks, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, jira_id, title, priority, status, tags, blockers, created_at, updated_at, starred, custom_fields
+		FROM tasks WHERE id IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		var task models.Task
+		var tagsJSON, blockersJSON, fieldsJSON sql.NullString
+
+		err := rows.Scan(
+			&task.ID, &task.JiraID, &task.Title, &task.Priority, &task.Status,
+			&tagsJSON, &blockersJSON, &task.CreatedAt, &task.UpdatedAt, &task.Starred, &fieldsJSON,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if task.Tags, err = unmarshalList(tagsJSON, "tags"); err != nil {
+			return nil, err
+		}
+		if task.Blockers, err = unmarshalList(blockersJSON, "blockers"); err != nil {
+			return nil, err
+		}
+		if task.CustomFields, err = unmarshalFields(fieldsJSON); err != nil {
+			return nil, err
+		}
+
+		tasks[task.ID] = &task
+	}
+
+	return tasks, rows.Err()
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the transaction the storage is bound to, or the database
+func (s *SQLiteStorage) conn() querier {
+	if s.tx != nil {
+		return s.tx
+	}
+	return s.db
+}
+
+// transaction is a *sql.Tx or a savepoint nested inside one
+type transaction interface {
+	querier
+	Commit() error
+	Rollback() error
+}
 
+// beginTx starts a transaction, or a savepoint when the storage is already
+// bound to one, so multi-statement writes stay atomic either way
+func (s *SQLiteStorage) beginTx(ctx context.Context) (transaction, error) {
+	if s.tx == nil {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return tx, nil
+	}
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT nested"); err != nil {
+		return nil, err
+	}
+	return &savepoint{Tx: s.tx, ctx: ctx}, nil
+}
+
+// savepoint scopes a nested transaction. SQLite resolves savepoint names to
+// the innermost one, so every level can reuse the same name.
+type savepoint struct {
+	*sql.Tx
+	ctx  context.Context
+	done bool
+}
+
+func (sp *savepoint) Commit() error {
+	if sp.done {
+		return sql.ErrTxDone
+	}
+	sp.done = true
+	_, err := sp.Tx.ExecContext(sp.ctx, "RELEASE SAVEPOINT nested")
 	return err
 }
 
-func (s *SQLiteStorage) GetTask(id string) (*models.Task, error) {
-	var task models.Task
-	var tagsJSON, blockersJSON string
+func (sp *savepoint) Rollback() error {
+	if sp.done {
+		return sql.ErrTxDone
+	}
+	sp.done = true
+	if _, err := sp.Tx.ExecContext(sp.ctx, "ROLLBACK TO SAVEPOINT nested"); err != nil {
+		return err
+	}
+	_, err := sp.Tx.ExecContext(sp.ctx, "RELEASE SAVEPOINT nested")
+	return err
+}
 
-	err := s.db.QueryRow(`
-		SELECT id, jira_id, title, priority, status, tags, blockers, created_at, updated_at
+// WithTransaction runs fn with a storage bound to one transaction. Activity
+// recorded by the writes is part of the transaction and is rolled back with
+// them. Calls on a storage that is already bound use a savepoint, so an inner
+// failure only undoes the inner writes.
+func (s *SQLiteStorage) WithTransaction(ctx context.Context, fn func(tx storage.Storage) error) error {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	bound := s
+	if sqlTx, ok := tx.(*sql.Tx); ok {
+		bound = &SQLiteStorage{
+			db:             s.db,
+			tx:             sqlTx,
+			retryAttempts:  s.retryAttempts,
+			retryBaseDelay: s.retryBaseDelay,
+		}
+	}
+
+	if err := fn(bound); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func getTask(ctx context.Context, q querier, id string) (*models.Task, error) {
+	return scanTask(q.QueryRowContext(ctx, `
+		SELECT id, jira_id, title, priority, status, tags, blockers, created_at, updated_at, starred, custom_fields
 		FROM tasks WHERE id = ?
-	`, id).Scan(
+	`, id))
+}
+
+func (s *SQLiteStorage) GetTaskByJiraID(ctx context.Context, jiraID string) (*models.Task, error) {
+	return scanTask(s.conn().QueryRowContext(ctx, `
+		SELECT id, jira_id, title, priority, status, tags, blockers, created_at, updated_at, starred, custom_fields
+		FROM tasks WHERE jira_id = ? AND status != 'archived'
+		ORDER BY created_at ASC
+		LIMIT 1
+	`, jiraID))
+}
+
+// unmarshalList decodes a task's tags or blockers column. Rows written
+// outside the app may hold an empty string or NULL instead of "[]"; those read
+// as an empty list rather than failing the whole query.
+func unmarshalList(raw sql.NullString, column string) ([]string, error) {
+	if !raw.Valid || strings.TrimSpace(raw.String) == "" {
+		return []string{}, nil
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(raw.String), &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", column, err)
+	}
+	return list, nil
+}
+
+// unmarshalFields decodes a task's custom_fields column, reading an empty
+// object, an empty string or NULL as no fields
+func unmarshalFields(raw sql.NullString) (map[string]string, error) {
+	if !raw.Valid || strings.TrimSpace(raw.String) == "" {
+		return nil, nil
+	}
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(raw.String), &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal custom_fields: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// customFieldPath is the JSON path of a custom field. The key is quoted, so
+// keys that aren't valid JSON path identifiers simply match nothing.
+func customFieldPath(key string) string {
+	return `$."` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+}
+
+// marshalFields encodes custom fields for the custom_fields column
+func marshalFields(fields map[string]string) string {
+	if len(fields) == 0 {
+		return "{}"
+	}
+	// A map of strings always encodes
+	data, _ := json.Marshal(fields)
+	return string(data)
+}
+
+// scanTask reads a single task row, mapping sql.ErrNoRows to storage.ErrNotFound
+func scanTask(row *sql.Row) (*models.Task, error) {
+	var task models.Task
+	var tagsJSON, blockersJSON, fieldsJSON sql.NullString
+
+	err := row.Scan(
 		&task.ID, &task.JiraID, &task.Title, &task.Priority, &task.Status,
-		&tagsJSON, &blockersJSON, &task.CreatedAt, &task.UpdatedAt,
+		&tagsJSON, &blockersJSON, &task.CreatedAt, &task.UpdatedAt, &task.Starred, &fieldsJSON,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("task not found")
+			return nil, fmt.Errorf("task %w", storage.ErrNotFound)
 		}
 		return nil, err
 	}
 
-	if err := json.Unmarshal([]byte(tagsJSON), &task.Tags); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	if task.Tags, err = unmarshalList(tagsJSON, "tags"); err != nil {
+		return nil, err
 	}
-
-	if err := json.Unmarshal([]byte(blockersJSON), &task.Blockers); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal blockers: %w", err)
+	if task.Blockers, err = unmarshalList(blockersJSON, "blockers"); err != nil {
+		return nil, err
+	}
+	if task.CustomFields, err = unmarshalFields(fieldsJSON); err != nil {
+		return nil, err
 	}
 
 	return &task, nil
 }
 
-func (s *SQLiteStorage) UpdateTask(task *models.Task) error {
+func (s *SQLiteStorage) UpdateTask(ctx context.Context, task *models.Task) error {
 	if err := task.Validate(); err != nil {
 		return err
 	}
 
 	task.UpdatedAt = time.Now()
 
+	// Best-effort read of the previous status so the timeline can tell
+	// status changes apart from other edits, and illegal moves are caught
+	var previousStatus models.Status
+	_ = s.conn().QueryRowContext(ctx, "SELECT status FROM tasks WHERE id = ?", task.ID).Scan(&previousStatus)
+	if previousStatus != "" {
+		if err := storage.CheckTransition(ctx, previousStatus, task.Status); err != nil {
+			return err
+		}
+		if err := task.ValidateBlockedChange(previousStatus); err != nil {
+			return err
+		}
+	}
+
 	tagsJSON, err := json.Marshal(task.Tags)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tags: %w", err)
@@ -126,22 +779,148 @@ func (s *SQLiteStorage) UpdateTask(task *models.Task) error {
 		return fmt.Errorf("failed to marshal blockers: %w", err)
 	}
 
-	_, err = s.db.Exec(`
-		UPDATE tasks
-		SET jira_id = ?, title = ?, priority = ?, status = ?, tags = ?, blockers = ?, updated_at = ?
-		WHERE id = ?
-	`, task.JiraID, task.Title, task.Priority, task.Status, string(tagsJSON), string(blockersJSON), task.UpdatedAt, task.ID)
+	err = s.withRetry(func() error {
+		_, err := s.conn().ExecContext(ctx, `
+			UPDATE tasks
+			SET jira_id = ?, title = ?, priority = ?, status = ?, tags = ?, blockers = ?, starred = ?, custom_fields = ?, updated_at = ?
+			WHERE id = ?
+		`, task.JiraID, task.Title, task.Priority, task.Status, string(tagsJSON), string(blockersJSON), task.Starred, marshalFields(task.CustomFields), task.UpdatedAt, task.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	if previousStatus != "" && previousStatus != task.Status {
+		s.recordActivity(ctx, task.ID, models.ActivityStatusChanged, map[string]interface{}{
+			"from": previousStatus,
+			"to":   task.Status,
+		})
+	} else {
+		s.recordActivity(ctx, task.ID, models.ActivityTaskUpdated, map[string]interface{}{
+			"title":    task.Title,
+			"priority": task.Priority,
+		})
+	}
+	return nil
 }
 
-func (s *SQLiteStorage) DeleteTask(id string) error {
-	_, err := s.db.Exec("DELETE FROM tasks WHERE id = ?", id)
-	return err
+func (s *SQLiteStorage) DeleteTask(ctx context.Context, id string) error {
+	return s.withRetry(func() error {
+		_, err := s.conn().ExecContext(ctx, "DELETE FROM tasks WHERE id = ?", id)
+		return err
+	})
+}
+
+// PurgeArchived relies on ON DELETE CASCADE to remove the purged tasks'
+// links, comments and activity
+func (s *SQLiteStorage) PurgeArchived(ctx context.Context, olderThan time.Time) (int, error) {
+	result, err := s.conn().ExecContext(ctx,
+		"DELETE FROM tasks WHERE status = ? AND julianday(updated_at) < julianday(?)",
+		models.Archived, sqliteTimestamp(olderThan))
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(purged), nil
 }
 
-func (s *SQLiteStorage) ListTasks(filters storage.TaskFilters) ([]*models.Task, error) {
-	query := "SELECT id, jira_id, title, priority, status, tags, blockers, created_at, updated_at FROM tasks WHERE 1=1"
+func (s *SQLiteStorage) ListTasks(ctx context.Context, filters storage.TaskFilters) ([]*models.Task, error) {
+	var tasks []*models.Task
+	err := s.StreamTasks(ctx, filters, func(task *models.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// StreamTasks hands fn each task as it is read from the result set, so the
+// list is never held in memory as a whole
+func (s *SQLiteStorage) StreamTasks(ctx context.Context, filters storage.TaskFilters, fn func(*models.Task) error) error {
+	conditions, args := taskFilterConditions(filters)
+	query := "SELECT id, jira_id, title, priority, status, tags, blockers, created_at, updated_at, starred, custom_fields FROM tasks WHERE 1=1" + conditions
+
+	if filters.After != nil {
+		// Compared through julianday() like appendTimeRange, so the cursor's
+		// and the stored time zones don't matter
+		query += " AND (starred, julianday(created_at), id) < (?, julianday(?), ?)"
+		args = append(args, filters.After.Starred, sqliteTimestamp(filters.After.CreatedAt), filters.After.ID)
+	}
+
+	// Starred tasks come first; id breaks ties so cursors never skip or repeat
+	// tasks created in the same instant. Ordering by julianday() keeps the
+	// list in step with the cursor comparison above.
+	query += " ORDER BY starred DESC, julianday(created_at) DESC, id DESC"
+
+	if filters.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filters.Limit)
+	}
+
+	if filters.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, filters.Offset)
+	}
+
+	rows, err := s.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		var task models.Task
+		var tagsJSON, blockersJSON, fieldsJSON sql.NullString
+
+		err := rows.Scan(
+			&task.ID, &task.JiraID, &task.Title, &task.Priority, &task.Status,
+			&tagsJSON, &blockersJSON, &task.CreatedAt, &task.UpdatedAt, &task.Starred, &fieldsJSON,
+		)
+		if err != nil {
+			return err
+		}
+
+		if task.Tags, err = unmarshalList(tagsJSON, "tags"); err != nil {
+			return err
+		}
+		if task.Blockers, err = unmarshalList(blockersJSON, "blockers"); err != nil {
+			return err
+		}
+		if task.CustomFields, err = unmarshalFields(fieldsJSON); err != nil {
+			return err
+		}
+
+		if err := fn(&task); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// CountTasks ignores the paging fields of filters
+func (s *SQLiteStorage) CountTasks(ctx context.Context, filters storage.TaskFilters) (int, error) {
+	conditions, args := taskFilterConditions(filters)
+	var count int
+	err := s.conn().QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks WHERE 1=1"+conditions, args...).Scan(&count)
+	return count, err
+}
+
+// taskFilterConditions builds the " AND ..." clauses for every TaskFilters
+// condition except paging
+func taskFilterConditions(filters storage.TaskFilters) (string, []interface{}) {
+	query := ""
 	args := []interface{}{}
 
 	if !filters.IncludeArchived {
@@ -172,19 +951,68 @@ func (s *SQLiteStorage) ListTasks(filters storage.TaskFilters) ([]*models.Task,
 		query += ")"
 	}
 
-	query += " ORDER BY created_at DESC"
+	if filters.Starred != nil {
+		query += " AND starred = ?"
+		args = append(args, *filters.Starred)
+	}
 
-	if filters.Limit > 0 {
-		query += " LIMIT ?"
-		args = append(args, filters.Limit)
+	if len(filters.Tags) > 0 {
+		placeholders := make([]string, len(filters.Tags))
+		for i, tag := range filters.Tags {
+			placeholders[i] = "?"
+			args = append(args, tag)
+		}
+		query += " AND EXISTS (SELECT 1 FROM json_each(NULLIF(tasks.tags, '')) WHERE value IN (" + strings.Join(placeholders, ", ") + "))"
 	}
 
-	if filters.Offset > 0 {
-		query += " OFFSET ?"
-		args = append(args, filters.Offset)
+	// Keys are sorted so the same filters always build the same query
+	for _, key := range slices.Sorted(maps.Keys(filters.CustomFields)) {
+		query += " AND json_extract(NULLIF(custom_fields, ''), ?) = ?"
+		args = append(args, customFieldPath(key), filters.CustomFields[key])
+	}
+
+	query, args = appendTimeRange(query, args, "created_at", filters.CreatedAfter, filters.CreatedBefore)
+	query, args = appendTimeRange(query, args, "updated_at", filters.UpdatedAfter, filters.UpdatedBefore)
+
+	return query, args
+}
+
+func (s *SQLiteStorage) SearchTasks(ctx context.Context, query string, includeArchived, includeComments bool, limit int) ([]*models.Task, error) {
+	pattern := "%" + query + "%"
+	fieldMatch := "(title LIKE ? OR jira_id LIKE ? OR tags LIKE ?)"
+	where := fieldMatch
+	args := []interface{}{pattern, pattern, pattern}
+
+	// EXISTS keeps a task with several matching comments from appearing twice
+	if includeComments {
+		where = "(" + fieldMatch + ` OR EXISTS (
+			SELECT 1 FROM comments WHERE comments.task_id = tasks.id AND comments.content LIKE ?
+		))`
+		args = append(args, pattern)
+	}
+
+	sqlQuery := `
+		SELECT id, jira_id, title, priority, status, tags, blockers, created_at, updated_at, starred, custom_fields
+		FROM tasks
+		WHERE ` + where
+
+	if !includeArchived {
+		sqlQuery += " AND status != 'archived'"
+	}
+
+	if includeComments {
+		sqlQuery += " ORDER BY CASE WHEN " + fieldMatch + " THEN 0 ELSE 1 END, created_at DESC"
+		args = append(args, pattern, pattern, pattern)
+	} else {
+		sqlQuery += " ORDER BY created_at DESC"
+	}
+
+	if limit > 0 {
+		sqlQuery += " LIMIT ?"
+		args = append(args, limit)
 	}
 
-	rows, err := s.db.Query(query, args...)
+	rows, err := s.conn().QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -197,92 +1025,421 @@ func (s *SQLiteStorage) ListTasks(filters storage.TaskFilters) ([]*models.Task,
 	var tasks []*models.Task
 	for rows.Next() {
 		var task models.Task
-		var tagsJSON, blockersJSON string
+		var tagsJSON, blockersJSON, fieldsJSON sql.NullString
 
 		err := rows.Scan(
 			&task.ID, &task.JiraID, &task.Title, &task.Priority, &task.Status,
-			&tagsJSON, &blockersJSON, &task.CreatedAt, &task.UpdatedAt,
+			&tagsJSON, &blockersJSON, &task.CreatedAt, &task.UpdatedAt, &task.Starred, &fieldsJSON,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		if err := json.Unmarshal([]byte(tagsJSON), &task.Tags); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+		if task.Tags, err = unmarshalList(tagsJSON, "tags"); err != nil {
+			return nil, err
+		}
+		if task.Blockers, err = unmarshalList(blockersJSON, "blockers"); err != nil {
+			return nil, err
+		}
+		if task.CustomFields, err = unmarshalFields(fieldsJSON); err != nil {
+			return nil, err
 		}
 
-		if err := json.Unmarshal([]byte(blockersJSON), &task.Blockers); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal blockers: %w", err)
+		tasks = append(tasks, &task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// GetRelatedTasks ranks other non-archived tasks by how many tags they share
+// with taskID, breaking ties by most recently updated
+func (s *SQLiteStorage) GetRelatedTasks(ctx context.Context, taskID string, limit int) ([]*models.Task, error) {
+	query := `
+		SELECT t.id, t.jira_id, t.title, t.priority, t.status, t.tags, t.blockers, t.created_at, t.updated_at, t.starred, t.custom_fields
+		FROM tasks t, json_each(NULLIF(t.tags, '')) AS tag
+		WHERE t.id != ? AND t.status != 'archived'
+		  AND tag.value IN (
+			SELECT src_tag.value FROM tasks src, json_each(NULLIF(src.tags, '')) AS src_tag WHERE src.id = ?
+		  )
+		GROUP BY t.id
+		ORDER BY COUNT(DISTINCT tag.value) DESC, t.updated_at DESC, t.id
+	`
+	args := []interface{}{taskID, taskID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	var tasks []*models.Task
+	for rows.Next() {
+		var task models.Task
+		var tagsJSON, blockersJSON, fieldsJSON sql.NullString
+
+		err := rows.Scan(
+			&task.ID, &task.JiraID, &task.Title, &task.Priority, &task.Status,
+			&tagsJSON, &blockersJSON, &task.CreatedAt, &task.UpdatedAt, &task.Starred, &fieldsJSON,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if task.Tags, err = unmarshalList(tagsJSON, "tags"); err != nil {
+			return nil, err
+		}
+		if task.Blockers, err = unmarshalList(blockersJSON, "blockers"); err != nil {
+			return nil, err
+		}
+		if task.CustomFields, err = unmarshalFields(fieldsJSON); err != nil {
+			return nil, err
 		}
 
 		tasks = append(tasks, &task)
 	}
 
-	return tasks, nil
+	return tasks, rows.Err()
 }
 
-func (s *SQLiteStorage) SearchTasks(query string, includeArchived bool, limit int) ([]*models.Task, error) {
-	sqlQuery := `
-		SELECT id, jira_id, title, priority, status, tags, blockers, created_at, updated_at
-		FROM tasks
-		WHERE (title LIKE ? OR jira_id LIKE ? OR tags LIKE ?)
+// CountTaskRelations counts links and comments per task with one grouped
+// query for each table, joined back onto the requested tasks
+func (s *SQLiteStorage) CountTaskRelations(ctx context.Context, taskIDs []string) (map[string]storage.TaskRelationCounts, error) {
+	counts := make(map[string]storage.TaskRelationCounts, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(taskIDs)), ",")
+	query := `
+		SELECT t.id, COALESCE(l.count, 0), COALESCE(c.count, 0)
+		FROM tasks t
+		LEFT JOIN (SELECT task_id, COUNT(*) AS count FROM links WHERE task_id IN (` + placeholders + `) GROUP BY task_id) l ON l.task_id = t.id
+		LEFT JOIN (SELECT task_id, COUNT(*) AS count FROM comments WHERE task_id IN (` + placeholders + `) GROUP BY task_id) c ON c.task_id = t.id
+		WHERE t.id IN (` + placeholders + `)
 	`
-	args := []interface{}{
-		"%" + query + "%",
-		"%" + query + "%",
-		"%" + query + "%",
+	args := make([]interface{}, 0, 3*len(taskIDs))
+	for range 3 {
+		for _, id := range taskIDs {
+			args = append(args, id)
+		}
 	}
 
-	if !includeArchived {
-		sqlQuery += " AND status != 'archived'"
+	rows, err := s.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		var id string
+		var c storage.TaskRelationCounts
+		if err := rows.Scan(&id, &c.Links, &c.Comments); err != nil {
+			return nil, err
+		}
+		counts[id] = c
+	}
+
+	return counts, rows.Err()
+}
+
+// MergeTasks moves the source task's links and comments to the target, unions
+// tags and blockers, and archives (or deletes) the source in a single
+// transaction, retried like other writes when the database is busy
+func (s *SQLiteStorage) MergeTasks(ctx context.Context, sourceID, targetID string, opts storage.MergeOptions) (*models.Task, error) {
+	if sourceID == targetID {
+		return nil, &models.ValidationError{Field: "source", Code: models.CodeNotAllowed, Message: "cannot merge a task into itself"}
+	}
+
+	var merged *models.Task
+	err := s.withRetry(func() error {
+		var err error
+		merged, err = s.mergeTasks(ctx, sourceID, targetID, opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+func (s *SQLiteStorage) mergeTasks(ctx context.Context, sourceID, targetID string, opts storage.MergeOptions) (*models.Task, error) {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	source, err := getTask(ctx, tx, sourceID)
+	if err != nil {
+		return nil, err
 	}
+	target, err := getTask(ctx, tx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousStatus := target.Status
+	if opts.Prefer == storage.PreferSource {
+		target.JiraID = source.JiraID
+		target.Title = source.Title
+		target.Priority = source.Priority
+		target.Status = source.Status
+	}
+	target.Tags = unionStrings(target.Tags, source.Tags)
+	target.Blockers = unionStrings(target.Blockers, source.Blockers)
+	target.Starred = target.Starred || source.Starred
+	target.CustomFields = storage.MergeCustomFields(target.CustomFields, source.CustomFields, opts.Prefer)
+
+	// The target takes on the source's status like an update would, so the
+	// same status checks apply
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	if err := storage.CheckTransition(ctx, previousStatus, target.Status); err != nil {
+		return nil, err
+	}
+	if err := target.ValidateBlockedChange(previousStatus); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE links SET task_id = ? WHERE task_id = ?", targetID, sourceID); err != nil {
+		return nil, fmt.Errorf("failed to move links: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE comments SET task_id = ? WHERE task_id = ?", targetID, sourceID); err != nil {
+		return nil, fmt.Errorf("failed to move comments: %w", err)
+	}
+
+	now := time.Now()
+	target.UpdatedAt = now
+
+	tagsJSON, err := json.Marshal(target.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	blockersJSON, err := json.Marshal(target.Blockers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal blockers: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET jira_id = ?, title = ?, priority = ?, status = ?, tags = ?, blockers = ?, starred = ?, custom_fields = ?, updated_at = ?
+		WHERE id = ?
+	`, target.JiraID, target.Title, target.Priority, target.Status, string(tagsJSON), string(blockersJSON), target.Starred, marshalFields(target.CustomFields), target.UpdatedAt, target.ID); err != nil {
+		return nil, fmt.Errorf("failed to update target task: %w", err)
+	}
+
+	if opts.DeleteSource {
+		_, err = tx.ExecContext(ctx, "DELETE FROM tasks WHERE id = ?", sourceID)
+	} else {
+		_, err = tx.ExecContext(ctx, "UPDATE tasks SET status = ?, updated_at = ? WHERE id = ?", models.Archived, now, sourceID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retire source task: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// unionStrings appends the values of b missing from a, preserving order
+func unionStrings(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	result := make([]string, 0, len(a)+len(b))
+	for _, v := range append(append([]string{}, a...), b...) {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
+
+// appendTimeRange adds an inclusive lower and exclusive upper bound on a
+// timestamp column. Values are compared through julianday so rows written
+// with different UTC offsets still order correctly.
+func appendTimeRange(query string, args []interface{}, column string, after, before time.Time) (string, []interface{}) {
+	if !after.IsZero() {
+		query += " AND julianday(" + column + ") >= julianday(?)"
+		args = append(args, sqliteTimestamp(after))
+	}
+	if !before.IsZero() {
+		query += " AND julianday(" + column + ") < julianday(?)"
+		args = append(args, sqliteTimestamp(before))
+	}
+	return query, args
+}
+
+// sqliteTimestampLayout is the layout the drivers use to store time values
+const sqliteTimestampLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// sqliteTimestamp formats t the way the driver stores time values
+func sqliteTimestamp(t time.Time) string {
+	return t.UTC().Format(sqliteTimestampLayout)
+}
+
+// ImportData inserts exported tasks, links and comments in one transaction,
+// preserving their IDs and timestamps. Records whose ID already exists are
+// skipped; any other failure rolls back the whole import.
+func (s *SQLiteStorage) ImportData(ctx context.Context, data *models.ExportData) (*models.ImportResult, error) {
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	result := &models.ImportResult{}
+	now := time.Now()
+
+	for _, task := range data.Tasks {
+		if err := task.Validate(); err != nil {
+			return nil, err
+		}
+		if task.ID == "" {
+			task.ID = uuid.New().String()
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		if task.UpdatedAt.IsZero() {
+			task.UpdatedAt = task.CreatedAt
+		}
+
+		tagsJSON, err := json.Marshal(task.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal tags: %w", err)
+		}
 
-	sqlQuery += " ORDER BY created_at DESC"
+		blockersJSON, err := json.Marshal(task.Blockers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal blockers: %w", err)
+		}
 
-	if limit > 0 {
-		sqlQuery += " LIMIT ?"
-		args = append(args, limit)
+		inserted, err := insertIgnoringExisting(ctx, tx, `
+			INSERT INTO tasks (id, jira_id, title, priority, status, tags, blockers, created_at, updated_at, starred, custom_fields)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO NOTHING
+		`, task.ID, task.JiraID, task.Title, task.Priority, task.Status, string(tagsJSON), string(blockersJSON), task.CreatedAt, task.UpdatedAt, task.Starred, marshalFields(task.CustomFields))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import task %s: %w", task.ID, err)
+		}
+		if inserted {
+			result.TasksImported++
+		} else {
+			result.TasksSkipped++
+		}
 	}
 
-	rows, err := s.db.Query(sqlQuery, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Printf("failed to close rows: %v", err)
+	for _, link := range data.Links {
+		if err := link.Validate(); err != nil {
+			return nil, err
+		}
+		if link.ID == "" {
+			link.ID = uuid.New().String()
 		}
-	}()
-
-	var tasks []*models.Task
-	for rows.Next() {
-		var task models.Task
-		var tagsJSON, blockersJSON string
 
-		err := rows.Scan(
-			&task.ID, &task.JiraID, &task.Title, &task.Priority, &task.Status,
-			&tagsJSON, &blockersJSON, &task.CreatedAt, &task.UpdatedAt,
-		)
+		inserted, err := insertIgnoringExisting(ctx, tx, `
+			INSERT INTO links (id, task_id, type, url, title, status, metadata)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO NOTHING
+		`, link.ID, link.TaskID, link.Type, link.URL, link.Title, link.Status, link.Metadata)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to import link %s: %w", link.ID, err)
+		}
+		if inserted {
+			result.LinksImported++
+		} else {
+			result.LinksSkipped++
 		}
+	}
 
-		if err := json.Unmarshal([]byte(tagsJSON), &task.Tags); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	for _, comment := range data.Comments {
+		if err := comment.Validate(); err != nil {
+			return nil, err
+		}
+		if comment.ID == "" {
+			comment.ID = uuid.New().String()
+		}
+		if comment.CreatedAt.IsZero() {
+			comment.CreatedAt = now
 		}
 
-		if err := json.Unmarshal([]byte(blockersJSON), &task.Blockers); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal blockers: %w", err)
+		inserted, err := insertIgnoringExisting(ctx, tx, `
+			INSERT INTO comments (id, task_id, content, created_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT(id) DO NOTHING
+		`, comment.ID, comment.TaskID, comment.Content, comment.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import comment %s: %w", comment.ID, err)
+		}
+		if inserted {
+			result.CommentsImported++
+		} else {
+			result.CommentsSkipped++
 		}
+	}
 
-		tasks = append(tasks, &task)
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
 
-	return tasks, nil
+	return result, nil
+}
+
+// insertIgnoringExisting runs an INSERT ... ON CONFLICT DO NOTHING statement and
+// reports whether a row was actually written
+func insertIgnoringExisting(ctx context.Context, q querier, query string, args ...interface{}) (bool, error) {
+	res, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
 }
 
 // Link operations (simplified for now)
-func (s *SQLiteStorage) CreateLink(link *models.Link) error {
+func (s *SQLiteStorage) CreateLink(ctx context.Context, link *models.Link) error {
+	err := s.withRetry(func() error {
+		return insertLink(ctx, s.conn(), link)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recordLinkAdded(ctx, link)
+	return nil
+}
+
+// insertLink validates a new link, assigns it an ID and inserts it
+func insertLink(ctx context.Context, q querier, link *models.Link) error {
 	if err := link.Validate(); err != nil {
 		return err
 	}
@@ -291,24 +1448,31 @@ func (s *SQLiteStorage) CreateLink(link *models.Link) error {
 		link.ID = uuid.New().String()
 	}
 
-	_, err := s.db.Exec(`
+	_, err := q.ExecContext(ctx, `
 		INSERT INTO links (id, task_id, type, url, title, status, metadata)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`, link.ID, link.TaskID, link.Type, link.URL, link.Title, link.Status, link.Metadata)
-
 	return err
 }
 
-func (s *SQLiteStorage) GetLink(id string) (*models.Link, error) {
+func (s *SQLiteStorage) recordLinkAdded(ctx context.Context, link *models.Link) {
+	s.recordActivity(ctx, link.TaskID, models.ActivityLinkAdded, map[string]interface{}{
+		"link_id": link.ID,
+		"type":    link.Type,
+		"url":     link.URL,
+	})
+}
+
+func (s *SQLiteStorage) GetLink(ctx context.Context, id string) (*models.Link, error) {
 	var link models.Link
-	err := s.db.QueryRow(`
+	err := s.conn().QueryRowContext(ctx, `
 		SELECT id, task_id, type, url, title, status, metadata
 		FROM links WHERE id = ?
 	`, id).Scan(&link.ID, &link.TaskID, &link.Type, &link.URL, &link.Title, &link.Status, &link.Metadata)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("link not found")
+			return nil, fmt.Errorf("link %w", storage.ErrNotFound)
 		}
 		return nil, err
 	}
@@ -316,30 +1480,69 @@ func (s *SQLiteStorage) GetLink(id string) (*models.Link, error) {
 	return &link, nil
 }
 
-func (s *SQLiteStorage) UpdateLink(link *models.Link) error {
+func (s *SQLiteStorage) UpdateLink(ctx context.Context, link *models.Link) error {
+	return s.UpdateLinkWithOptions(ctx, link, storage.UpdateLinkOptions{})
+}
+
+// UpdateLinkWithOptions merges metadata with json_patch inside the UPDATE
+// itself, so a concurrent writer's keys are never read stale and lost
+func (s *SQLiteStorage) UpdateLinkWithOptions(ctx context.Context, link *models.Link, opts storage.UpdateLinkOptions) error {
 	if err := link.Validate(); err != nil {
 		return err
 	}
 
-	_, err := s.db.Exec(`
-		UPDATE links
-		SET task_id = ?, type = ?, url = ?, title = ?, status = ?, metadata = ?
-		WHERE id = ?
-	`, link.TaskID, link.Type, link.URL, link.Title, link.Status, link.Metadata, link.ID)
+	if opts.MergeMetadata {
+		err := s.withRetry(func() error {
+			return s.conn().QueryRowContext(ctx, `
+				UPDATE links
+				SET task_id = ?, type = ?, url = ?, title = ?, status = ?, metadata = json_patch(metadata, ?)
+				WHERE id = ?
+				RETURNING metadata
+			`, link.TaskID, link.Type, link.URL, link.Title, link.Status, link.Metadata, link.ID).Scan(&link.Metadata)
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("link %w", storage.ErrNotFound)
+		}
+		return err
+	}
 
-	return err
+	return s.withRetry(func() error {
+		_, err := s.conn().ExecContext(ctx, `
+			UPDATE links
+			SET task_id = ?, type = ?, url = ?, title = ?, status = ?, metadata = ?
+			WHERE id = ?
+		`, link.TaskID, link.Type, link.URL, link.Title, link.Status, link.Metadata, link.ID)
+		return err
+	})
 }
 
-func (s *SQLiteStorage) DeleteLink(id string) error {
-	_, err := s.db.Exec("DELETE FROM links WHERE id = ?", id)
-	return err
+func (s *SQLiteStorage) DeleteLink(ctx context.Context, id string) error {
+	return s.withRetry(func() error {
+		_, err := s.conn().ExecContext(ctx, "DELETE FROM links WHERE id = ?", id)
+		return err
+	})
+}
+
+func (s *SQLiteStorage) GetTaskLinks(ctx context.Context, taskID string) ([]*models.Link, error) {
+	return s.GetTaskLinksByType(ctx, taskID)
 }
 
-func (s *SQLiteStorage) GetTaskLinks(taskID string) ([]*models.Link, error) {
-	rows, err := s.db.Query(`
+func (s *SQLiteStorage) GetTaskLinksByType(ctx context.Context, taskID string, types ...models.LinkType) ([]*models.Link, error) {
+	query := `
 		SELECT id, task_id, type, url, title, status, metadata
 		FROM links WHERE task_id = ?
-	`, taskID)
+	`
+	args := []interface{}{taskID}
+	if len(types) > 0 {
+		placeholders := make([]string, len(types))
+		for i, linkType := range types {
+			placeholders[i] = "?"
+			args = append(args, linkType)
+		}
+		query += " AND type IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	rows, err := s.conn().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -359,11 +1562,11 @@ func (s *SQLiteStorage) GetTaskLinks(taskID string) ([]*models.Link, error) {
 		links = append(links, &link)
 	}
 
-	return links, nil
+	return links, rows.Err()
 }
 
 // Comment operations (simplified for now)
-func (s *SQLiteStorage) CreateComment(comment *models.Comment) error {
+func (s *SQLiteStorage) CreateComment(ctx context.Context, comment *models.Comment) error {
 	if err := comment.Validate(); err != nil {
 		return err
 	}
@@ -374,24 +1577,92 @@ func (s *SQLiteStorage) CreateComment(comment *models.Comment) error {
 
 	comment.CreatedAt = time.Now()
 
-	_, err := s.db.Exec(`
+	err := s.withRetry(func() error {
+		return insertComment(ctx, s.conn(), comment)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recordCommentAdded(ctx, comment)
+	return nil
+}
+
+// CreateComments inserts the comments in one transaction; if any is invalid
+// nothing is stored
+func (s *SQLiteStorage) CreateComments(ctx context.Context, comments []*models.Comment) error {
+	for i, comment := range comments {
+		if err := comment.Validate(); err != nil {
+			var validationErr *models.ValidationError
+			if errors.As(err, &validationErr) {
+				return &models.ValidationError{
+					Field:   fmt.Sprintf("comments[%d].%s", i, validationErr.Field),
+					Code:    validationErr.Code,
+					Message: validationErr.Message,
+				}
+			}
+			return err
+		}
+	}
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	now := time.Now()
+	for i, comment := range comments {
+		if comment.ID == "" {
+			comment.ID = uuid.New().String()
+		}
+		if comment.CreatedAt.IsZero() {
+			// Offset by the position so undated comments keep their order
+			comment.CreatedAt = now.Add(time.Duration(i))
+		}
+		if err := insertComment(ctx, tx, comment); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		s.recordCommentAdded(ctx, comment)
+	}
+	return nil
+}
+
+func insertComment(ctx context.Context, q querier, comment *models.Comment) error {
+	_, err := q.ExecContext(ctx, `
 		INSERT INTO comments (id, task_id, content, created_at)
 		VALUES (?, ?, ?, ?)
 	`, comment.ID, comment.TaskID, comment.Content, comment.CreatedAt)
-
 	return err
 }
 
-func (s *SQLiteStorage) GetComment(id string) (*models.Comment, error) {
+func (s *SQLiteStorage) recordCommentAdded(ctx context.Context, comment *models.Comment) {
+	s.recordActivity(ctx, comment.TaskID, models.ActivityCommentAdded, map[string]interface{}{
+		"comment_id": comment.ID,
+	})
+}
+
+func (s *SQLiteStorage) GetComment(ctx context.Context, id string) (*models.Comment, error) {
 	var comment models.Comment
-	err := s.db.QueryRow(`
+	err := s.conn().QueryRowContext(ctx, `
 		SELECT id, task_id, content, created_at
 		FROM comments WHERE id = ?
 	`, id).Scan(&comment.ID, &comment.TaskID, &comment.Content, &comment.CreatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("comment not found")
+			return nil, fmt.Errorf("comment %w", storage.ErrNotFound)
 		}
 		return nil, err
 	}
@@ -399,19 +1670,115 @@ func (s *SQLiteStorage) GetComment(id string) (*models.Comment, error) {
 	return &comment, nil
 }
 
-func (s *SQLiteStorage) DeleteComment(id string) error {
-	_, err := s.db.Exec("DELETE FROM comments WHERE id = ?", id)
-	return err
+func (s *SQLiteStorage) DeleteComment(ctx context.Context, id string) error {
+	return s.withRetry(func() error {
+		_, err := s.conn().ExecContext(ctx, "DELETE FROM comments WHERE id = ?", id)
+		return err
+	})
 }
 
-func (s *SQLiteStorage) GetTaskComments(taskID string) ([]*models.Comment, error) {
-	rows, err := s.db.Query(`
+func (s *SQLiteStorage) GetTaskComments(ctx context.Context, taskID string) ([]*models.Comment, error) {
+	rows, err := s.conn().QueryContext(ctx, `
 		SELECT id, task_id, content, created_at
 		FROM comments WHERE task_id = ? ORDER BY created_at ASC
 	`, taskID)
 	if err != nil {
 		return nil, err
 	}
+	return scanComments(rows)
+}
+
+func (s *SQLiteStorage) GetTaskCommentsPaged(ctx context.Context, taskID string, limit, offset int) ([]*models.Comment, int, error) {
+	var total int
+	if err := s.conn().QueryRowContext(ctx, "SELECT COUNT(*) FROM comments WHERE task_id = ?", taskID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// SQLite treats a negative LIMIT as no limit
+	if limit <= 0 {
+		limit = -1
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, task_id, content, created_at
+		FROM comments WHERE task_id = ? ORDER BY created_at ASC, id ASC
+		LIMIT ? OFFSET ?
+	`, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments, err := scanComments(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// StreamLinks reads every link in one query. Orphaned links, left behind by
+// writes made with foreign keys off, are skipped.
+func (s *SQLiteStorage) StreamLinks(ctx context.Context, fn func(*models.Link) error) error {
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, task_id, type, url, title, status, metadata
+		FROM links WHERE task_id IN (SELECT id FROM tasks)
+		ORDER BY task_id, id
+	`)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		var link models.Link
+		err := rows.Scan(&link.ID, &link.TaskID, &link.Type, &link.URL, &link.Title, &link.Status, &link.Metadata)
+		if err != nil {
+			return err
+		}
+		if err := fn(&link); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// StreamComments reads every comment in one query, skipping orphaned ones
+// like StreamLinks
+func (s *SQLiteStorage) StreamComments(ctx context.Context, fn func(*models.Comment) error) error {
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, task_id, content, created_at
+		FROM comments WHERE task_id IN (SELECT id FROM tasks)
+		ORDER BY task_id, created_at ASC, id ASC
+	`)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		var comment models.Comment
+		if err := rows.Scan(&comment.ID, &comment.TaskID, &comment.Content, &comment.CreatedAt); err != nil {
+			return err
+		}
+		if err := fn(&comment); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// scanComments reads and closes rows of (id, task_id, content, created_at)
+func scanComments(rows *sql.Rows) ([]*models.Comment, error) {
 	defer func() {
 		if err := rows.Close(); err != nil {
 			log.Printf("failed to close rows: %v", err)
@@ -428,5 +1795,208 @@ func (s *SQLiteStorage) GetTaskComments(taskID string) ([]*models.Comment, error
 		comments = append(comments, &comment)
 	}
 
-	return comments, nil
+	return comments, rows.Err()
+}
+
+// DistinctValues groups tasks by the field, archived ones included, so values
+// used long ago are still suggested
+func (s *SQLiteStorage) DistinctValues(ctx context.Context, field, prefix string, limit int) ([]string, error) {
+	if err := storage.CheckDistinctField(field); err != nil {
+		return nil, err
+	}
+
+	// The field is one of the allowed column names, so it is safe to splice in
+	query := "SELECT " + field + " FROM tasks WHERE " + field + " != '' AND " + field + " != ? AND " + field + ` LIKE ? ESCAPE '\'
+		GROUP BY ` + field + " ORDER BY COUNT(*) DESC, " + field
+	args := []interface{}{models.NoJiraID(), escapeLike(prefix) + "%"}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// escapeLike escapes LIKE wildcards so s only matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ListTags counts tag usage across non-archived tasks by expanding each
+// task's JSON tag array with json_each
+func (s *SQLiteStorage) ListTags(ctx context.Context) (map[string]int, error) {
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT tag.value, COUNT(*)
+		FROM tasks, json_each(NULLIF(tasks.tags, '')) AS tag
+		WHERE tasks.status != 'archived' AND json_type(NULLIF(tasks.tags, '')) = 'array'
+		GROUP BY tag.value
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	tags := make(map[string]int)
+	for rows.Next() {
+		var tag string
+		var count int
+		if err := rows.Scan(&tag, &count); err != nil {
+			return nil, err
+		}
+		tags[tag] = count
+	}
+
+	return tags, rows.Err()
+}
+
+// RenameTag rewrites from to to on every task (archived included) in a single
+// transaction. Tasks that already carry to keep a single copy of it.
+func (s *SQLiteStorage) RenameTag(ctx context.Context, from, to string) (int, error) {
+	from, to, err := storage.NormalizeTagRename(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, tags FROM tasks
+		WHERE EXISTS (SELECT 1 FROM json_each(NULLIF(tasks.tags, '')) WHERE value = ?)
+	`, from)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := make(map[string][]string)
+	for rows.Next() {
+		var id string
+		var tagsJSON sql.NullString
+		if err := rows.Scan(&id, &tagsJSON); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		tags, err := unmarshalList(tagsJSON, "tags")
+		if err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		updated[id] = replaceTag(tags, from, to)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for id, tags := range updated {
+		tagsJSON, err := json.Marshal(tags)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal tags: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE tasks SET tags = ?, updated_at = ? WHERE id = ?", string(tagsJSON), now, id); err != nil {
+			return 0, fmt.Errorf("failed to update task %s: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(updated), nil
+}
+
+// replaceTag swaps from for to, dropping the duplicate when to is already present
+func replaceTag(tags []string, from, to string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if tag == from {
+			tag = to
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// recordActivity appends an entry to a task's timeline. It is best-effort:
+// failures are logged and never surface to the caller's write.
+func (s *SQLiteStorage) recordActivity(ctx context.Context, taskID string, activityType models.ActivityType, payload interface{}) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("failed to marshal %s activity for task %s: %v", activityType, taskID, err)
+		return
+	}
+
+	_, err = s.conn().ExecContext(ctx, `
+		INSERT INTO activity (task_id, type, timestamp, payload)
+		VALUES (?, ?, ?, ?)
+	`, taskID, activityType, time.Now(), string(payloadJSON))
+	if err != nil {
+		log.Printf("failed to record %s activity for task %s: %v", activityType, taskID, err)
+	}
+}
+
+func (s *SQLiteStorage) GetTaskActivity(ctx context.Context, taskID string) ([]models.Activity, error) {
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, task_id, type, timestamp, payload
+		FROM activity WHERE task_id = ?
+		ORDER BY timestamp ASC, id ASC
+	`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+
+	var activities []models.Activity
+	for rows.Next() {
+		var activity models.Activity
+		var payload string
+		if err := rows.Scan(&activity.ID, &activity.TaskID, &activity.Type, &activity.Timestamp, &payload); err != nil {
+			return nil, err
+		}
+		activity.Payload = json.RawMessage(payload)
+		activities = append(activities, activity)
+	}
+
+	return activities, rows.Err()
 }
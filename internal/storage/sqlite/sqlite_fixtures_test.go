@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"context"
 	"testing"
 
 	"michishirube/internal/models"
@@ -12,6 +13,7 @@ import (
 )
 
 func TestSQLiteStorage_WithFixtures(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 	
@@ -27,24 +29,24 @@ func TestSQLiteStorage_WithFixtures(t *testing.T) {
 	
 	// Insert all fixture data
 	for _, task := range tasks {
-		err := store.CreateTask(&task)
+		err := store.CreateTask(ctx, &task)
 		require.NoError(t, err)
 	}
 	
 	for _, link := range links {
-		err := store.CreateLink(&link)
+		err := store.CreateLink(ctx, &link)
 		require.NoError(t, err)
 	}
 	
 	for _, comment := range comments {
-		err := store.CreateComment(&comment)
+		err := store.CreateComment(ctx, &comment)
 		require.NoError(t, err)
 	}
 	
 	// Test various scenarios with realistic data
 	t.Run("list tasks with filters", func(t *testing.T) {
 		// Get high priority tasks
-		highPriorityTasks, err := store.ListTasks(storage.TaskFilters{
+		highPriorityTasks, err := store.ListTasks(ctx, storage.TaskFilters{
 			Priority: []models.Priority{models.High, models.Critical},
 		})
 		require.NoError(t, err)
@@ -63,7 +65,7 @@ func TestSQLiteStorage_WithFixtures(t *testing.T) {
 	
 	t.Run("search realistic content", func(t *testing.T) {
 		// Search for memory-related tasks
-		results, err := store.SearchTasks("memory", false, 10)
+		results, err := store.SearchTasks(ctx, "memory", false, false, 10)
 		require.NoError(t, err)
 		
 		// Should find the memory leak task
@@ -77,12 +79,12 @@ func TestSQLiteStorage_WithFixtures(t *testing.T) {
 		taskID := tasks[0].ID
 		
 		// Get task details
-		task, err := store.GetTask(taskID)
+		task, err := store.GetTask(ctx, taskID)
 		require.NoError(t, err)
 		assert.Equal(t, "Fix memory leak in pod controller", task.Title)
 		
 		// Get related links
-		taskLinks, err := store.GetTaskLinks(taskID)
+		taskLinks, err := store.GetTaskLinks(ctx, taskID)
 		require.NoError(t, err)
 		assert.Len(t, taskLinks, 3) // PR + Slack + Jira from fixtures
 		
@@ -96,7 +98,7 @@ func TestSQLiteStorage_WithFixtures(t *testing.T) {
 		assert.True(t, linkTypes[models.JiraTicket])
 		
 		// Get related comments
-		taskComments, err := store.GetTaskComments(taskID)
+		taskComments, err := store.GetTaskComments(ctx, taskID)
 		require.NoError(t, err)
 		assert.Len(t, taskComments, 3) // 3 comments from fixtures
 		
@@ -109,6 +111,7 @@ func TestSQLiteStorage_WithFixtures(t *testing.T) {
 }
 
 func TestSQLiteStorage_SearchScenario(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 	
@@ -128,14 +131,14 @@ func TestSQLiteStorage_SearchScenario(t *testing.T) {
 	
 	// Insert scenario tasks
 	for _, task := range scenario.Tasks {
-		err := store.CreateTask(&task)
+		err := store.CreateTask(ctx, &task)
 		require.NoError(t, err)
 	}
 	
 	// Run all search test cases
 	for _, testCase := range scenario.SearchTestCases {
 		t.Run(testCase.Description, func(t *testing.T) {
-			results, err := store.SearchTasks(testCase.Query, false, 10)
+			results, err := store.SearchTasks(ctx, testCase.Query, false, false, 10)
 			require.NoError(t, err)
 			
 			// Extract IDs from results
@@ -152,6 +155,7 @@ func TestSQLiteStorage_SearchScenario(t *testing.T) {
 }
 
 func TestSQLiteStorage_WorkflowScenario(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 	
@@ -173,7 +177,7 @@ func TestSQLiteStorage_WorkflowScenario(t *testing.T) {
 	
 	// Create initial task
 	task := scenario.Task
-	err := store.CreateTask(&task)
+	err := store.CreateTask(ctx, &task)
 	require.NoError(t, err)
 	
 	// Test workflow transitions
@@ -183,11 +187,11 @@ func TestSQLiteStorage_WorkflowScenario(t *testing.T) {
 			task.Status = models.Status(step.ExpectedStatus)
 			task.Blockers = step.ExpectedBlockers
 			
-			err := store.UpdateTask(&task)
+			err := store.UpdateTask(ctx, &task)
 			require.NoError(t, err)
 			
 			// Verify the update
-			retrieved, err := store.GetTask(task.ID)
+			retrieved, err := store.GetTask(ctx, task.ID)
 			require.NoError(t, err)
 			
 			assert.Equal(t, step.ExpectedStatus, string(retrieved.Status))
@@ -197,24 +201,24 @@ func TestSQLiteStorage_WorkflowScenario(t *testing.T) {
 	
 	// Add links and comments
 	for _, link := range scenario.Links {
-		err := store.CreateLink(&link)
+		err := store.CreateLink(ctx, &link)
 		require.NoError(t, err)
 	}
 	
 	for _, comment := range scenario.Comments {
-		err := store.CreateComment(&comment)
+		err := store.CreateComment(ctx, &comment)
 		require.NoError(t, err)
 	}
 	
 	// Verify final state
-	finalTask, err := store.GetTask(task.ID)
+	finalTask, err := store.GetTask(ctx, task.ID)
 	require.NoError(t, err)
 	
-	finalLinks, err := store.GetTaskLinks(task.ID)
+	finalLinks, err := store.GetTaskLinks(ctx, task.ID)
 	require.NoError(t, err)
 	assert.Len(t, finalLinks, len(scenario.Links))
 	
-	finalComments, err := store.GetTaskComments(task.ID)
+	finalComments, err := store.GetTaskComments(ctx, task.ID)
 	require.NoError(t, err)
 	assert.Len(t, finalComments, len(scenario.Comments))
 	
@@ -1,8 +1,12 @@
 package sqlite
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -52,12 +56,13 @@ func createTestTask(_ *testing.T) *models.Task {
 }
 
 func TestSQLiteStorage_CreateTask(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	task := createTestTask(t)
 
-	err := store.CreateTask(task)
+	err := store.CreateTask(ctx, task)
 	require.NoError(t, err)
 
 	// Verify task was created with ID and timestamps
@@ -68,6 +73,7 @@ func TestSQLiteStorage_CreateTask(t *testing.T) {
 }
 
 func TestSQLiteStorage_CreateTask_WithDefaults(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -75,7 +81,7 @@ func TestSQLiteStorage_CreateTask_WithDefaults(t *testing.T) {
 		Title: "Minimal Task",
 	}
 
-	err := store.CreateTask(task)
+	err := store.CreateTask(ctx, task)
 	require.NoError(t, err)
 
 	// Verify defaults were applied
@@ -84,7 +90,56 @@ func TestSQLiteStorage_CreateTask_WithDefaults(t *testing.T) {
 	assert.Equal(t, models.DefaultStatus, task.Status)
 }
 
+func TestSQLiteStorage_CreateTaskWithLinks(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := createTestTask(t)
+	links := []*models.Link{
+		{Type: models.PullRequest, URL: "https://github.com/org/repo/pull/1", Title: "PR", Status: "open"},
+		{Type: models.JiraTicket, URL: "https://issues.redhat.com/browse/TEST-123", Title: "Ticket", Status: "active"},
+	}
+
+	require.NoError(t, store.CreateTaskWithLinks(ctx, task, links))
+	assert.NotEmpty(t, task.ID)
+
+	stored, err := store.GetTaskLinks(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+	for _, link := range links {
+		assert.NotEmpty(t, link.ID)
+		assert.Equal(t, task.ID, link.TaskID)
+	}
+
+	activity, err := store.GetTaskActivity(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, activity, 3)
+}
+
+func TestSQLiteStorage_CreateTaskWithLinks_RollsBackOnInvalidLink(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := createTestTask(t)
+	links := []*models.Link{
+		{Type: models.PullRequest, URL: "https://github.com/org/repo/pull/1", Title: "PR", Status: "open"},
+		{Type: models.PullRequest, Title: "No URL", Status: "open"},
+	}
+
+	err := store.CreateTaskWithLinks(ctx, task, links)
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "links[1].url", validationErr.Field)
+
+	tasks, err := store.ListTasks(ctx, storage.TaskFilters{IncludeArchived: true})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
 func TestSQLiteStorage_CreateTask_ValidationError(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -93,7 +148,7 @@ func TestSQLiteStorage_CreateTask_ValidationError(t *testing.T) {
 		Priority: models.High,
 	}
 
-	err := store.CreateTask(task)
+	err := store.CreateTask(ctx, task)
 	require.Error(t, err)
 
 	// Should be validation error
@@ -103,16 +158,17 @@ func TestSQLiteStorage_CreateTask_ValidationError(t *testing.T) {
 }
 
 func TestSQLiteStorage_GetTask(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	// Create task
 	original := createTestTask(t)
-	err := store.CreateTask(original)
+	err := store.CreateTask(ctx, original)
 	require.NoError(t, err)
 
 	// Get task
-	retrieved, err := store.GetTask(original.ID)
+	retrieved, err := store.GetTask(ctx, original.ID)
 	require.NoError(t, err)
 
 	// Verify all fields match
@@ -128,22 +184,62 @@ func TestSQLiteStorage_GetTask(t *testing.T) {
 }
 
 func TestSQLiteStorage_GetTask_NotFound(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	task, err := store.GetTask("nonexistent-id")
+	task, err := store.GetTask(ctx, "nonexistent-id")
 	assert.Error(t, err)
 	assert.Nil(t, task)
 	assert.Contains(t, err.Error(), "not found")
 }
 
+func TestSQLiteStorage_CreateTask_ConfiguredDefaults(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, models.Configure(models.Limits{NewTaskPriority: models.High, NewTaskStatus: models.InProgress}))
+	defer func() { _ = models.Configure(models.Limits{}) }()
+
+	task := &models.Task{Title: "No priority given"}
+	require.NoError(t, store.CreateTask(ctx, task))
+
+	stored, err := store.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.High, stored.Priority)
+	assert.Equal(t, models.InProgress, stored.Status)
+}
+
+func TestSQLiteStorage_GetTasks(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	first := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, first))
+	second := createTestTask(t)
+	second.Title = "Second task"
+	require.NoError(t, store.CreateTask(ctx, second))
+
+	tasks, err := store.GetTasks(ctx, []string{second.ID, "non-existent", first.ID})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, first.ID, tasks[first.ID].ID)
+	assert.Equal(t, "Second task", tasks[second.ID].Title)
+	assert.Equal(t, first.Tags, tasks[first.ID].Tags)
+	assert.Equal(t, first.Blockers, tasks[first.ID].Blockers)
+	assert.NotContains(t, tasks, "non-existent")
+}
+
 func TestSQLiteStorage_UpdateTask(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	// Create task
 	task := createTestTask(t)
-	err := store.CreateTask(task)
+	err := store.CreateTask(ctx, task)
 	require.NoError(t, err)
 
 	originalUpdatedAt := task.UpdatedAt
@@ -154,14 +250,14 @@ func TestSQLiteStorage_UpdateTask(t *testing.T) {
 	task.Status = models.Done
 	task.Tags = []string{"updated", "test"}
 
-	err = store.UpdateTask(task)
+	err = store.UpdateTask(ctx, task)
 	require.NoError(t, err)
 
 	// Verify updated_at changed
 	assert.True(t, task.UpdatedAt.After(originalUpdatedAt))
 
 	// Verify changes were persisted
-	retrieved, err := store.GetTask(task.ID)
+	retrieved, err := store.GetTask(ctx, task.ID)
 	require.NoError(t, err)
 
 	assert.Equal(t, "Updated Title", retrieved.Title)
@@ -170,24 +266,128 @@ func TestSQLiteStorage_UpdateTask(t *testing.T) {
 }
 
 func TestSQLiteStorage_DeleteTask(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	// Create task
 	task := createTestTask(t)
-	err := store.CreateTask(task)
+	err := store.CreateTask(ctx, task)
 	require.NoError(t, err)
 
 	// Delete task
-	err = store.DeleteTask(task.ID)
+	err = store.DeleteTask(ctx, task.ID)
 	require.NoError(t, err)
 
 	// Verify task is gone
-	_, err = store.GetTask(task.ID)
+	_, err = store.GetTask(ctx, task.ID)
+	assert.Error(t, err)
+}
+
+func TestSQLiteStorage_PurgeArchived(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	seed := func(id string, status models.Status, updatedAt time.Time) *models.Task {
+		task := createTestTask(t)
+		task.ID = id
+		task.Status = status
+		task.CreatedAt = updatedAt
+		task.UpdatedAt = updatedAt
+		return task
+	}
+
+	// ImportData preserves the seeded timestamps
+	_, err := store.ImportData(ctx, &models.ExportData{
+		Tasks: []*models.Task{
+			seed("old-archived", models.Archived, now.AddDate(0, 0, -90)),
+			seed("recent-archived", models.Archived, now.AddDate(0, 0, -5)),
+			seed("old-done", models.Done, now.AddDate(0, 0, -90)),
+		},
+		Links: []*models.Link{
+			{ID: "link-1", TaskID: "old-archived", Type: models.PullRequest, URL: "https://github.com/org/repo/pull/1", Title: "PR", Status: "merged"},
+		},
+		Comments: []*models.Comment{
+			{ID: "comment-1", TaskID: "old-archived", Content: "Wrapped up"},
+		},
+	})
+	require.NoError(t, err)
+
+	purged, err := store.PurgeArchived(ctx, now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	_, err = store.GetTask(ctx, "old-archived")
+	assert.Error(t, err)
+	_, err = store.GetTask(ctx, "recent-archived")
+	assert.NoError(t, err)
+	_, err = store.GetTask(ctx, "old-done")
+	assert.NoError(t, err)
+
+	_, err = store.GetLink(ctx, "link-1")
+	assert.Error(t, err)
+	_, err = store.GetComment(ctx, "comment-1")
 	assert.Error(t, err)
 }
 
+func TestSQLiteStorage_EmptyListColumns(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tagged := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, tagged))
+
+	// Rows imported outside the app may hold '' instead of '[]'
+	_, err := store.db.ExecContext(ctx, `INSERT INTO tasks (id, jira_id, title, priority, status, tags, blockers, custom_fields, created_at, updated_at)
+		VALUES ('legacy', 'NO-JIRA', 'Legacy row', 'normal', 'new', '', '', '', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+
+	task, err := store.GetTask(ctx, "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, task.Tags)
+	assert.Equal(t, []string{}, task.Blockers)
+	assert.Nil(t, task.CustomFields)
+
+	tasks, err := store.ListTasks(ctx, storage.TaskFilters{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2, "one bad row doesn't break the list")
+
+	tasks, err = store.ListTasks(ctx, storage.TaskFilters{CustomFields: map[string]string{"sprint": "12"}})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	tasks, err = store.ListTasks(ctx, storage.TaskFilters{Tags: []string{"test"}})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+
+	tags, err := store.ListTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"test": 1, "unit": 1}, tags)
+
+	_, err = store.GetRelatedTasks(ctx, tagged.ID, 10)
+	require.NoError(t, err)
+}
+
+func TestSQLiteStorage_NotFound(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	_, err := store.GetTask(ctx, "missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	_, err = store.GetLink(ctx, "missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	_, err = store.GetComment(ctx, "missing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
 func TestSQLiteStorage_ListTasks(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -200,7 +400,7 @@ func TestSQLiteStorage_ListTasks(t *testing.T) {
 	}
 
 	for _, task := range tasks {
-		err := store.CreateTask(task)
+		err := store.CreateTask(ctx, task)
 		require.NoError(t, err)
 	}
 
@@ -238,7 +438,7 @@ func TestSQLiteStorage_ListTasks(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := store.ListTasks(tt.filters)
+			result, err := store.ListTasks(ctx, tt.filters)
 			require.NoError(t, err)
 			assert.Len(t, result, tt.expected)
 		})
@@ -246,6 +446,7 @@ func TestSQLiteStorage_ListTasks(t *testing.T) {
 }
 
 func TestSQLiteStorage_SearchTasks(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -257,7 +458,7 @@ func TestSQLiteStorage_SearchTasks(t *testing.T) {
 	}
 
 	for _, task := range tasks {
-		err := store.CreateTask(task)
+		err := store.CreateTask(ctx, task)
 		require.NoError(t, err)
 	}
 
@@ -290,20 +491,56 @@ func TestSQLiteStorage_SearchTasks(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := store.SearchTasks(tt.query, false, 10)
+			result, err := store.SearchTasks(ctx, tt.query, false, false, 10)
 			require.NoError(t, err)
 			assert.Len(t, result, tt.expected)
 		})
 	}
 }
 
+func TestSQLiteStorage_SearchTasks_IncludeComments(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	commented := &models.Task{Title: "Investigate flaky upgrade", JiraID: "BUG-1"}
+	titled := &models.Task{Title: "Etcd quorum loss runbook", JiraID: "DOC-2"}
+	unrelated := &models.Task{Title: "Add new feature", JiraID: "FEAT-3"}
+	for _, task := range []*models.Task{commented, titled, unrelated} {
+		require.NoError(t, store.CreateTask(ctx, task))
+	}
+
+	// Two matching comments on the same task must not duplicate it
+	for _, content := range []string{"Root cause looks like etcd quorum loss", "Confirmed: etcd quorum loss again"} {
+		require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: commented.ID, Content: content}))
+	}
+	require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: unrelated.ID, Content: "Looks good"}))
+
+	result, err := store.SearchTasks(ctx, "quorum loss", false, false, 10)
+	require.NoError(t, err)
+	require.Len(t, result, 1, "comments are ignored unless requested")
+	assert.Equal(t, titled.ID, result[0].ID)
+
+	result, err = store.SearchTasks(ctx, "quorum loss", false, true, 10)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, titled.ID, result[0].ID, "title matches rank before comment-only matches")
+	assert.Equal(t, commented.ID, result[1].ID)
+
+	result, err = store.SearchTasks(ctx, "Root cause", false, true, 10)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, commented.ID, result[0].ID)
+}
+
 func TestSQLiteStorage_LinkOperations(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	// Create a task first
 	task := createTestTask(t)
-	err := store.CreateTask(task)
+	err := store.CreateTask(ctx, task)
 	require.NoError(t, err)
 
 	// Create link
@@ -316,12 +553,12 @@ func TestSQLiteStorage_LinkOperations(t *testing.T) {
 	}
 
 	// Test CreateLink
-	err = store.CreateLink(link)
+	err = store.CreateLink(ctx, link)
 	require.NoError(t, err)
 	assert.NotEmpty(t, link.ID)
 
 	// Test GetLink
-	retrieved, err := store.GetLink(link.ID)
+	retrieved, err := store.GetLink(ctx, link.ID)
 	require.NoError(t, err)
 	assert.Equal(t, link.TaskID, retrieved.TaskID)
 	assert.Equal(t, link.Type, retrieved.Type)
@@ -332,35 +569,135 @@ func TestSQLiteStorage_LinkOperations(t *testing.T) {
 	// Test UpdateLink
 	link.Status = "merged"
 	link.Title = "Fix memory leak - merged"
-	err = store.UpdateLink(link)
+	err = store.UpdateLink(ctx, link)
 	require.NoError(t, err)
 
-	updated, err := store.GetLink(link.ID)
+	updated, err := store.GetLink(ctx, link.ID)
 	require.NoError(t, err)
 	assert.Equal(t, "merged", updated.Status)
 	assert.Equal(t, "Fix memory leak - merged", updated.Title)
 
 	// Test GetTaskLinks
-	links, err := store.GetTaskLinks(task.ID)
+	links, err := store.GetTaskLinks(ctx, task.ID)
 	require.NoError(t, err)
 	assert.Len(t, links, 1)
 	assert.Equal(t, link.ID, links[0].ID)
 
 	// Test DeleteLink
-	err = store.DeleteLink(link.ID)
+	err = store.DeleteLink(ctx, link.ID)
 	require.NoError(t, err)
 
-	_, err = store.GetLink(link.ID)
+	_, err = store.GetLink(ctx, link.ID)
 	assert.Error(t, err)
 }
 
+func TestSQLiteStorage_GetTaskLinksByType(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, task))
+	other := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, other))
+
+	seed := []struct {
+		taskID   string
+		linkType models.LinkType
+		url      string
+	}{
+		{task.ID, models.PullRequest, "https://github.com/org/repo/pull/1"},
+		{task.ID, models.PullRequest, "https://github.com/org/repo/pull/2"},
+		{task.ID, models.JiraTicket, "https://issues.example.com/browse/TEST-1"},
+		{task.ID, models.Documentation, "https://docs.example.com/guide"},
+		{other.ID, models.PullRequest, "https://github.com/org/repo/pull/3"},
+	}
+	for _, s := range seed {
+		require.NoError(t, store.CreateLink(ctx, &models.Link{TaskID: s.taskID, Type: s.linkType, URL: s.url}))
+	}
+
+	typesOf := func(links []*models.Link) []models.LinkType {
+		var types []models.LinkType
+		for _, link := range links {
+			assert.Equal(t, task.ID, link.TaskID)
+			types = append(types, link.Type)
+		}
+		return types
+	}
+
+	links, err := store.GetTaskLinksByType(ctx, task.ID, models.PullRequest)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []models.LinkType{models.PullRequest, models.PullRequest}, typesOf(links))
+
+	links, err = store.GetTaskLinksByType(ctx, task.ID, models.PullRequest, models.JiraTicket)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []models.LinkType{models.PullRequest, models.PullRequest, models.JiraTicket}, typesOf(links))
+
+	links, err = store.GetTaskLinksByType(ctx, task.ID, models.Commit)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	// No types means every link of the task
+	links, err = store.GetTaskLinksByType(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, links, 4)
+}
+
+func TestSQLiteStorage_UpdateLinkMergesMetadata(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, task))
+
+	link := &models.Link{
+		TaskID:   task.ID,
+		Type:     models.PullRequest,
+		URL:      "https://github.com/org/repo/pull/1",
+		Metadata: `{"a":1,"nested":{"x":1}}`,
+	}
+	require.NoError(t, store.CreateLink(ctx, link))
+
+	// An editor that only knows about "b" must not drop "a"
+	edit := *link
+	edit.Title = "Renamed"
+	edit.Metadata = `{"b":2,"nested":{"y":2}}`
+	require.NoError(t, store.UpdateLinkWithOptions(ctx, &edit, storage.UpdateLinkOptions{MergeMetadata: true}))
+
+	expected := map[string]any{
+		"a":      float64(1),
+		"b":      float64(2),
+		"nested": map[string]any{"x": float64(1), "y": float64(2)},
+	}
+	assert.Equal(t, expected, edit.MetadataMap(), "merged metadata is written back to the link")
+
+	stored, err := store.GetLink(ctx, link.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", stored.Title)
+	assert.Equal(t, expected, stored.MetadataMap())
+
+	// A plain update still replaces the metadata
+	edit.Metadata = `{"c":3}`
+	require.NoError(t, store.UpdateLink(ctx, &edit))
+	stored, err = store.GetLink(ctx, link.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"c": float64(3)}, stored.MetadataMap())
+
+	missing := edit
+	missing.ID = "missing"
+	err = store.UpdateLinkWithOptions(ctx, &missing, storage.UpdateLinkOptions{MergeMetadata: true})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
 func TestSQLiteStorage_LinkValidation(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	// Create task first
 	task := createTestTask(t)
-	err := store.CreateTask(task)
+	err := store.CreateTask(ctx, task)
 	require.NoError(t, err)
 
 	// Test link with missing URL
@@ -370,21 +707,44 @@ func TestSQLiteStorage_LinkValidation(t *testing.T) {
 		Title:  "Test Link",
 	}
 
-	err = store.CreateLink(link)
+	err = store.CreateLink(ctx, link)
 	require.Error(t, err)
 
 	var validationErr *models.ValidationError
 	ok := errors.As(err, &validationErr)
 	assert.True(t, ok)
+
+	// Test link with metadata that isn't JSON
+	link.URL = "https://github.com/org/repo/pull/1"
+	link.Metadata = "not json"
+	err = store.CreateLink(ctx, link)
+	require.Error(t, err)
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "metadata", validationErr.Field)
+
+	// Empty metadata defaults to an empty object
+	link.Metadata = ""
+	require.NoError(t, store.CreateLink(ctx, link))
+	stored, err := store.GetLink(ctx, link.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", stored.Metadata)
+
+	// Updates are validated too
+	stored.Metadata = "{broken"
+	err = store.UpdateLink(ctx, stored)
+	require.Error(t, err)
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "metadata", validationErr.Field)
 }
 
 func TestSQLiteStorage_CommentOperations(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	// Create a task first
 	task := createTestTask(t)
-	err := store.CreateTask(task)
+	err := store.CreateTask(ctx, task)
 	require.NoError(t, err)
 
 	// Create comment
@@ -394,13 +754,13 @@ func TestSQLiteStorage_CommentOperations(t *testing.T) {
 	}
 
 	// Test CreateComment
-	err = store.CreateComment(comment)
+	err = store.CreateComment(ctx, comment)
 	require.NoError(t, err)
 	assert.NotEmpty(t, comment.ID)
 	assert.False(t, comment.CreatedAt.IsZero())
 
 	// Test GetComment
-	retrieved, err := store.GetComment(comment.ID)
+	retrieved, err := store.GetComment(ctx, comment.ID)
 	require.NoError(t, err)
 	assert.Equal(t, comment.TaskID, retrieved.TaskID)
 	assert.Equal(t, comment.Content, retrieved.Content)
@@ -411,30 +771,126 @@ func TestSQLiteStorage_CommentOperations(t *testing.T) {
 		TaskID:  task.ID,
 		Content: "Second comment",
 	}
-	err = store.CreateComment(comment2)
+	err = store.CreateComment(ctx, comment2)
 	require.NoError(t, err)
 
 	// Test GetTaskComments (should be ordered by created_at)
-	comments, err := store.GetTaskComments(task.ID)
+	comments, err := store.GetTaskComments(ctx, task.ID)
 	require.NoError(t, err)
 	assert.Len(t, comments, 2)
 	assert.Equal(t, comment.ID, comments[0].ID) // First comment should be first
 	assert.Equal(t, comment2.ID, comments[1].ID)
 
 	// Test DeleteComment
-	err = store.DeleteComment(comment.ID)
+	err = store.DeleteComment(ctx, comment.ID)
 	require.NoError(t, err)
 
-	_, err = store.GetComment(comment.ID)
+	_, err = store.GetComment(ctx, comment.ID)
 	assert.Error(t, err)
 
 	// Verify only one comment remains
-	comments, err = store.GetTaskComments(task.ID)
+	comments, err = store.GetTaskComments(ctx, task.ID)
 	require.NoError(t, err)
 	assert.Len(t, comments, 1)
 }
 
+func TestSQLiteStorage_GetTaskCommentsPaged(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, task))
+
+	// Seed comments with increasing timestamps; ImportData preserves them
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	var comments []*models.Comment
+	for i := 0; i < 25; i++ {
+		comments = append(comments, &models.Comment{
+			ID:        fmt.Sprintf("comment-%02d", i),
+			TaskID:    task.ID,
+			Content:   fmt.Sprintf("Update %d", i),
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	_, err := store.ImportData(ctx, &models.ExportData{Comments: comments})
+	require.NoError(t, err)
+
+	other := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, other))
+	require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: other.ID, Content: "Elsewhere"}))
+
+	tests := []struct {
+		name          string
+		limit, offset int
+		expectedIDs   []string
+	}{
+		{"first page", 10, 0, []string{"comment-00", "comment-01", "comment-02", "comment-03", "comment-04", "comment-05", "comment-06", "comment-07", "comment-08", "comment-09"}},
+		{"last partial page", 10, 20, []string{"comment-20", "comment-21", "comment-22", "comment-23", "comment-24"}},
+		{"past the end", 10, 30, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, total, err := store.GetTaskCommentsPaged(ctx, task.ID, tt.limit, tt.offset)
+			require.NoError(t, err)
+			assert.Equal(t, 25, total)
+
+			var ids []string
+			for _, comment := range page {
+				ids = append(ids, comment.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+
+	all, total, err := store.GetTaskCommentsPaged(ctx, task.ID, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 25, total)
+	assert.Len(t, all, 25)
+}
+
+func TestSQLiteStorage_CountTaskRelations(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Tasks with links only, comments only, both, and neither
+	var tasks []*models.Task
+	for _, relations := range []struct{ links, comments int }{{3, 0}, {0, 2}, {1, 4}, {0, 0}} {
+		task := createTestTask(t)
+		require.NoError(t, store.CreateTask(ctx, task))
+		for i := 0; i < relations.links; i++ {
+			require.NoError(t, store.CreateLink(ctx, &models.Link{
+				TaskID: task.ID,
+				Type:   models.PullRequest,
+				URL:    fmt.Sprintf("https://github.com/org/repo/pull/%d", i),
+				Status: "open",
+			}))
+		}
+		for i := 0; i < relations.comments; i++ {
+			require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: task.ID, Content: fmt.Sprintf("Comment %d", i)}))
+		}
+		tasks = append(tasks, task)
+	}
+
+	counts, err := store.CountTaskRelations(ctx, []string{tasks[0].ID, tasks[1].ID, tasks[2].ID, tasks[3].ID, "missing"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]storage.TaskRelationCounts{
+		tasks[0].ID: {Links: 3, Comments: 0},
+		tasks[1].ID: {Links: 0, Comments: 2},
+		tasks[2].ID: {Links: 1, Comments: 4},
+		tasks[3].ID: {Links: 0, Comments: 0},
+	}, counts)
+
+	counts, err = store.CountTaskRelations(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
 func TestSQLiteStorage_CommentValidation(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
@@ -443,7 +899,7 @@ func TestSQLiteStorage_CommentValidation(t *testing.T) {
 		TaskID: "some-task-id",
 	}
 
-	err := store.CreateComment(comment)
+	err := store.CreateComment(ctx, comment)
 	require.Error(t, err)
 
 	var validationErr *models.ValidationError
@@ -452,12 +908,13 @@ func TestSQLiteStorage_CommentValidation(t *testing.T) {
 }
 
 func TestSQLiteStorage_CascadeDelete(t *testing.T) {
+	ctx := context.Background()
 	store, cleanup := setupTestDB(t)
 	defer cleanup()
 
 	// Create task with links and comments
 	task := createTestTask(t)
-	err := store.CreateTask(task)
+	err := store.CreateTask(ctx, task)
 	require.NoError(t, err)
 
 	// Create link
@@ -467,7 +924,7 @@ func TestSQLiteStorage_CascadeDelete(t *testing.T) {
 		URL:    "https://github.com/test/repo/pull/1",
 		Title:  "Test PR",
 	}
-	err = store.CreateLink(link)
+	err = store.CreateLink(ctx, link)
 	require.NoError(t, err)
 
 	// Create comment
@@ -475,25 +932,993 @@ func TestSQLiteStorage_CascadeDelete(t *testing.T) {
 		TaskID:  task.ID,
 		Content: "Test comment",
 	}
-	err = store.CreateComment(comment)
+	err = store.CreateComment(ctx, comment)
 	require.NoError(t, err)
 
 	// Delete task
-	err = store.DeleteTask(task.ID)
+	err = store.DeleteTask(ctx, task.ID)
 	require.NoError(t, err)
 
 	// Verify links and comments are also deleted (CASCADE)
-	_, err = store.GetLink(link.ID)
+	_, err = store.GetLink(ctx, link.ID)
 	assert.Error(t, err)
 
-	_, err = store.GetComment(comment.ID)
+	_, err = store.GetComment(ctx, comment.ID)
 	assert.Error(t, err)
 
-	links, err := store.GetTaskLinks(task.ID)
+	links, err := store.GetTaskLinks(ctx, task.ID)
 	require.NoError(t, err)
 	assert.Len(t, links, 0)
 
-	comments, err := store.GetTaskComments(task.ID)
+	comments, err := store.GetTaskComments(ctx, task.ID)
 	require.NoError(t, err)
 	assert.Len(t, comments, 0)
 }
+
+func TestSQLiteStorage_MergeTasks(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	source := &models.Task{Title: "Source Task", JiraID: "TEST-1", Priority: models.Critical, Tags: []string{"k8s", "memory"}, Blockers: []string{"waiting for review"}}
+	require.NoError(t, store.CreateTask(ctx, source))
+
+	target := &models.Task{Title: "Target Task", JiraID: "TEST-2", Priority: models.Normal, Tags: []string{"k8s", "api"}}
+	require.NoError(t, store.CreateTask(ctx, target))
+
+	link := &models.Link{TaskID: source.ID, Type: models.PullRequest, URL: "https://github.com/org/repo/pull/1"}
+	require.NoError(t, store.CreateLink(ctx, link))
+
+	comment := &models.Comment{TaskID: source.ID, Content: "Found the root cause"}
+	require.NoError(t, store.CreateComment(ctx, comment))
+
+	merged, err := store.MergeTasks(ctx, source.ID, target.ID, storage.MergeOptions{Prefer: storage.PreferTarget})
+	require.NoError(t, err)
+
+	// Target keeps its own scalar fields and gains the union of tags/blockers
+	assert.Equal(t, target.ID, merged.ID)
+	assert.Equal(t, "Target Task", merged.Title)
+	assert.Equal(t, models.Normal, merged.Priority)
+	assert.Equal(t, []string{"k8s", "api", "memory"}, merged.Tags)
+	assert.Equal(t, []string{"waiting for review"}, merged.Blockers)
+
+	// Links and comments moved to the target
+	links, err := store.GetTaskLinks(ctx, target.ID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, link.ID, links[0].ID)
+
+	comments, err := store.GetTaskComments(ctx, target.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, comment.ID, comments[0].ID)
+
+	sourceLinks, err := store.GetTaskLinks(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Len(t, sourceLinks, 0)
+
+	// Source is archived, not deleted
+	archived, err := store.GetTask(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Archived, archived.Status)
+}
+
+func TestSQLiteStorage_MergeTasks_PreferSourceAndDelete(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	source := &models.Task{Title: "Source Task", Priority: models.Critical}
+	require.NoError(t, store.CreateTask(ctx, source))
+
+	target := &models.Task{Title: "Target Task", Priority: models.Minor}
+	require.NoError(t, store.CreateTask(ctx, target))
+
+	merged, err := store.MergeTasks(ctx, source.ID, target.ID, storage.MergeOptions{Prefer: storage.PreferSource, DeleteSource: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Source Task", merged.Title)
+	assert.Equal(t, models.Critical, merged.Priority)
+
+	_, err = store.GetTask(ctx, source.ID)
+	assert.Error(t, err)
+}
+
+func TestSQLiteStorage_MergeTasks_Errors(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, task))
+
+	// Merging a task into itself is rejected
+	_, err := store.MergeTasks(ctx, task.ID, task.ID, storage.MergeOptions{})
+	var validationErr *models.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	// Missing source leaves the target untouched
+	_, err = store.MergeTasks(ctx, "missing", task.ID, storage.MergeOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	unchanged, err := store.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.Status, unchanged.Status)
+}
+
+func TestSQLiteStorage_Ping(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	require.NoError(t, store.Ping(ctx))
+
+	require.NoError(t, store.db.Close())
+	assert.Error(t, store.Ping(ctx))
+}
+
+func TestSQLiteStorage_Checkpoint(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	enabled, err := store.WALEnabled(ctx)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, store.CreateTask(ctx, createTestTask(t)))
+	}
+
+	result, err := store.Checkpoint(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Busy)
+	assert.Greater(t, result.Checkpointed, 0)
+	assert.Equal(t, result.LogPages, result.Checkpointed)
+}
+
+func TestSQLiteStorage_Optimize(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Bulky comments leave plenty of free pages behind once deleted
+	content := strings.Repeat("padding ", 500)
+	var tasks []*models.Task
+	for i := 0; i < 50; i++ {
+		task := createTestTask(t)
+		require.NoError(t, store.CreateTask(ctx, task))
+		for j := 0; j < 5; j++ {
+			require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: task.ID, Content: content}))
+		}
+		tasks = append(tasks, task)
+	}
+	for _, task := range tasks[:45] {
+		require.NoError(t, store.DeleteTask(ctx, task.ID))
+	}
+
+	before, err := store.DatabaseSize(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Optimize(ctx))
+
+	after, err := store.DatabaseSize(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, after, int64(0))
+	assert.Less(t, after, before, "deleted rows are reclaimed")
+
+	// The data that was kept is still readable
+	remaining, err := store.ListTasks(ctx, storage.TaskFilters{})
+	require.NoError(t, err)
+	assert.Len(t, remaining, 5)
+}
+
+func TestSQLiteStorage_Orphans(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, task))
+	require.NoError(t, store.CreateLink(ctx, &models.Link{TaskID: task.ID, Type: models.PullRequest, URL: "https://github.com/org/repo/pull/1", Status: "open"}))
+	require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: task.ID, Content: "Kept"}))
+
+	links, comments, err := store.FindOrphans(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+	assert.Empty(t, comments)
+
+	// Foreign keys are per connection, so switch them off on a dedicated one
+	// and back on before it returns to the pool
+	conn, err := store.db.Conn(ctx)
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, `INSERT INTO links (id, task_id, type, url, title)
+		VALUES ('orphan-link', 'missing-task', 'pull_request', 'https://github.com/org/repo/pull/2', '')`)
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, `INSERT INTO comments (id, task_id, content, created_at)
+		VALUES ('orphan-comment', 'missing-task', 'Lost', CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	links, comments, err = store.FindOrphans(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orphan-link"}, links)
+	assert.Equal(t, []string{"orphan-comment"}, comments)
+
+	// Exports stream links and comments in bulk and leave orphans out
+	var streamed []string
+	require.NoError(t, store.StreamLinks(ctx, func(link *models.Link) error {
+		streamed = append(streamed, link.ID)
+		return nil
+	}))
+	require.NoError(t, store.StreamComments(ctx, func(comment *models.Comment) error {
+		streamed = append(streamed, comment.ID)
+		return nil
+	}))
+	assert.Len(t, streamed, 2)
+	assert.NotContains(t, streamed, "orphan-link")
+	assert.NotContains(t, streamed, "orphan-comment")
+
+	links, comments, err = store.PurgeOrphans(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orphan-link"}, links)
+	assert.Equal(t, []string{"orphan-comment"}, comments)
+
+	links, comments, err = store.FindOrphans(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+	assert.Empty(t, comments)
+
+	// The task's own link and comment are untouched
+	taskLinks, err := store.GetTaskLinks(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, taskLinks, 1)
+	taskComments, err := store.GetTaskComments(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, taskComments, 1)
+}
+
+func TestSQLiteStorage_WithTransaction(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var kept *models.Task
+	err := store.WithTransaction(ctx, func(tx storage.Storage) error {
+		kept = createTestTask(t)
+		require.NoError(t, tx.CreateTask(ctx, kept))
+
+		// CreateTaskWithLinks opens a savepoint inside the transaction; its
+		// invalid link undoes only that call
+		failed := createTestTask(t)
+		err := tx.CreateTaskWithLinks(ctx, failed, []*models.Link{
+			{Type: models.Other, URL: "not a url", Status: "active"},
+		})
+		var validationErr *models.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+
+		return tx.CreateComment(ctx, &models.Comment{TaskID: kept.ID, Content: "note"})
+	})
+	require.NoError(t, err)
+
+	tasks, err := store.ListTasks(ctx, storage.TaskFilters{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, kept.ID, tasks[0].ID)
+
+	comments, err := store.GetTaskComments(ctx, kept.ID)
+	require.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	// Writes made through the transaction are invisible until it commits
+	err = store.WithTransaction(ctx, func(tx storage.Storage) error {
+		task := createTestTask(t)
+		require.NoError(t, tx.CreateTask(ctx, task))
+		_, err := tx.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+	tasks, err = store.ListTasks(ctx, storage.TaskFilters{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+}
+
+func TestSQLiteStorage_WithTransaction_CloseRefused(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	err := store.WithTransaction(ctx, func(tx storage.Storage) error {
+		return tx.Close()
+	})
+	assert.Error(t, err)
+	assert.NoError(t, store.Ping(ctx), "the database stays open")
+}
+
+func TestSQLiteStorage_ImportData(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	existing := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, existing))
+
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	imported := createTestTask(t)
+	imported.ID = "imported-task"
+	imported.CreatedAt = created
+	imported.UpdatedAt = created
+
+	data := &models.ExportData{
+		Tasks: []*models.Task{existing, imported},
+		Links: []*models.Link{{
+			ID:     "imported-link",
+			TaskID: imported.ID,
+			Type:   models.PullRequest,
+			URL:    "https://github.com/org/repo/pull/1",
+			Title:  "PR #1",
+			Status: "open",
+		}},
+		Comments: []*models.Comment{{
+			ID:        "imported-comment",
+			TaskID:    imported.ID,
+			Content:   "Restored from backup",
+			CreatedAt: created,
+		}},
+	}
+
+	result, err := store.ImportData(ctx, data)
+	require.NoError(t, err)
+	assert.Equal(t, &models.ImportResult{
+		TasksImported:    1,
+		TasksSkipped:     1,
+		LinksImported:    1,
+		CommentsImported: 1,
+	}, result)
+
+	got, err := store.GetTask(ctx, imported.ID)
+	require.NoError(t, err)
+	assert.True(t, created.Equal(got.CreatedAt))
+
+	links, err := store.GetTaskLinks(ctx, imported.ID)
+	require.NoError(t, err)
+	assert.Len(t, links, 1)
+
+	comments, err := store.GetTaskComments(ctx, imported.ID)
+	require.NoError(t, err)
+	assert.Len(t, comments, 1)
+}
+
+func TestSQLiteStorage_ImportData_RollsBackOnValidationError(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	valid := createTestTask(t)
+	valid.ID = "valid-task"
+	invalid := createTestTask(t)
+	invalid.Title = ""
+
+	_, err := store.ImportData(ctx, &models.ExportData{Tasks: []*models.Task{valid, invalid}})
+	require.Error(t, err)
+
+	_, err = store.GetTask(ctx, valid.ID)
+	assert.Error(t, err)
+}
+
+func TestSQLiteStorage_ListTasks_DateRanges(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	// Seed tasks with fixed timestamps; ImportData preserves them
+	var tasks []*models.Task
+	for _, d := range []int{8, 10, 15} {
+		task := createTestTask(t)
+		task.ID = fmt.Sprintf("task-%d", d)
+		task.CreatedAt = day(d)
+		task.UpdatedAt = day(d + 1)
+		tasks = append(tasks, task)
+	}
+	_, err := store.ImportData(ctx, &models.ExportData{Tasks: tasks})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		filters  storage.TaskFilters
+		expected []string
+	}{
+		{
+			name:     "created after is inclusive",
+			filters:  storage.TaskFilters{CreatedAfter: day(10)},
+			expected: []string{"task-15", "task-10"},
+		},
+		{
+			name:     "created before is exclusive",
+			filters:  storage.TaskFilters{CreatedBefore: day(10)},
+			expected: []string{"task-8"},
+		},
+		{
+			name:     "created range",
+			filters:  storage.TaskFilters{CreatedAfter: day(8), CreatedBefore: day(15)},
+			expected: []string{"task-10", "task-8"},
+		},
+		{
+			name:     "updated range",
+			filters:  storage.TaskFilters{UpdatedAfter: day(11), UpdatedBefore: day(16)},
+			expected: []string{"task-10"},
+		},
+		{
+			name: "bounds in another time zone",
+			filters: storage.TaskFilters{
+				CreatedAfter: day(10).In(time.FixedZone("UTC+9", 9*60*60)),
+			},
+			expected: []string{"task-15", "task-10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := store.ListTasks(ctx, tt.filters)
+			require.NoError(t, err)
+
+			var ids []string
+			for _, task := range result {
+				ids = append(ids, task.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestSQLiteStorage_TaskActivity(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	task := createTestTask(t)
+	task.Status = models.New
+	require.NoError(t, store.CreateTask(ctx, task))
+
+	task.Status = models.InProgress
+	require.NoError(t, store.UpdateTask(ctx, task))
+
+	task.Title = "Renamed task"
+	require.NoError(t, store.UpdateTask(ctx, task))
+
+	require.NoError(t, store.CreateLink(ctx, &models.Link{
+		TaskID: task.ID,
+		Type:   models.PullRequest,
+		URL:    "https://github.com/org/repo/pull/1",
+	}))
+	require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: task.ID, Content: "Started work"}))
+
+	activity, err := store.GetTaskActivity(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, activity, 5)
+
+	var types []models.ActivityType
+	for _, a := range activity {
+		assert.Equal(t, task.ID, a.TaskID)
+		types = append(types, a.Type)
+	}
+	assert.Equal(t, []models.ActivityType{
+		models.ActivityTaskCreated,
+		models.ActivityStatusChanged,
+		models.ActivityTaskUpdated,
+		models.ActivityLinkAdded,
+		models.ActivityCommentAdded,
+	}, types)
+	assert.JSONEq(t, `{"from":"new","to":"in_progress"}`, string(activity[1].Payload))
+	assert.False(t, activity[1].Timestamp.Before(activity[0].Timestamp))
+
+	// Deleting the task removes its timeline
+	require.NoError(t, store.DeleteTask(ctx, task.ID))
+	activity, err = store.GetTaskActivity(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Empty(t, activity)
+}
+
+func TestSQLiteStorage_TaskActivity_BestEffort(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Primary writes succeed even when the activity table is unavailable
+	_, err := store.db.Exec("DROP TABLE activity")
+	require.NoError(t, err)
+
+	task := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, task))
+	task.Status = models.Done
+	require.NoError(t, store.UpdateTask(ctx, task))
+	require.NoError(t, store.CreateComment(ctx, &models.Comment{TaskID: task.ID, Content: "Done"}))
+}
+
+func TestSQLiteStorage_GetTaskByJiraID(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	archived := createTestTask(t)
+	archived.JiraID = "OCPBUGS-100"
+	archived.Status = models.Archived
+	require.NoError(t, store.CreateTask(ctx, archived))
+
+	_, err := store.GetTaskByJiraID(ctx, "OCPBUGS-100")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	active := createTestTask(t)
+	active.JiraID = "OCPBUGS-100"
+	require.NoError(t, store.CreateTask(ctx, active))
+
+	found, err := store.GetTaskByJiraID(ctx, "OCPBUGS-100")
+	require.NoError(t, err)
+	assert.Equal(t, active.ID, found.ID)
+	assert.Equal(t, active.Tags, found.Tags)
+
+	_, err = store.GetTaskByJiraID(ctx, "OCPBUGS-999")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestSQLiteStorage_JournalModeOption(t *testing.T) {
+	ctx := context.Background()
+	dbPath := t.TempDir() + "/journal.db"
+
+	store, err := New(dbPath, WithJournalMode("delete"), WithSynchronous("full"))
+	require.NoError(t, err)
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Logf("failed to close store: %v", err)
+		}
+	}()
+
+	enabled, err := store.WALEnabled(ctx)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	var synchronous int
+	require.NoError(t, store.db.QueryRow("PRAGMA synchronous").Scan(&synchronous))
+	assert.Equal(t, 2, synchronous, "FULL")
+}
+
+func TestSQLiteStorage_PoolOptions(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+	assert.Equal(t, 4, store.db.Stats().MaxOpenConnections)
+
+	tuned, err := New(t.TempDir()+"/pool.db", WithMaxOpenConns(1), WithMaxIdleConns(1), WithConnMaxLifetime(time.Minute))
+	require.NoError(t, err)
+	defer func() {
+		if err := tuned.Close(); err != nil {
+			t.Logf("failed to close store: %v", err)
+		}
+	}()
+	assert.Equal(t, 1, tuned.db.Stats().MaxOpenConnections)
+
+	// A single connection still serves sequential work
+	require.NoError(t, tuned.CreateTask(ctx, createTestTask(t)))
+	tasks, err := tuned.ListTasks(ctx, storage.TaskFilters{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+}
+
+func TestSQLiteStorage_ConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	const workers = 8
+	const iterations = 20
+
+	errs := make(chan error, workers*iterations*2)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				task := &models.Task{
+					JiraID:   "NO-JIRA",
+					Title:    fmt.Sprintf("Concurrent task %d", i),
+					Priority: models.Normal,
+					Status:   models.New,
+				}
+				if err := store.CreateTask(ctx, task); err != nil {
+					errs <- err
+				}
+				if _, err := store.ListTasks(ctx, storage.TaskFilters{Limit: 10}); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	tasks, err := store.ListTasks(ctx, storage.TaskFilters{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, workers*iterations)
+}
+
+func TestSQLiteStorage_ContextCancellation(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, store.CreateTask(context.Background(), createTestTask(t)))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := store.ListTasks(ctx, storage.TaskFilters{})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = store.GetTask(ctx, "any")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = store.CreateTask(ctx, createTestTask(t))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Nothing was written by the cancelled call
+	tasks, err := store.ListTasks(context.Background(), storage.TaskFilters{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 5)
+
+	expired, cancelExpired := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancelExpired()
+	<-expired.Done()
+
+	_, err = store.SearchTasks(expired, "task", false, false, 10)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSQLiteStorage_ListTags(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	tags, err := store.ListTags(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	for _, task := range []*models.Task{
+		{JiraID: "NO-JIRA", Title: "One", Priority: models.Normal, Status: models.New, Tags: []string{"k8s", "network"}},
+		{JiraID: "NO-JIRA", Title: "Two", Priority: models.Normal, Status: models.InProgress, Tags: []string{"k8s", "storage"}},
+		{JiraID: "NO-JIRA", Title: "Three", Priority: models.Normal, Status: models.Done, Tags: []string{"k8s"}},
+		{JiraID: "NO-JIRA", Title: "Untagged", Priority: models.Normal, Status: models.New},
+		{JiraID: "NO-JIRA", Title: "Archived", Priority: models.Normal, Status: models.Archived, Tags: []string{"k8s", "legacy"}},
+	} {
+		require.NoError(t, store.CreateTask(ctx, task))
+	}
+
+	tags, err = store.ListTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"k8s": 3, "network": 1, "storage": 1}, tags)
+}
+
+func TestSQLiteStorage_RenameTag(t *testing.T) {
+	ctx := context.Background()
+
+	newTask := func(title string, tags ...string) *models.Task {
+		return &models.Task{JiraID: "NO-JIRA", Title: title, Priority: models.Normal, Status: models.New, Tags: tags}
+	}
+
+	t.Run("straight rename", func(t *testing.T) {
+		store, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		task := newTask("Rename", "k8s", "network")
+		require.NoError(t, store.CreateTask(ctx, task))
+		require.NoError(t, store.CreateTask(ctx, newTask("Untouched", "storage")))
+
+		affected, err := store.RenameTag(ctx, "k8s", "kubernetes")
+		require.NoError(t, err)
+		assert.Equal(t, 1, affected)
+
+		got, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"kubernetes", "network"}, got.Tags)
+	})
+
+	t.Run("merge into existing tag", func(t *testing.T) {
+		store, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		task := newTask("Both", "kubernetes", "k8s", "network")
+		require.NoError(t, store.CreateTask(ctx, task))
+
+		affected, err := store.RenameTag(ctx, "k8s", "kubernetes")
+		require.NoError(t, err)
+		assert.Equal(t, 1, affected)
+
+		got, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"kubernetes", "network"}, got.Tags)
+
+		tags, err := store.ListTags(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"kubernetes": 1, "network": 1}, tags)
+	})
+
+	t.Run("unused tag is a no-op", func(t *testing.T) {
+		store, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		task := newTask("Other", "network")
+		require.NoError(t, store.CreateTask(ctx, task))
+
+		affected, err := store.RenameTag(ctx, "k8s", "kubernetes")
+		require.NoError(t, err)
+		assert.Zero(t, affected)
+
+		got, err := store.GetTask(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"network"}, got.Tags)
+		assert.True(t, task.UpdatedAt.Equal(got.UpdatedAt))
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		store, cleanup := setupTestDB(t)
+		defer cleanup()
+
+		var validationErr *models.ValidationError
+		_, err := store.RenameTag(ctx, "", "kubernetes")
+		assert.True(t, errors.As(err, &validationErr))
+		_, err = store.RenameTag(ctx, "k8s", "k8s")
+		assert.True(t, errors.As(err, &validationErr))
+	})
+}
+
+func TestSQLiteStorage_ListTasks_Cursor(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Several tasks share a timestamp so the id tie-break is exercised
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	var seeded []*models.Task
+	for i := 0; i < 8; i++ {
+		task := createTestTask(t)
+		task.ID = fmt.Sprintf("task-%02d", i)
+		task.CreatedAt = base.Add(time.Duration(i/3) * time.Minute)
+		task.UpdatedAt = task.CreatedAt
+		seeded = append(seeded, task)
+	}
+	_, err := store.ImportData(ctx, &models.ExportData{Tasks: seeded})
+	require.NoError(t, err)
+
+	all, err := store.ListTasks(ctx, storage.TaskFilters{})
+	require.NoError(t, err)
+	require.Len(t, all, len(seeded))
+
+	var paged []string
+	filters := storage.TaskFilters{Limit: 3}
+	for pages := 0; pages < 10; pages++ {
+		page, err := store.ListTasks(ctx, filters)
+		require.NoError(t, err)
+		for _, task := range page {
+			paged = append(paged, task.ID)
+		}
+		if len(page) < filters.Limit {
+			break
+		}
+		filters.After = storage.CursorFor(page[len(page)-1])
+	}
+
+	var expected []string
+	for _, task := range all {
+		expected = append(expected, task.ID)
+	}
+	assert.Equal(t, expected, paged, "cursor pages must cover every task exactly once, in order")
+	assert.Equal(t, "task-07", paged[0])
+}
+
+func TestSQLiteStorage_GetRelatedTasks(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	newTask := func(title string, status models.Status, tags ...string) *models.Task {
+		task := &models.Task{JiraID: "NO-JIRA", Title: title, Priority: models.Normal, Status: status, Tags: tags}
+		require.NoError(t, store.CreateTask(ctx, task))
+		return task
+	}
+
+	source := newTask("Source", models.InProgress, "k8s", "network", "memory")
+	oneShared := newTask("One shared", models.New, "k8s", "docs")
+	twoShared := newTask("Two shared", models.New, "network", "memory", "ui")
+	newTask("Unrelated", models.New, "docs")
+	newTask("Untagged", models.New)
+	newTask("Archived", models.Archived, "k8s", "network", "memory")
+
+	related, err := store.GetRelatedTasks(ctx, source.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, related, 2)
+	assert.Equal(t, twoShared.ID, related[0].ID, "most shared tags ranks first")
+	assert.Equal(t, oneShared.ID, related[1].ID)
+
+	limited, err := store.GetRelatedTasks(ctx, source.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	assert.Equal(t, twoShared.ID, limited[0].ID)
+
+	none, err := store.GetRelatedTasks(ctx, "missing", 10)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestSQLiteStorage_RetriesWhileLocked(t *testing.T) {
+	ctx := context.Background()
+
+	// No busy timeout, so a held lock fails fast and only withRetry waits it out
+	store, err := New(t.TempDir()+"/locked.db",
+		WithBusyTimeout(0),
+		WithRetryAttempts(10),
+		WithRetryBaseDelay(5*time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Logf("failed to close store: %v", err)
+		}
+	}()
+
+	blocker := createTestTask(t)
+	blocker.JiraID = "LOCK-1"
+	require.NoError(t, store.CreateTask(ctx, blocker))
+
+	// Take the write lock on another connection
+	tx, err := store.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "UPDATE tasks SET title = ? WHERE id = ?", "Held", blocker.ID)
+	require.NoError(t, err)
+
+	released := make(chan error, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		released <- tx.Commit()
+	}()
+
+	task := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, task), "write succeeds once the lock is released")
+	require.NoError(t, <-released)
+
+	stored, err := store.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.Title, stored.Title)
+}
+
+func TestSQLiteStorage_MergeRetriesWhileLocked(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := New(t.TempDir()+"/locked.db",
+		WithBusyTimeout(0),
+		WithRetryAttempts(10),
+		WithRetryBaseDelay(5*time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Logf("failed to close store: %v", err)
+		}
+	}()
+
+	source := createTestTask(t)
+	target := createTestTask(t)
+	require.NoError(t, store.CreateTask(ctx, source))
+	require.NoError(t, store.CreateTask(ctx, target))
+
+	// Take the write lock on another connection
+	tx, err := store.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "UPDATE tasks SET title = ? WHERE id = ?", "Held", target.ID)
+	require.NoError(t, err)
+
+	released := make(chan error, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		released <- tx.Commit()
+	}()
+
+	_, err = store.MergeTasks(ctx, source.ID, target.ID, storage.MergeOptions{})
+	require.NoError(t, err, "merge succeeds once the lock is released")
+	require.NoError(t, <-released)
+
+	retired, err := store.GetTask(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Archived, retired.Status)
+}
+
+func TestSQLiteStorage_WithRetry(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("other errors return immediately", func(t *testing.T) {
+		calls := 0
+		err := store.withRetry(func() error {
+			calls++
+			return errors.New("constraint failed")
+		})
+		assert.EqualError(t, err, "constraint failed")
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("success stops retrying", func(t *testing.T) {
+		calls := 0
+		err := store.withRetry(func() error {
+			calls++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestSQLiteStorage_EncryptionKey(t *testing.T) {
+	ctx := context.Background()
+	dbPath := t.TempDir() + "/encrypted.db"
+
+	s, err := New(dbPath, WithEncryptionKey("correct horse"))
+	if errors.Is(err, ErrEncryptionUnsupported) {
+		t.Skip("SQLite driver built without SQLCipher")
+	}
+	require.NoError(t, err)
+
+	task := &models.Task{Title: "Secret", JiraID: "SEC-1"}
+	require.NoError(t, s.CreateTask(ctx, task))
+	require.NoError(t, s.Close())
+
+	_, err = New(dbPath, WithEncryptionKey("battery staple"))
+	assert.Error(t, err, "a wrong key can't read the database")
+
+	_, err = New(dbPath)
+	assert.Error(t, err, "nor can opening it without a key")
+
+	s, err = New(dbPath, WithEncryptionKey("correct horse"))
+	require.NoError(t, err)
+	defer func() {
+		if err := s.Close(); err != nil {
+			t.Logf("failed to close storage: %v", err)
+		}
+	}()
+	got, err := s.GetTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Secret", got.Title)
+}
+
+func TestQuoteKey(t *testing.T) {
+	assert.Equal(t, "'plain'", quoteKey("plain"))
+	assert.Equal(t, "'it''s'", quoteKey("it's"))
+}
+
+func TestSQLiteStorage_SchemaVersion(t *testing.T) {
+	store, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var applied int
+	require.NoError(t, store.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&applied))
+
+	version, err := store.SchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, applied, version, "one version per applied migration")
+	assert.Equal(t, len(migrations), version)
+	assert.Equal(t, version, store.ExpectedSchemaVersion())
+}
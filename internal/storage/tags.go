@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"strings"
+
+	"michishirube/internal/models"
+)
+
+// NormalizeTagRename normalizes both sides of a tag rename the way tags are
+// stored, so a differently cased from still matches and to can't break the
+// stored form. It returns a ValidationError when either is empty, to contains
+// a comma or both end up the same.
+func NormalizeTagRename(from, to string) (string, string, error) {
+	from, to = models.NormalizeTag(from), models.NormalizeTag(to)
+	if from == "" || to == "" {
+		return "", "", &models.ValidationError{Field: "tag", Code: models.CodeRequired, Message: "from and to are required"}
+	}
+	if strings.Contains(to, ",") {
+		return "", "", &models.ValidationError{Field: "to", Code: models.CodeInvalidFormat, Message: "tags must not contain commas"}
+	}
+	if from == to {
+		return "", "", &models.ValidationError{Field: "to", Code: models.CodeNotAllowed, Message: "cannot rename a tag to itself"}
+	}
+	return from, to, nil
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"context"
+
+	"michishirube/internal/models"
+)
+
+type forceTransitionKey struct{}
+
+// WithForcedTransition returns a context under which UpdateTask skips the
+// status transition check, for corrections that need to bypass the workflow
+func WithForcedTransition(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceTransitionKey{}, true)
+}
+
+// CheckTransition is the status check backends run in UpdateTask. It returns
+// the error from models.ValidateTransition unless ctx forces the change.
+func CheckTransition(ctx context.Context, from, to models.Status) error {
+	if forced, _ := ctx.Value(forceTransitionKey{}).(bool); forced {
+		return nil
+	}
+	return models.ValidateTransition(from, to)
+}
@@ -263,6 +263,11 @@ function initializeKeyboardShortcuts() {
 // Report functionality
 
 // Helper function to format links with proper titles
+// isNoJira reports whether a Jira ID is the server's no-ticket placeholder
+function isNoJira(jiraId) {
+    return !jiraId || jiraId === (document.body.dataset.noJira || 'NO-JIRA');
+}
+
 function formatLinksForReport(links) {
     if (!links || links.length === 0) return '';
 
@@ -460,7 +465,7 @@ async function generateReport() {
         reportText += '### 🦀 Things I\'ve been working on\n';
         if (reportData.working_on && reportData.working_on.length > 0) {
             reportData.working_on.forEach(task => {
-                const jiraId = task.jira_id !== 'NO-JIRA' ? `[${task.jira_id}] ` : '';
+                const jiraId = !isNoJira(task.jira_id) ? `[${task.jira_id}] ` : '';
                 reportText += `- ${jiraId}${task.title}\n`;
 
                 // Add links if they exist, grouped by type
@@ -476,7 +481,7 @@ async function generateReport() {
         reportText += '\n### 🖖 Things I plan on working on next\n';
         if (reportData.next_up && reportData.next_up.length > 0) {
             reportData.next_up.forEach(task => {
-                const jiraId = task.jira_id !== 'NO-JIRA' ? `[${task.jira_id}] ` : '';
+                const jiraId = !isNoJira(task.jira_id) ? `[${task.jira_id}] ` : '';
                 reportText += `- ${jiraId}${task.title}\n`;
 
                 // Add links if they exist, grouped by type
@@ -492,7 +497,7 @@ async function generateReport() {
         reportText += '\n### 🤦 Things that are blocking me\n';
         if (reportData.blockers && reportData.blockers.length > 0) {
             reportData.blockers.forEach(task => {
-                const jiraId = task.jira_id !== 'NO-JIRA' ? `[${task.jira_id}] ` : '';
+                const jiraId = !isNoJira(task.jira_id) ? `[${task.jira_id}] ` : '';
                 reportText += `- ${jiraId}${task.title}\n`;
 
                 // Add blockers if they exist
@@ -534,7 +539,7 @@ async function generatePlainReport() {
         reportText += '- 🦀 Things I\'ve been working on\n';
         if (reportData.working_on && reportData.working_on.length > 0) {
             reportData.working_on.forEach(task => {
-                const jiraId = task.jira_id !== 'NO-JIRA' ? `[${task.jira_id}] ` : '';
+                const jiraId = !isNoJira(task.jira_id) ? `[${task.jira_id}] ` : '';
                 reportText += `  - ${jiraId}${task.title}\n`;
 
                 // Add links if they exist, grouped by type
@@ -550,7 +555,7 @@ async function generatePlainReport() {
         reportText += '\n- 🖖 Things I plan on working on next\n';
         if (reportData.next_up && reportData.next_up.length > 0) {
             reportData.next_up.forEach(task => {
-                const jiraId = task.jira_id !== 'NO-JIRA' ? `[${task.jira_id}] ` : '';
+                const jiraId = !isNoJira(task.jira_id) ? `[${task.jira_id}] ` : '';
                 reportText += `  - ${jiraId}${task.title}\n`;
 
                 // Add links if they exist, grouped by type
@@ -566,7 +571,7 @@ async function generatePlainReport() {
         reportText += '\n- 🤦 Things that are blocking me\n';
         if (reportData.blockers && reportData.blockers.length > 0) {
             reportData.blockers.forEach(task => {
-                const jiraId = task.jira_id !== 'NO-JIRA' ? `[${task.jira_id}] ` : '';
+                const jiraId = !isNoJira(task.jira_id) ? `[${task.jira_id}] ` : '';
                 reportText += `  - ${jiraId}${task.title}\n`;
 
                 // Add blockers if they exist
@@ -15,6 +15,9 @@ function addLinkRow(type = 'other') {
             <option value="slack_thread" ${type === 'slack_thread' ? 'selected' : ''}>💬 Slack Thread</option>
             <option value="jira_ticket" ${type === 'jira_ticket' ? 'selected' : ''}>📋 Jira Ticket</option>
             <option value="documentation" ${type === 'documentation' ? 'selected' : ''}>📚 Documentation</option>
+            <option value="commit" ${type === 'commit' ? 'selected' : ''}>🔖 Commit</option>
+            <option value="design_doc" ${type === 'design_doc' ? 'selected' : ''}>📐 Design Doc</option>
+            <option value="incident" ${type === 'incident' ? 'selected' : ''}>🚨 Incident</option>
             <option value="other" ${type === 'other' ? 'selected' : ''}>🌐 Other</option>
         </select>
         <input type="url" name="link_urls[]" placeholder="https://..." required>
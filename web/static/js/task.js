@@ -358,39 +358,6 @@ async function removeLink(linkId) {
 }
 
 // Comments management
-async function addComment(event, taskId) {
-    event.preventDefault();
-
-    const form = event.target;
-    const formData = new FormData(form);
-    const content = formData.get('content').trim();
-
-    if (!content) {
-        App.notify.warning('Please enter a comment');
-        return;
-    }
-
-    App.loading.show('Adding comment...');
-
-    try {
-        await App.api.post('/api/comments', {
-            task_id: taskId,
-            content: content
-        });
-
-        form.reset();
-
-        // Refresh page to show new comment
-        window.location.reload();
-
-    } catch (error) {
-        console.error('Failed to add comment:', error);
-        App.notify.error('Failed to add comment');
-    } finally {
-        App.loading.hide();
-    }
-}
-
 async function removeComment(commentId) {
     if (!confirm('Are you sure you want to remove this comment?')) {
         return;
@@ -435,8 +402,7 @@ async function removeComment(commentId) {
 
 // Task actions
 async function editTask(taskId) {
-    // For now, redirect to a simple form - could be enhanced with modal
-    window.location.href = `/edit/${taskId}`;
+    window.location.href = `/task/${taskId}/edit`;
 }
 
 async function deleteTask(taskId) {